@@ -0,0 +1,240 @@
+package gomponere
+
+import "fmt"
+
+// Builder puts a diagram together in code
+//
+// Every method returns a builder so calls can be chained, and the builders for areas, teams and components have the
+// methods of Builder too, so the next entity can be started straight after the last one. Keys that are used twice
+// are reported by Build, along with anything Validate finds.
+type Builder struct {
+	diagram Diagram
+	errors  []string
+}
+
+// AreaBuilder sets the fields of the area last added
+type AreaBuilder struct {
+	*Builder
+	key string
+}
+
+// TeamBuilder sets the fields of the team last added
+type TeamBuilder struct {
+	*Builder
+	key string
+}
+
+// ComponentBuilder sets the fields of the component last added
+type ComponentBuilder struct {
+	*Builder
+	key string
+}
+
+// NewBuilder returns a builder for an empty diagram
+func NewBuilder() *Builder {
+	return &Builder{
+		diagram: Diagram{
+			Areas:      map[string]Area{},
+			Components: map[string]Component{},
+			Levels:     map[string]Level{},
+			Teams:      map[string]Team{},
+			Types:      map[string]Type{},
+		},
+	}
+}
+
+// Build returns the diagram, or an error when it is not valid
+// the diagram shares its maps with the builder, so the builder should not be changed after it is built
+func (b *Builder) Build() (Diagram, error) {
+	problems := append(ValidationError{}, b.errors...)
+	if err, ok := Validate(b.diagram).(ValidationError); ok {
+		problems = append(problems, err...)
+	}
+	if len(problems) > 0 {
+		return Diagram{}, problems
+	}
+
+	return b.diagram, nil
+}
+
+// Level adds a level, which are drawn in the order they are added
+func (b *Builder) Level(key string, name string) *Builder {
+	if exists(b, "level", key, b.diagram.Levels) {
+		return b
+	}
+
+	b.diagram.Levels[key] = Level{Name: name, Order: len(b.diagram.Levels) + 1}
+	return b
+}
+
+// Type adds a component type drawn with the given graphviz shape, the default box when empty
+func (b *Builder) Type(key string, name string, shape string) *Builder {
+	if exists(b, "type", key, b.diagram.Types) {
+		return b
+	}
+
+	b.diagram.Types[key] = Type{Name: name, Shape: shape}
+	return b
+}
+
+// Style adds a style, which cascade in the order described by Diagram.ComponentStyles
+func (b *Builder) Style(style Style) *Builder {
+	b.diagram.Styles = append(b.diagram.Styles, style)
+	return b
+}
+
+// Area adds an area
+func (b *Builder) Area(key string, name string) *AreaBuilder {
+	if !exists(b, "area", key, b.diagram.Areas) {
+		b.diagram.Areas[key] = Area{Name: name}
+	}
+
+	return &AreaBuilder{b, key}
+}
+
+// Team adds a team
+func (b *Builder) Team(key string, name string) *TeamBuilder {
+	if !exists(b, "team", key, b.diagram.Teams) {
+		b.diagram.Teams[key] = Team{Name: name}
+	}
+
+	return &TeamBuilder{b, key}
+}
+
+// Component adds a component
+func (b *Builder) Component(key string, name string) *ComponentBuilder {
+	if !exists(b, "component", key, b.diagram.Components) {
+		b.diagram.Components[key] = Component{Name: name}
+	}
+
+	return &ComponentBuilder{b, key}
+}
+
+// Within puts the area inside another one
+func (a *AreaBuilder) Within(parentKey string) *AreaBuilder {
+	area := a.diagram.Areas[a.key]
+	area.ParentKey = parentKey
+	a.diagram.Areas[a.key] = area
+
+	return a
+}
+
+// Contact sets who to get in touch with about the team
+func (t *TeamBuilder) Contact(name string, email string) *TeamBuilder {
+	return t.update(func(team *Team) {
+		team.TeamContact = TeamContact{Name: name, Email: email}
+	})
+}
+
+// Lead sets the lead of the team
+func (t *TeamBuilder) Lead(name string, email string) *TeamBuilder {
+	return t.update(func(team *Team) {
+		team.LeadContact = TeamContact{Name: name, Email: email}
+	})
+}
+
+// Colors sets the colors the team's components are drawn with
+func (t *TeamBuilder) Colors(background string, foreground string) *TeamBuilder {
+	return t.update(func(team *Team) {
+		team.Display = Display{BackgroundColor: background, ForegroundColor: foreground}
+	})
+}
+
+func (t *TeamBuilder) update(change func(team *Team)) *TeamBuilder {
+	team := t.diagram.Teams[t.key]
+	change(&team)
+	t.diagram.Teams[t.key] = team
+
+	return t
+}
+
+// Describe sets the description of the component
+func (c *ComponentBuilder) Describe(description string) *ComponentBuilder {
+	return c.update(func(component *Component) {
+		component.Description = description
+	})
+}
+
+// Git sets the repository of the component
+func (c *ComponentBuilder) Git(url string) *ComponentBuilder {
+	return c.update(func(component *Component) {
+		component.Git = url
+	})
+}
+
+// Tagged adds tags to the component
+func (c *ComponentBuilder) Tagged(tags ...string) *ComponentBuilder {
+	return c.update(func(component *Component) {
+		component.Tags = append(component.Tags, tags...)
+	})
+}
+
+// InArea puts the component in an area
+func (c *ComponentBuilder) InArea(areaKey string) *ComponentBuilder {
+	return c.update(func(component *Component) {
+		component.AreaKey = areaKey
+	})
+}
+
+// AtLevel puts the component at a level
+func (c *ComponentBuilder) AtLevel(levelKey string) *ComponentBuilder {
+	return c.update(func(component *Component) {
+		component.LevelKey = levelKey
+	})
+}
+
+// OfType sets the type of the component
+func (c *ComponentBuilder) OfType(typeKey string) *ComponentBuilder {
+	return c.update(func(component *Component) {
+		component.TypeKey = typeKey
+	})
+}
+
+// OwnedBy sets the team that owns the component
+func (c *ComponentBuilder) OwnedBy(teamKey string) *ComponentBuilder {
+	return c.update(func(component *Component) {
+		component.TeamKey = teamKey
+	})
+}
+
+// DependsOn adds dependencies on other components, which may be added after this one
+func (c *ComponentBuilder) DependsOn(keys ...string) *ComponentBuilder {
+	return c.update(func(component *Component) {
+		component.DependencyKeys = append(component.DependencyKeys, keys...)
+	})
+}
+
+// Status sets the lifecycle status of the component, along with the date it was entered when not empty
+func (c *ComponentBuilder) Status(status string, date string) *ComponentBuilder {
+	return c.update(func(component *Component) {
+		component.Lifecycle.Status = status
+		if date == "" {
+			return
+		}
+
+		dates := map[string]string{}
+		for s, d := range component.Lifecycle.Dates {
+			dates[s] = d
+		}
+		dates[status] = date
+		component.Lifecycle.Dates = dates
+	})
+}
+
+func (c *ComponentBuilder) update(change func(component *Component)) *ComponentBuilder {
+	component := c.diagram.Components[c.key]
+	change(&component)
+	c.diagram.Components[c.key] = component
+
+	return c
+}
+
+// exists records an error when the key has already been used for the kind of entity
+func exists[V any](b *Builder, kind string, key string, entities map[string]V) bool {
+	if _, found := entities[key]; found {
+		b.errors = append(b.errors, fmt.Sprintf("%s '%s' is added more than once", kind, key))
+		return true
+	}
+
+	return false
+}
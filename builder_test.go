@@ -0,0 +1,78 @@
+package gomponere_test
+
+import (
+	"github.com/abramsimon/gomponere"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Builder", func() {
+	var (
+		b   *gomponere.Builder
+		d   gomponere.Diagram
+		err error
+	)
+
+	BeforeEach(func() {
+		b = gomponere.NewBuilder()
+		b.Level("service", "Services").
+			Level("data", "Data").
+			Type("database", "Database", "cylinder").
+			Area("prod", "Production").
+			Area("eu", "Europe").Within("prod").
+			Team("shop", "Shop").Contact("Shop", "shop@example.com").Lead("Lead", "lead@example.com").Colors("blue", "white").
+			Component("web", "Web").InArea("eu").AtLevel("service").OwnedBy("shop").DependsOn("api").
+			Component("api", "Api").Describe("the api").Git("https://github.com/acme/api").InArea("eu").AtLevel("service").OwnedBy("shop").DependsOn("db").Status(gomponere.Deprecated, "2020-01-02").
+			Component("db", "DB").InArea("prod").AtLevel("data").OfType("database").Tagged("pci").
+			Style(gomponere.Style{Select: gomponere.Selector{Tag: "pci"}, BorderColor: "red"})
+	})
+
+	JustBeforeEach(func() {
+		d, err = b.Build()
+	})
+
+	It("builds the diagram", func() {
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d.Levels).To(Equal(map[string]gomponere.Level{"service": {Name: "Services", Order: 1}, "data": {Name: "Data", Order: 2}}))
+		Expect(d.Types).To(Equal(map[string]gomponere.Type{"database": {Name: "Database", Shape: "cylinder"}}))
+		Expect(d.Areas).To(Equal(map[string]gomponere.Area{"prod": {Name: "Production"}, "eu": {Name: "Europe", ParentKey: "prod"}}))
+		Expect(d.Teams).To(Equal(map[string]gomponere.Team{"shop": {
+			Name:        "Shop",
+			TeamContact: gomponere.TeamContact{Name: "Shop", Email: "shop@example.com"},
+			LeadContact: gomponere.TeamContact{Name: "Lead", Email: "lead@example.com"},
+			Display:     gomponere.Display{BackgroundColor: "blue", ForegroundColor: "white"},
+		}}))
+		Expect(d.Components["api"]).To(Equal(gomponere.Component{
+			Name:           "Api",
+			Description:    "the api",
+			Git:            "https://github.com/acme/api",
+			LevelKey:       "service",
+			TeamKey:        "shop",
+			AreaKey:        "eu",
+			DependencyKeys: []string{"db"},
+			Lifecycle:      gomponere.Lifecycle{Status: gomponere.Deprecated, Dates: map[string]string{gomponere.Deprecated: "2020-01-02"}},
+		}))
+		Expect(d.ComponentKeys()).To(Equal([]string{"api", "db", "web"}))
+		Expect(d.ComponentStyles("db")).To(Equal([]gomponere.Style{{Select: gomponere.Selector{Tag: "pci"}, BorderColor: "red"}}))
+	})
+
+	Context("with a key used twice", func() {
+		BeforeEach(func() {
+			b.Component("web", "Web again")
+		})
+
+		It("fails", func() {
+			Expect(err).To(MatchError("invalid diagram: component 'web' is added more than once"))
+		})
+	})
+
+	Context("with a dependency on a component that is never added", func() {
+		BeforeEach(func() {
+			b.Component("ops", "Ops").InArea("prod").DependsOn("missing")
+		})
+
+		It("fails", func() {
+			Expect(err).To(MatchError("invalid diagram: component 'ops' depends on unknown component 'missing'"))
+		})
+	})
+})
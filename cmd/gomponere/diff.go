@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/abramsimon/gomponere/internal/diff"
+)
+
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	format := flags.String("format", "text", "output format: text, json or dot")
+	oldRev := flags.String("old-rev", "", "git revision to read the old directory from instead of the working copy")
+	newRev := flags.String("new-rev", "", "git revision to read the new directory from instead of the working copy")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: gomponere diff [flags] <old-dir> <new-dir>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return fmt.Errorf("diff requires an old and a new directory")
+	}
+
+	old, err := loadDiagram(flags.Arg(0), *oldRev)
+	if err != nil {
+		return err
+	}
+
+	new, err := loadDiagram(flags.Arg(1), *newRev)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "text":
+		return diff.WriteText(os.Stdout, diff.Compare(old, new))
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(diff.Compare(old, new))
+	case "dot":
+		dot, err := diff.MakeDot(old, new)
+		if err != nil {
+			return err
+		}
+		fmt.Print(dot)
+		return nil
+	default:
+		return fmt.Errorf("unknown format '%s'", *format)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/abramsimon/gomponere/internal/drift"
+)
+
+func runDrift(args []string) error {
+	flags := flag.NewFlagSet("drift", flag.ExitOnError)
+	dir := flags.String("i", "../test/input", "directory where the declared input files can be found")
+	rev := flags.String("rev", "", "git revision to read the declared input files from instead of the working copy")
+	actualDir := flags.String("actual", "", "directory where input files describing what is actually deployed can be found")
+	format := flags.String("format", "text", "output format: text, json or dot")
+	flags.Parse(args)
+
+	if *actualDir == "" {
+		flags.Usage()
+		return fmt.Errorf("drift requires a directory describing the actual architecture")
+	}
+
+	declared, err := loadDiagram(*dir, *rev)
+	if err != nil {
+		return err
+	}
+
+	actual, err := loadDiagram(*actualDir, "")
+	if err != nil {
+		return err
+	}
+
+	r := drift.Compare(declared, actual)
+
+	switch *format {
+	case "text":
+		if err := drift.WriteText(os.Stdout, r); err != nil {
+			return err
+		}
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	case "dot":
+		dot, err := drift.MakeDot(declared, actual)
+		if err != nil {
+			return err
+		}
+		fmt.Print(dot)
+	default:
+		return fmt.Errorf("unknown format '%s'", *format)
+	}
+
+	if !r.Empty() {
+		return fmt.Errorf("the actual architecture has drifted from the declared one")
+	}
+
+	return nil
+}
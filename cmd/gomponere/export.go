@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/abramsimon/gomponere/internal/backstage"
+	"github.com/spf13/afero"
+)
+
+// exporters maps each kind of target to the function that exports to it from the remaining arguments
+var exporters = map[string]func(args []string) error{
+	"backstage": runExportBackstage,
+}
+
+func runExport(args []string) error {
+	if len(args) == 0 {
+		kinds := make([]string, 0, len(exporters))
+		for k := range exporters {
+			kinds = append(kinds, k)
+		}
+		sort.Strings(kinds)
+		return fmt.Errorf("usage: gomponere export <%s> [flags]", strings.Join(kinds, "|"))
+	}
+
+	run, exists := exporters[args[0]]
+	if !exists {
+		return fmt.Errorf("unknown export target '%s'", args[0])
+	}
+
+	return run(args[1:])
+}
+
+func runExportBackstage(args []string) error {
+	flags := flag.NewFlagSet("export backstage", flag.ExitOnError)
+	dir := flags.String("i", "../test/input", "directory where input files can be found")
+	rev := flags.String("rev", "", "git revision to read the input files from instead of the working copy")
+	out := flags.String("o", "", "file to write the catalog to, printed when empty")
+	flags.Parse(args)
+
+	d, err := loadDiagram(*dir, *rev)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := backstage.Export(&b, d); err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(b.Bytes())
+		return err
+	}
+
+	return afero.WriteFile(afero.NewOsFs(), *out, b.Bytes(), 0644)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abramsimon/gomponere"
+)
+
+// listFlag is a flag that can be given more than once
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// splitPair splits a flag value formatted as name=value
+func splitPair(s string, what string, format string) (string, string, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", fmt.Errorf("%s '%s' is not formatted as %s", what, s, format)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// formatOptions reads the -option flags of a renderer, which override the defaults
+func formatOptions(defaults gomponere.FormatOptions, options []string) (gomponere.FormatOptions, error) {
+	opts := gomponere.FormatOptions{}
+	for name, value := range defaults {
+		opts[name] = value
+	}
+	for _, o := range options {
+		name, value, err := splitPair(o, "option", "name=value")
+		if err != nil {
+			return nil, err
+		}
+		opts[name] = value
+	}
+
+	return opts, nil
+}
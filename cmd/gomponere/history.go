@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/abramsimon/gomponere/internal/gitfs"
+	"github.com/abramsimon/gomponere/internal/history"
+	"github.com/spf13/afero"
+)
+
+func runHistory(args []string) error {
+	flags := flag.NewFlagSet("history", flag.ExitOnError)
+	dir := flags.String("i", "../test/input", "directory where input files can be found, it must be inside a git repository")
+	format := flags.String("format", "markdown", "output format: markdown or json")
+	out := flags.String("o", "", "directory to write history.md and a diagram per commit to, instead of printing the changelog")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: gomponere history [flags]")
+		fmt.Fprintln(flags.Output(), "the lifetimes table only tracks components, changes to dependencies and teams are listed per commit")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	commits, err := gitfs.Log(*dir)
+	if err != nil {
+		return err
+	}
+
+	t := history.Build(commits, history.GitLoader(*dir))
+
+	if *format == "json" {
+		return writeHistoryJSON(os.Stdout, t)
+	}
+	if *format != "markdown" {
+		return fmt.Errorf("unknown format '%s'", *format)
+	}
+
+	if *out == "" {
+		return history.WriteMarkdown(os.Stdout, t, nil)
+	}
+
+	fs := afero.NewOsFs()
+	pages, err := history.WritePages(fs, *out, t)
+	if err != nil {
+		return err
+	}
+
+	f, err := fs.Create(filepath.Join(*out, "history.md"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return history.WriteMarkdown(f, t, func(s history.Snapshot) string {
+		return pages[s.Commit.Hash]
+	})
+}
+
+func writeHistoryJSON(w io.Writer, t history.Timeline) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(t)
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/abramsimon/gomponere/internal/backstage"
+	"github.com/abramsimon/gomponere/internal/importer"
+	"github.com/abramsimon/gomponere/internal/input"
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/abramsimon/gomponere/internal/output"
+	"github.com/spf13/afero"
+)
+
+// importers maps each kind of source to the function that imports it from the remaining arguments
+var importers = map[string]func(args []string) error{
+	"backstage":  runImportBackstage,
+	"compose":    runImportCompose,
+	"gomod":      runImportGoModules,
+	"kubernetes": runImportKubernetes,
+	"terraform":  runImportTerraform,
+}
+
+func runImport(args []string) error {
+	if len(args) == 0 {
+		kinds := make([]string, 0, len(importers))
+		for k := range importers {
+			kinds = append(kinds, k)
+		}
+		sort.Strings(kinds)
+		return fmt.Errorf("usage: gomponere import <%s> [flags] <source>", strings.Join(kinds, "|"))
+	}
+
+	run, exists := importers[args[0]]
+	if !exists {
+		return fmt.Errorf("unknown import source '%s'", args[0])
+	}
+
+	return run(args[1:])
+}
+
+// importFlags are the flags shared by every importer
+type importFlags struct {
+	flags *flag.FlagSet
+	out   *string
+	team  *string
+	area  *string
+	merge *string
+}
+
+func newImportFlags(name string, usage string) importFlags {
+	flags := flag.NewFlagSet("import "+name, flag.ExitOnError)
+	f := importFlags{
+		flags: flags,
+		out:   flags.String("o", "", "directory without model files to write the imported yaml files to, printed as a single document when empty"),
+		team:  flags.String("team", importer.Unassigned, "key of the placeholder team given to imported components"),
+		area:  flags.String("area", "", "key of the placeholder area imported components are put in"),
+		merge: flags.String("merge", "", "directory of an existing model to merge the import into, keeping the fields already set there"),
+	}
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: gomponere import %s [flags] %s\n", name, usage)
+		flags.PrintDefaults()
+	}
+
+	return f
+}
+
+// writeImport writes the imported diagram as yaml files, or prints it when no output directory was given
+// when an existing model was given, the import is merged into it first
+func (f importFlags) writeImport(d model.Diagram) error {
+	if *f.merge != "" {
+		existing, err := input.Load(afero.NewOsFs(), *f.merge)
+		if err != nil {
+			return fmt.Errorf("unable to load '%s' to merge into: %s", *f.merge, err)
+		}
+		d = importer.Merge(existing, d)
+	}
+
+	if *f.out == "" {
+		b, err := output.Marshal(d)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+
+	return writeDiagram(*f.out, d)
+}
+
+func runImportBackstage(args []string) error {
+	f := newImportFlags("backstage", "<catalog dir or file>")
+	f.flags.Parse(args)
+
+	if f.flags.NArg() != 1 {
+		f.flags.Usage()
+		return fmt.Errorf("import backstage requires a catalog file or a directory of them")
+	}
+	path := f.flags.Arg(0)
+
+	d, err := backstage.Import(afero.NewOsFs(), path, importer.Options{TeamKey: *f.team, AreaKey: *f.area})
+	if err != nil {
+		return fmt.Errorf("unable to import '%s': %s", path, err)
+	}
+
+	return f.writeImport(d)
+}
+
+func runImportCompose(args []string) error {
+	f := newImportFlags("compose", "<docker-compose.yml>")
+	f.flags.Parse(args)
+
+	if f.flags.NArg() != 1 {
+		f.flags.Usage()
+		return fmt.Errorf("import compose requires a docker-compose file")
+	}
+	file := f.flags.Arg(0)
+
+	data, err := afero.ReadFile(afero.NewOsFs(), file)
+	if err != nil {
+		return err
+	}
+
+	// compose names the project after the directory the file is in, so the area is too
+	opts := importer.Options{TeamKey: *f.team, AreaKey: *f.area}
+	if opts.AreaKey == "" {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return err
+		}
+		opts.AreaKey = importer.Key(filepath.Base(filepath.Dir(abs)))
+	}
+
+	d, err := importer.Compose(data, opts)
+	if err != nil {
+		return fmt.Errorf("unable to import '%s': %s", file, err)
+	}
+
+	return f.writeImport(d)
+}
+
+func runImportGoModules(args []string) error {
+	f := newImportFlags("gomod", "<repositories dir>")
+	prefix := f.flags.String("prefix", "", "module path prefix of your own modules, like github.com/acme/")
+	f.flags.Parse(args)
+
+	if f.flags.NArg() != 1 {
+		f.flags.Usage()
+		return fmt.Errorf("import gomod requires a directory of repositories")
+	}
+	dir := f.flags.Arg(0)
+
+	d, err := importer.GoModules(afero.NewOsFs(), dir, *prefix, importer.Options{TeamKey: *f.team, AreaKey: *f.area})
+	if err != nil {
+		return fmt.Errorf("unable to import '%s': %s", dir, err)
+	}
+
+	return f.writeImport(d)
+}
+
+func runImportKubernetes(args []string) error {
+	f := newImportFlags("kubernetes", "<manifest dir>")
+	f.flags.Parse(args)
+
+	if f.flags.NArg() != 1 {
+		f.flags.Usage()
+		return fmt.Errorf("import kubernetes requires a directory of manifests")
+	}
+	dir := f.flags.Arg(0)
+
+	d, err := importer.Kubernetes(afero.NewOsFs(), dir, importer.Options{TeamKey: *f.team, AreaKey: *f.area})
+	if err != nil {
+		return fmt.Errorf("unable to import '%s': %s", dir, err)
+	}
+
+	return f.writeImport(d)
+}
+
+func runImportTerraform(args []string) error {
+	f := newImportFlags("terraform", "<terraform show -json output>")
+	f.flags.Parse(args)
+
+	if f.flags.NArg() != 1 {
+		f.flags.Usage()
+		return fmt.Errorf("import terraform requires the json output of terraform show")
+	}
+	file := f.flags.Arg(0)
+
+	data, err := afero.ReadFile(afero.NewOsFs(), file)
+	if err != nil {
+		return err
+	}
+
+	d, err := importer.Terraform(data, importer.Options{TeamKey: *f.team, AreaKey: *f.area})
+	if err != nil {
+		return fmt.Errorf("unable to import '%s': %s", file, err)
+	}
+
+	return f.writeImport(d)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/abramsimon/gomponere/internal/input"
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+var _ = Describe("import", func() {
+	var (
+		err  error
+		dir  string
+		arch string
+		out  string
+		args []string
+	)
+
+	load := func(path string) (model.Diagram, error) {
+		return input.Load(afero.NewOsFs(), path)
+	}
+
+	BeforeEach(func() {
+		if dir, err = ioutil.TempDir("", "import"); err != nil {
+			Fail(err.Error())
+		}
+
+		arch = filepath.Join(dir, "arch")
+		out = filepath.Join(dir, "out")
+		if err := os.MkdirAll(arch, os.ModePerm); err != nil {
+			Fail(err.Error())
+		}
+		if err := ioutil.WriteFile(filepath.Join(arch, "web.yaml"), []byte("components:\n  web:\n    name: Web\n    team: orders\n"), os.ModePerm); err != nil {
+			Fail(err.Error())
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, "docker-compose.yml"), []byte("services:\n  web:\n    build: .\n  db:\n    image: postgres\n"), os.ModePerm); err != nil {
+			Fail(err.Error())
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	JustBeforeEach(func() {
+		err = runImport(append(args, filepath.Join(dir, "docker-compose.yml")))
+	})
+
+	Context("merging into a new directory", func() {
+		BeforeEach(func() {
+			args = []string{"compose", "-merge", arch, "-o", out}
+		})
+
+		It("does not error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("writes a model that can be loaded", func() {
+			d, err := load(out)
+			Expect(err).To(BeNil())
+			Expect(d.Components).To(HaveKey("db"))
+			Expect(d.Components["web"].TeamKey).To(Equal("orders"))
+		})
+	})
+
+	Context("merging into the directory merged from", func() {
+		BeforeEach(func() {
+			args = []string{"compose", "-merge", arch, "-o", arch}
+		})
+
+		It("does error", func() {
+			Expect(err).ToNot(BeNil())
+		})
+		It("leaves the model as it was", func() {
+			d, err := load(arch)
+			Expect(err).To(BeNil())
+			Expect(d.Components).To(HaveLen(1))
+		})
+	})
+
+	Context("writing into a directory with model files", func() {
+		BeforeEach(func() {
+			args = []string{"compose", "-o", arch}
+		})
+
+		It("does error", func() {
+			Expect(err).ToNot(BeNil())
+		})
+		It("leaves the model as it was", func() {
+			_, err := load(arch)
+			Expect(err).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/abramsimon/gomponere/internal/config"
+	"github.com/abramsimon/gomponere/internal/lint"
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/abramsimon/gomponere/internal/plugin"
+)
+
+func runLint(args []string) error {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	dir := flags.String("i", "../test/input", "directory where input files can be found")
+	rev := flags.String("rev", "", "git revision to read the input files from instead of the working copy")
+	format := flags.String("format", "text", "output format: text or json")
+	configPath := flags.String("config", "", "config file declaring custom rules, "+config.Name+" in the input directory by default")
+	severities := listFlag{}
+	flags.Var(&severities, "severity", "change the severity of a rule, as rule=error|warning|info|off, can be repeated")
+	required := listFlag{}
+	flags.Var(&required, "require-ownership", "fail when a component in the area has no team, can be repeated")
+	plugins := listFlag{}
+	flags.Var(&plugins, "plugin", "also run the lint command of the plugin gomponere-<name>, can be repeated")
+	flags.Parse(args)
+
+	d, err := loadDiagram(*dir, *rev)
+	if err != nil {
+		return err
+	}
+
+	c, err := loadConfig(*dir, *rev, *configPath)
+	if err != nil {
+		return err
+	}
+
+	rules, err := lintRules(d, c.Lint, severities, required, plugins)
+	if err != nil {
+		return err
+	}
+
+	diagnostics := lint.Run(d, rules)
+
+	switch *format {
+	case "text":
+		if err := lint.WriteText(os.Stdout, diagnostics); err != nil {
+			return err
+		}
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(diagnostics); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown format '%s'", *format)
+	}
+
+	if lint.HasErrors(diagnostics) {
+		return fmt.Errorf("lint found errors")
+	}
+
+	return nil
+}
+
+// lintRules returns the built in and custom rules with the severities changed, along with the ownership rule for the
+// required areas and the rules of each plugin, which is run on the diagram straight away unless it is turned off
+func lintRules(d model.Diagram, custom lint.Config, severities []string, required []string, plugins []string) ([]lint.Rule, error) {
+	changes := map[string]lint.Severity{}
+	for _, s := range severities {
+		rule, value, err := splitPair(s, "severity", "rule=severity")
+		if err != nil {
+			return nil, err
+		}
+
+		severity, err := lint.ParseSeverity(value)
+		if err != nil {
+			return nil, err
+		}
+		changes[rule] = severity
+	}
+
+	compiled, err := custom.Compile()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := append(lint.Rules[:len(lint.Rules):len(lint.Rules)], compiled...)
+	for _, a := range required {
+		if _, exists := d.Areas[a]; !exists {
+			return nil, fmt.Errorf("unknown area '%s'", a)
+		}
+	}
+	if len(required) > 0 {
+		rules = append(rules[:len(rules):len(rules)], lint.RequireOwnership(required...))
+	}
+
+	for _, name := range plugins {
+		p, err := plugin.Lookup(os.Getenv("PATH"), name)
+		if err != nil {
+			return nil, err
+		}
+
+		// a plugin that is turned off is not run at all
+		if changes[p.Name] == lint.Off {
+			delete(changes, p.Name)
+			continue
+		}
+
+		r, err := p.Rules(d)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules[:len(rules):len(rules)], r...)
+	}
+
+	return lint.WithSeverities(rules, changes)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/abramsimon/gomponere"
+	"github.com/abramsimon/gomponere/internal/config"
+	"github.com/abramsimon/gomponere/internal/gitfs"
+	"github.com/abramsimon/gomponere/internal/input"
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/abramsimon/gomponere/internal/output"
+	"github.com/spf13/afero"
+)
+
+// inputFs returns the filesystem to read dir from and where dir is on it, which is dir as it was at rev when rev is
+// not empty
+func inputFs(dir string, rev string) (afero.Fs, string, error) {
+	if rev == "" {
+		return afero.NewOsFs(), dir, nil
+	}
+
+	fs, err := gitfs.New(dir, rev)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return fs, fs.Prefix(), nil
+}
+
+// loadDiagram loads the diagram from dir, or from dir as it was at rev when rev is not empty
+func loadDiagram(dir string, rev string) (model.Diagram, error) {
+	fs, root, err := inputFs(dir, rev)
+	if err != nil {
+		return model.Diagram{}, err
+	}
+
+	return input.Load(fs, root)
+}
+
+// writeDiagram writes the diagram as yaml files into dir, which must not hold any model files yet since they would be
+// loaded together with the written ones, setting every field twice
+func writeDiagram(dir string, d model.Diagram) error {
+	fs := afero.NewOsFs()
+
+	exists, err := afero.DirExists(fs, dir)
+	if err != nil {
+		return err
+	}
+	if exists {
+		files, err := input.NewReader(fs).FindFiles(dir)
+		if err != nil {
+			return err
+		}
+		if len(files) > 0 {
+			return fmt.Errorf("'%s' already holds model files, write to an empty directory instead", dir)
+		}
+	}
+
+	return output.NewWriter(fs).WriteAll(dir, d)
+}
+
+// loadThemes registers the theme files listed in the config file in dir, as it was at rev when rev is not empty, and
+// returns the format options choosing the theme the config file sets
+func loadThemes(dir string, rev string) (gomponere.FormatOptions, error) {
+	c, err := loadConfig(dir, rev, "")
+	if err != nil {
+		return nil, err
+	}
+
+	fs, root, err := inputFs(dir, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range c.Themes {
+		t, err := gomponere.LoadTheme(fs, filepath.Join(root, path))
+		if err != nil {
+			return nil, err
+		}
+		if err := gomponere.RegisterTheme(t); err != nil {
+			return nil, err
+		}
+	}
+
+	opts := gomponere.FormatOptions{}
+	if c.Theme != "" {
+		opts[gomponere.ThemeOption] = c.Theme
+	}
+
+	return opts, nil
+}
+
+// loadTheme returns the theme with the name, or the theme the config file in dir sets when the name is empty
+func loadTheme(dir string, rev string, name string) (gomponere.Theme, error) {
+	opts, err := loadThemes(dir, rev)
+	if err != nil {
+		return gomponere.Theme{}, err
+	}
+	if name == "" {
+		name = opts[gomponere.ThemeOption]
+	}
+
+	return gomponere.LookupTheme(name)
+}
+
+// loadConfig reads the config file at path, or the one in dir as it was at rev when path is empty
+func loadConfig(dir string, rev string, path string) (config.Config, error) {
+	if path != "" {
+		return config.Load(afero.NewOsFs(), path)
+	}
+
+	fs, root, err := inputFs(dir, rev)
+	if err != nil {
+		return config.Config{}, err
+	}
+
+	return config.Load(fs, filepath.Join(root, config.Name))
+}
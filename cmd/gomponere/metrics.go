@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abramsimon/gomponere"
+	"github.com/abramsimon/gomponere/internal/metrics"
+)
+
+func runMetrics(args []string) error {
+	flags := flag.NewFlagSet("metrics", flag.ExitOnError)
+	dir := flags.String("i", "../test/input", "directory where input files can be found")
+	rev := flags.String("rev", "", "git revision to read the input files from instead of the working copy")
+	format := flags.String("format", "table", "output format: table, csv, json or dot for a heat map")
+	by := flags.String("by", metrics.ByComponent, "rows of the table or csv: component, area or team")
+	metric := flags.String("metric", metrics.Instability, "metric the heat map is colored by: "+strings.Join(metrics.Names, ", "))
+	themeName := flags.String("theme", "", "theme of the heat map: "+strings.Join(gomponere.Themes(), ", ")+", or one from the config file")
+	flags.Parse(args)
+
+	d, err := loadDiagram(*dir, *rev)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "table":
+		return metrics.WriteTable(os.Stdout, metrics.Compute(d), *by)
+	case "csv":
+		return metrics.WriteCSV(os.Stdout, metrics.Compute(d), *by)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(metrics.Compute(d))
+	case "dot":
+		t, err := loadTheme(*dir, *rev, *themeName)
+		if err != nil {
+			return err
+		}
+		dot, err := gomponere.RenderHeatMapWithOptions(d, *metric, gomponere.RenderOptions{Theme: t})
+		if err != nil {
+			return err
+		}
+		fmt.Print(dot)
+		return nil
+	default:
+		return fmt.Errorf("unknown format '%s'", *format)
+	}
+}
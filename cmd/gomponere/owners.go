@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/abramsimon/gomponere/internal/owners"
+	"github.com/spf13/afero"
+)
+
+func runOwners(args []string) error {
+	flags := flag.NewFlagSet("owners", flag.ExitOnError)
+	dir := flags.String("i", "../test/input", "directory where input files can be found")
+	repos := flags.String("repos", ".", "directory containing local clones of the components' git repositories")
+	mappingFile := flags.String("mapping", "", "yaml file mapping CODEOWNERS handles to team keys")
+	format := flags.String("format", "text", "output format: text or json")
+	out := flags.String("o", "", "directory without model files to write the model to with the suggested teams filled in")
+	flags.Parse(args)
+
+	fs := afero.NewOsFs()
+
+	d, err := loadDiagram(*dir, "")
+	if err != nil {
+		return err
+	}
+
+	mapping := owners.Mapping{}
+	if *mappingFile != "" {
+		if mapping, err = owners.LoadMapping(fs, *mappingFile); err != nil {
+			return err
+		}
+	}
+
+	findings := owners.Check(fs, d, *repos, mapping)
+
+	switch *format {
+	case "text":
+		if err := owners.WriteText(os.Stdout, findings); err != nil {
+			return err
+		}
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown format '%s'", *format)
+	}
+
+	if *out != "" {
+		return writeDiagram(*out, owners.Apply(d, findings))
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/abramsimon/gomponere/internal/input"
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+var _ = Describe("owners", func() {
+	var (
+		err  error
+		dir  string
+		arch string
+		out  string
+		args []string
+	)
+
+	load := func(path string) (model.Diagram, error) {
+		return input.Load(afero.NewOsFs(), path)
+	}
+
+	write := func(path string, content string) {
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			Fail(err.Error())
+		}
+		if err := ioutil.WriteFile(path, []byte(content), os.ModePerm); err != nil {
+			Fail(err.Error())
+		}
+	}
+
+	BeforeEach(func() {
+		if dir, err = ioutil.TempDir("", "owners"); err != nil {
+			Fail(err.Error())
+		}
+
+		arch = filepath.Join(dir, "arch")
+		out = filepath.Join(dir, "out")
+		write(filepath.Join(arch, "web.yaml"), "components:\n  web:\n    name: Web\n    git: git@github.com:acme/web.git\n")
+		write(filepath.Join(arch, "orders.yaml"), "teams:\n  orders:\n    name: Orders\n")
+		write(filepath.Join(dir, "repos", "web", "CODEOWNERS"), "* @acme/orders\n")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	JustBeforeEach(func() {
+		err = runOwners(append([]string{"-i", arch, "-repos", filepath.Join(dir, "repos"), "-format", "json"}, args...))
+	})
+
+	Context("writing into a new directory", func() {
+		BeforeEach(func() {
+			args = []string{"-o", out}
+		})
+
+		It("does not error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("writes a model that can be loaded with the suggested teams", func() {
+			d, err := load(out)
+			Expect(err).To(BeNil())
+			Expect(d.Components["web"].TeamKey).To(Equal("orders"))
+		})
+	})
+
+	Context("writing into the input directory", func() {
+		BeforeEach(func() {
+			args = []string{"-o", arch}
+		})
+
+		It("does error", func() {
+			Expect(err).ToNot(BeNil())
+		})
+		It("leaves the model as it was", func() {
+			d, err := load(arch)
+			Expect(err).To(BeNil())
+			Expect(d.Components["web"].TeamKey).To(Equal(""))
+		})
+	})
+})
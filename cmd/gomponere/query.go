@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/abramsimon/gomponere"
+	"github.com/abramsimon/gomponere/internal/query"
+)
+
+func runQuery(args []string) error {
+	flags := flag.NewFlagSet("query", flag.ExitOnError)
+	dir := flags.String("i", "../test/input", "directory where input files can be found")
+	rev := flags.String("rev", "", "git revision to read the input files from instead of the working copy")
+	format := flags.String("format", "table", "output format: table, json, or a format to render the subgraph found in: "+strings.Join(gomponere.Formats(), ", "))
+	options := listFlag{}
+	flags.Var(&options, "option", "an option for the render format, as name=value, can be repeated")
+	flags.Parse(args)
+
+	q, err := query.Parse(strings.Join(flags.Args(), " "))
+	if err != nil {
+		return err
+	}
+
+	d, err := loadDiagram(*dir, *rev)
+	if err != nil {
+		return err
+	}
+
+	r := q.Run(d)
+
+	switch *format {
+	case "table":
+		return query.WriteTable(os.Stdout, r)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+
+	defaults, err := loadThemes(*dir, *rev)
+	if err != nil {
+		return err
+	}
+
+	opts, err := formatOptions(defaults, options)
+	if err != nil {
+		return err
+	}
+
+	b, _, err := gomponere.RenderFormat(r.Subset(d), *format, opts)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(b)
+	return err
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/abramsimon/gomponere"
+)
+
+func runRender(args []string) error {
+	flags := flag.NewFlagSet("render", flag.ExitOnError)
+	dir := flags.String("i", "../test/input", "directory where input files can be found")
+	rev := flags.String("rev", "", "git revision to read the input files from instead of the working copy")
+	format := flags.String("format", "dot", "output format: "+strings.Join(gomponere.Formats(), ", "))
+	options := listFlag{}
+	flags.Var(&options, "option", "an option for the format, as name=value, can be repeated")
+	flags.Parse(args)
+
+	defaults, err := loadThemes(*dir, *rev)
+	if err != nil {
+		return err
+	}
+
+	opts, err := formatOptions(defaults, options)
+	if err != nil {
+		return err
+	}
+
+	renderer, err := gomponere.Lookup(*format)
+	if err != nil {
+		return err
+	}
+
+	d, err := loadDiagram(*dir, *rev)
+	if err != nil {
+		return err
+	}
+
+	b, _, err := renderer.Render(d, opts)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(b)
+	return err
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/abramsimon/gomponere/internal/server"
+	"github.com/spf13/afero"
+)
+
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	dir := flags.String("i", "../test/input", "directory where input files can be found")
+	addr := flags.String("addr", ":8080", "address to listen on")
+	interval := flags.Duration("reload-interval", 2*time.Second, "how often to check the input files for changes, 0 disables reloading")
+	flags.Parse(args)
+
+	s, err := server.NewServer(afero.NewOsFs(), *dir)
+	if err != nil {
+		return err
+	}
+
+	opts, err := loadThemes(*dir, "")
+	if err != nil {
+		return err
+	}
+	s.SetFormatOptions(opts)
+
+	if *interval > 0 {
+		go s.Watch(context.Background(), *interval, func(err error) {
+			log.Printf("unable to reload '%s': %s", *dir, err)
+		})
+	}
+
+	log.Printf("serving '%s' on %s", *dir, *addr)
+
+	return http.ListenAndServe(*addr, s.Handler())
+}
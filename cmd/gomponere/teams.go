@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abramsimon/gomponere"
+	"github.com/abramsimon/gomponere/internal/teammap"
+)
+
+func runTeams(args []string) error {
+	flags := flag.NewFlagSet("teams", flag.ExitOnError)
+	dir := flags.String("i", "../test/input", "directory where input files can be found")
+	rev := flags.String("rev", "", "git revision to read the input files from instead of the working copy")
+	format := flags.String("format", "dot", "output format: dot, text or json")
+	themeName := flags.String("theme", "", "theme of the dot output: "+strings.Join(gomponere.Themes(), ", ")+", or one from the config file")
+	flags.Parse(args)
+
+	d, err := loadDiagram(*dir, *rev)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "dot":
+		t, err := loadTheme(*dir, *rev, *themeName)
+		if err != nil {
+			return err
+		}
+		dot, err := gomponere.RenderTeamsWithOptions(d, gomponere.RenderOptions{Theme: t})
+		if err != nil {
+			return err
+		}
+		fmt.Print(dot)
+		return nil
+	case "text":
+		return teammap.WriteText(os.Stdout, d, teammap.Interactions(d))
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(teammap.Interactions(d))
+	default:
+		return fmt.Errorf("unknown format '%s'", *format)
+	}
+}
@@ -0,0 +1,70 @@
+// Package gomponere loads, validates, builds and renders architectural diagrams
+//
+// A diagram is read from yaml files with Load, or put together in code with a Builder, and drawn as graphviz dot
+// with Render:
+//
+//	d, err := gomponere.NewBuilder().
+//		Area("prod", "Production").
+//		Team("shop", "Shop").Contact("Shop", "shop@example.com").
+//		Component("web", "Web").InArea("prod").OwnedBy("shop").DependsOn("api").
+//		Component("api", "Api").InArea("prod").OwnedBy("shop").
+//		Build()
+//	if err != nil {
+//		return err
+//	}
+//	dot, err := gomponere.Render(d)
+package gomponere
+
+import (
+	"github.com/abramsimon/gomponere/internal/input"
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/abramsimon/gomponere/internal/output"
+	"github.com/spf13/afero"
+)
+
+// the types a diagram is made of
+type (
+	Diagram         = model.Diagram
+	Area            = model.Area
+	Component       = model.Component
+	Level           = model.Level
+	Team            = model.Team
+	TeamContact     = model.TeamContact
+	Display         = model.Display
+	Type            = model.Type
+	Lifecycle       = model.Lifecycle
+	Link            = model.Link
+	Style           = model.Style
+	Selector        = model.Selector
+	EdgeStyle       = model.EdgeStyle
+	ValidationError = model.ValidationError
+)
+
+// the lifecycle statuses a component moves through, in order
+const (
+	Planned        = model.Planned
+	InDevelopment  = model.InDevelopment
+	Active         = model.Active
+	Deprecated     = model.Deprecated
+	Decommissioned = model.Decommissioned
+)
+
+// Load reads all of the yaml files under root into a diagram
+func Load(fs afero.Fs, root string) (Diagram, error) {
+	return input.Load(fs, root)
+}
+
+// Validate checks that every key the diagram refers to exists, returning a ValidationError listing what does not
+func Validate(d Diagram) error {
+	return d.Validate()
+}
+
+// Write writes the diagram into root as areas.yaml, teams.yaml, meta.yaml and components.yaml
+func Write(fs afero.Fs, root string, d Diagram) error {
+	return output.NewWriter(fs).WriteAll(root, d)
+}
+
+// Marshal returns the diagram as a single yaml document, which Load reads back
+func Marshal(d Diagram) ([]byte, error) {
+	return output.Marshal(d)
+}
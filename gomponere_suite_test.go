@@ -0,0 +1,13 @@
+package gomponere_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestGomponere(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Gomponere Suite")
+}
@@ -0,0 +1,63 @@
+package gomponere_test
+
+import (
+	"github.com/abramsimon/gomponere"
+	"github.com/spf13/afero"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Gomponere", func() {
+	var (
+		d gomponere.Diagram
+	)
+
+	BeforeEach(func() {
+		var err error
+		d, err = gomponere.NewBuilder().
+			Area("prod", "Production").
+			Team("shop", "Shop").
+			Component("web", "Web").InArea("prod").OwnedBy("shop").DependsOn("api").
+			Component("api", "Api").InArea("prod").OwnedBy("shop").
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("Write and Load", func() {
+		It("round trips the diagram", func() {
+			fs := afero.NewMemMapFs()
+			Expect(gomponere.Write(fs, "arch", d)).To(Succeed())
+
+			loaded, err := gomponere.Load(fs, "arch")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loaded.Components).To(Equal(d.Components))
+			Expect(loaded.Areas).To(Equal(d.Areas))
+			Expect(gomponere.Validate(loaded)).To(Succeed())
+		})
+	})
+
+	Describe("Render", func() {
+		It("draws the components", func() {
+			dot, err := gomponere.Render(d)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dot).To(ContainSubstring(`label="Web"`))
+			Expect(dot).To(ContainSubstring(`label="Api"`))
+		})
+	})
+
+	Describe("RenderTeams", func() {
+		It("draws the teams", func() {
+			dot, err := gomponere.RenderTeams(d)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dot).To(ContainSubstring("Shop"))
+		})
+	})
+
+	Describe("RenderHeatMap", func() {
+		It("fails for unknown metrics", func() {
+			_, err := gomponere.RenderHeatMap(d, "size")
+			Expect(err).To(MatchError("unknown metric 'size'"))
+		})
+	})
+})
@@ -0,0 +1,81 @@
+package backstage
+
+import (
+	"strings"
+)
+
+// the backstage entity kinds that are converted
+const (
+	Domain    = "Domain"
+	System    = "System"
+	Group     = "Group"
+	Component = "Component"
+	Resource  = "Resource"
+	API       = "API"
+)
+
+// APIVersion is the version of the entities written by Export
+const APIVersion = "backstage.io/v1alpha1"
+
+// annotations used to keep what backstage has no field for
+const (
+	// SourceLocationAnnotation is the standard backstage annotation holding where the source of an entity is
+	SourceLocationAnnotation = "backstage.io/source-location"
+
+	// LifecycleAnnotation holds the exact lifecycle status, as backstage only knows experimental, production and deprecated
+	LifecycleAnnotation = "gomponere/lifecycle"
+)
+
+// entity is a backstage catalog entity, with the fields of every supported kind
+type entity struct {
+	APIVersion string   `yaml:"apiVersion"`
+	Kind       string   `yaml:"kind"`
+	Metadata   metadata `yaml:"metadata"`
+	Spec       spec     `yaml:"spec"`
+}
+
+type metadata struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace,omitempty"`
+	Title       string            `yaml:"title,omitempty"`
+	Description string            `yaml:"description,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+	Tags        []string          `yaml:"tags,omitempty"`
+	Links       []link            `yaml:"links,omitempty"`
+}
+
+type link struct {
+	URL   string `yaml:"url"`
+	Title string `yaml:"title,omitempty"`
+}
+
+type spec struct {
+	Type         string   `yaml:"type"`
+	Lifecycle    string   `yaml:"lifecycle"`
+	Owner        string   `yaml:"owner"`
+	System       string   `yaml:"system"`
+	Domain       string   `yaml:"domain"`
+	SubdomainOf  string   `yaml:"subdomainOf"`
+	DependsOn    []string `yaml:"dependsOn"`
+	ProvidesAPIs []string `yaml:"providesApis"`
+	ConsumesAPIs []string `yaml:"consumesApis"`
+	Profile      profile  `yaml:"profile"`
+}
+
+type profile struct {
+	DisplayName string `yaml:"displayName,omitempty"`
+	Email       string `yaml:"email,omitempty"`
+}
+
+// refName returns the name from an entity reference like "component:default/name"
+// the namespace is dropped, as the model has no namespaces
+func refName(ref string) string {
+	if i := strings.Index(ref, ":"); i >= 0 {
+		ref = ref[i+1:]
+	}
+	if i := strings.LastIndex(ref, "/"); i >= 0 {
+		ref = ref[i+1:]
+	}
+
+	return ref
+}
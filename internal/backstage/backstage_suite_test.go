@@ -0,0 +1,13 @@
+package backstage_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestBackstage(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Backstage Suite")
+}
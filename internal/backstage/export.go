@@ -0,0 +1,231 @@
+package backstage
+
+import (
+	"io"
+	"sort"
+
+	"github.com/abramsimon/gomponere/internal/importer"
+	"github.com/abramsimon/gomponere/internal/model"
+	"gopkg.in/yaml.v2"
+)
+
+// resourceTypes are the component types exported as Resources rather than Components
+var resourceTypes = map[string]bool{
+	importer.Database: true,
+	importer.Cache:    true,
+	importer.Queue:    true,
+	importer.Search:   true,
+	importer.Storage:  true,
+}
+
+// document is an entity as it is written, with the spec of its kind
+type document struct {
+	APIVersion string      `yaml:"apiVersion"`
+	Kind       string      `yaml:"kind"`
+	Metadata   metadata    `yaml:"metadata"`
+	Spec       interface{} `yaml:"spec"`
+}
+
+type domainSpec struct {
+	Owner       string `yaml:"owner"`
+	SubdomainOf string `yaml:"subdomainOf,omitempty"`
+}
+
+type systemSpec struct {
+	Owner  string `yaml:"owner"`
+	Domain string `yaml:"domain,omitempty"`
+}
+
+type groupSpec struct {
+	Type     string   `yaml:"type"`
+	Profile  profile  `yaml:"profile,omitempty"`
+	Children []string `yaml:"children"`
+}
+
+type componentSpec struct {
+	Type      string   `yaml:"type"`
+	Lifecycle string   `yaml:"lifecycle,omitempty"`
+	Owner     string   `yaml:"owner"`
+	System    string   `yaml:"system,omitempty"`
+	DependsOn []string `yaml:"dependsOn,omitempty"`
+}
+
+// Export writes the diagram as backstage catalog entities, one yaml document per entity
+//
+// Areas that components are in become Systems, and the areas above them Domains. A System can only be in a Domain,
+// so systems within systems are put in the closest domain above them. Domains and Systems are owned by the team that
+// owns the most components in them. Teams become Groups. Components become Components, or Resources for databases,
+// caches, queues, search indexes and storage. Lifecycle statuses backstage does not know are kept in an annotation
+// so they survive being imported again.
+func Export(w io.Writer, d model.Diagram) error {
+	documents := []document{}
+
+	// areas that components are in directly are systems
+	systems := map[string]bool{}
+	for _, c := range d.Components {
+		if _, exists := d.Areas[c.AreaKey]; exists {
+			systems[c.AreaKey] = true
+		}
+	}
+
+	// domain returns the closest area above the given one that is not a system
+	domain := func(key string) string {
+		for _, k := range d.AreaAncestors(key)[1:] {
+			if !systems[k] {
+				return k
+			}
+		}
+		return ""
+	}
+
+	teams := map[string]model.Team{}
+	for k, t := range d.Teams {
+		teams[k] = t
+	}
+
+	for _, k := range sortedKeys(d.Areas) {
+		a := d.Areas[k]
+		owner := areaOwner(d, k)
+		if _, exists := teams[owner]; !exists {
+			teams[owner] = model.Team{Name: importer.Name(owner)}
+		}
+
+		doc := document{
+			APIVersion: APIVersion,
+			Kind:       Domain,
+			Metadata:   metadata{Name: k, Title: a.Name},
+			Spec:       domainSpec{Owner: "group:" + owner, SubdomainOf: domain(k)},
+		}
+		if systems[k] {
+			doc.Kind = System
+			doc.Spec = systemSpec{Owner: "group:" + owner, Domain: domain(k)}
+		}
+		documents = append(documents, doc)
+	}
+
+	components := []document{}
+	for _, k := range d.ComponentKeys() {
+		c := d.Components[k]
+
+		owner := c.TeamKey
+		if owner == "" {
+			owner = importer.Unassigned
+		}
+		if _, exists := teams[owner]; !exists {
+			teams[owner] = model.Team{Name: importer.Name(owner)}
+		}
+
+		s := componentSpec{
+			Type:  c.TypeKey,
+			Owner: "group:" + owner,
+		}
+		if s.Type == "" {
+			s.Type = importer.Service
+		}
+		if _, exists := d.Areas[c.AreaKey]; exists {
+			s.System = c.AreaKey
+		}
+		for _, dk := range d.Dependencies(k) {
+			s.DependsOn = append(s.DependsOn, kind(d.Components[dk])+":"+dk)
+		}
+
+		m := metadata{Name: k, Title: c.Name, Description: c.Description, Annotations: map[string]string{}, Tags: c.Tags}
+		if c.Git != "" {
+			m.Annotations[SourceLocationAnnotation] = "url:" + c.Git
+		}
+		for _, l := range c.Links {
+			m.Links = append(m.Links, link{URL: l.URL, Title: l.Name})
+		}
+
+		if kind(c) == "component" {
+			switch c.Lifecycle.Current() {
+			case model.Planned, model.InDevelopment:
+				s.Lifecycle = "experimental"
+			case model.Deprecated, model.Decommissioned:
+				s.Lifecycle = "deprecated"
+			default:
+				s.Lifecycle = "production"
+			}
+		}
+		if status := c.Lifecycle.Current(); status != model.Active && status != lifecycles[s.Lifecycle] {
+			m.Annotations[LifecycleAnnotation] = status
+		}
+
+		doc := document{APIVersion: APIVersion, Kind: Component, Metadata: m, Spec: s}
+		if kind(c) == "resource" {
+			doc.Kind = Resource
+		}
+		components = append(components, doc)
+	}
+
+	for _, k := range sortedKeys(teams) {
+		t := teams[k]
+		documents = append(documents, document{
+			APIVersion: APIVersion,
+			Kind:       Group,
+			Metadata:   metadata{Name: k, Title: t.Name},
+			Spec: groupSpec{
+				Type:     "team",
+				Profile:  profile{DisplayName: t.Name, Email: t.TeamContact.Email},
+				Children: []string{},
+			},
+		})
+	}
+	documents = append(documents, components...)
+
+	for i, doc := range documents {
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+
+		b, err := yaml.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// kind returns the kind of entity the component is exported as, as used in entity references
+func kind(c model.Component) string {
+	if resourceTypes[c.TypeKey] {
+		return "resource"
+	}
+
+	return "component"
+}
+
+// areaOwner returns the team owning the most components in the area or its children
+func areaOwner(d model.Diagram, key string) string {
+	counts := map[string]int{}
+	for _, c := range d.Components {
+		if c.TeamKey != "" && d.InArea(c.AreaKey, key) {
+			counts[c.TeamKey]++
+		}
+	}
+
+	owner := importer.Unassigned
+	for _, k := range sortedKeys(counts) {
+		if counts[k] > counts[owner] {
+			owner = k
+		}
+	}
+
+	return owner
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
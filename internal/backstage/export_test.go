@@ -0,0 +1,114 @@
+package backstage_test
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/abramsimon/gomponere/internal/backstage"
+	"github.com/abramsimon/gomponere/internal/importer"
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+var _ = Describe("Export", func() {
+	var (
+		err error
+		d   model.Diagram
+		out string
+	)
+
+	BeforeEach(func() {
+		d = model.Diagram{
+			Areas: map[string]model.Area{
+				"commerce": {Name: "Commerce"},
+				"shop":     {Name: "Online Shop", ParentKey: "commerce"},
+				"checkout": {Name: "Checkout", ParentKey: "shop"},
+			},
+			Components: map[string]model.Component{
+				"web": {
+					Name:           "Web Shop",
+					Git:            "https://github.com/example/web",
+					TypeKey:        "service",
+					TeamKey:        "orders",
+					AreaKey:        "shop",
+					DependencyKeys: []string{"orders-db", "missing"},
+				},
+				"orders-db": {Name: "Orders Db", TypeKey: importer.Database, TeamKey: "orders", AreaKey: "checkout"},
+				"legacy": {
+					Name:      "Legacy",
+					AreaKey:   "checkout",
+					Lifecycle: model.Lifecycle{Status: model.Decommissioned},
+					Tags:      []string{"mainframe"},
+					Links:     []model.Link{{Name: "Runbook", URL: "https://wiki.example.com/legacy"}},
+				},
+			},
+			Teams: map[string]model.Team{
+				"orders": {Name: "Order Squad", TeamContact: model.TeamContact{Email: "orders@example.com"}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		var b bytes.Buffer
+		err = backstage.Export(&b, d)
+		out = b.String()
+	})
+
+	It("does not error", func() {
+		Expect(err).To(BeNil())
+	})
+	It("writes areas without components as domains", func() {
+		Expect(out).To(ContainSubstring("kind: Domain\nmetadata:\n  name: commerce\n  title: Commerce\nspec:\n  owner: group:orders\n"))
+	})
+	It("writes areas with components as systems in the closest domain", func() {
+		Expect(out).To(ContainSubstring("kind: System\nmetadata:\n  name: shop\n  title: Online Shop\nspec:\n  owner: group:orders\n  domain: commerce\n"))
+		Expect(out).To(ContainSubstring("kind: System\nmetadata:\n  name: checkout\n  title: Checkout\nspec:\n  owner: group:orders\n  domain: commerce\n"))
+	})
+	It("writes teams as groups", func() {
+		Expect(out).To(ContainSubstring("kind: Group\nmetadata:\n  name: orders\n  title: Order Squad\nspec:\n  type: team\n  profile:\n    displayName: Order Squad\n    email: orders@example.com\n  children: []\n"))
+	})
+	It("writes a group for components without a team", func() {
+		Expect(out).To(ContainSubstring("name: unassigned"))
+		Expect(out).To(ContainSubstring("owner: group:unassigned"))
+	})
+	It("writes components and resources", func() {
+		Expect(out).To(ContainSubstring("kind: Component\nmetadata:\n  name: web\n  title: Web Shop\n  annotations:\n    backstage.io/source-location: url:https://github.com/example/web\nspec:\n  type: service\n  lifecycle: production\n  owner: group:orders\n  system: shop\n  dependsOn:\n  - resource:orders-db\n"))
+		Expect(out).To(ContainSubstring("kind: Resource\nmetadata:\n  name: orders-db\n  title: Orders Db\nspec:\n  type: database\n  owner: group:orders\n  system: checkout\n"))
+	})
+	It("keeps lifecycle statuses backstage does not know in an annotation", func() {
+		Expect(out).To(ContainSubstring("gomponere/lifecycle: decommissioned"))
+		Expect(out).To(ContainSubstring("lifecycle: deprecated"))
+	})
+
+	It("writes tags and links", func() {
+		Expect(out).To(ContainSubstring("  tags:\n  - mainframe\n  links:\n  - url: https://wiki.example.com/legacy\n    title: Runbook\n"))
+	})
+
+	Context("when imported again", func() {
+		var imported model.Diagram
+
+		JustBeforeEach(func() {
+			fs := afero.NewMemMapFs()
+			afero.WriteFile(fs, "/catalog-info.yaml", []byte(out), os.ModePerm)
+			imported, err = backstage.Import(fs, "/catalog-info.yaml", importer.Options{})
+		})
+
+		It("does not error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("keeps the components", func() {
+			Expect(imported.Components["web"].Name).To(Equal("Web Shop"))
+			Expect(imported.Components["web"].Git).To(Equal("https://github.com/example/web"))
+			Expect(imported.Components["web"].DependencyKeys).To(Equal([]string{"orders-db"}))
+			Expect(imported.Components["legacy"].Lifecycle.Status).To(Equal(model.Decommissioned))
+			Expect(imported.Components["legacy"].Tags).To(Equal([]string{"mainframe"}))
+			Expect(imported.Components["legacy"].Links).To(Equal([]model.Link{{Name: "Runbook", URL: "https://wiki.example.com/legacy"}}))
+		})
+		It("keeps the areas, except for systems within systems", func() {
+			Expect(imported.Areas["shop"]).To(Equal(model.Area{Name: "Online Shop", ParentKey: "commerce"}))
+			Expect(imported.Areas["checkout"]).To(Equal(model.Area{Name: "Checkout", ParentKey: "commerce"}))
+		})
+	})
+})
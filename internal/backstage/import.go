@@ -0,0 +1,198 @@
+package backstage
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/abramsimon/gomponere/internal/importer"
+	"github.com/abramsimon/gomponere/internal/input"
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+)
+
+// lifecycles maps backstage lifecycles to lifecycle statuses, production is the default and left empty
+var lifecycles = map[string]string{
+	"experimental": model.InDevelopment,
+	"deprecated":   model.Deprecated,
+}
+
+// Import creates a diagram from the backstage catalog files under root, or from a single catalog file
+//
+// Domains and Systems become areas, nested by the domain of a system and the parent domain of a subdomain. Groups
+// become teams. Components and Resources become components, with their spec.type as type, their owner as team, their
+// system as area and dependsOn as dependencies. A component that consumes an API depends on the components that
+// provide it. Other kinds are ignored, as are namespaces.
+// Components without an owner or a system are given the team and area in opts.
+func Import(fs afero.Fs, root string, opts importer.Options) (model.Diagram, error) {
+	entities, err := read(fs, root)
+	if err != nil {
+		return model.Diagram{}, err
+	}
+
+	d := model.Diagram{
+		Areas:      map[string]model.Area{},
+		Components: map[string]model.Component{},
+		Teams:      map[string]model.Team{},
+		Types:      map[string]model.Type{},
+	}
+
+	// the components providing each api
+	providers := map[string][]string{}
+
+	for _, e := range entities {
+		name := e.Metadata.Name
+		title := e.Metadata.Title
+		if title == "" {
+			title = importer.Name(name)
+		}
+
+		switch e.Kind {
+		case Domain:
+			d.Areas[name] = model.Area{Name: title, ParentKey: refName(e.Spec.SubdomainOf)}
+		case System:
+			d.Areas[name] = model.Area{Name: title, ParentKey: refName(e.Spec.Domain)}
+		case Group:
+			if e.Spec.Profile.DisplayName != "" && e.Metadata.Title == "" {
+				title = e.Spec.Profile.DisplayName
+			}
+			t := model.Team{Name: title}
+			if e.Spec.Profile.Email != "" {
+				t.TeamContact = model.TeamContact{Name: title, Email: e.Spec.Profile.Email}
+			}
+			d.Teams[name] = t
+		case Component, Resource:
+			for _, ref := range e.Spec.ProvidesAPIs {
+				providers[refName(ref)] = append(providers[refName(ref)], name)
+			}
+		}
+	}
+
+	for _, e := range entities {
+		if e.Kind != Component && e.Kind != Resource {
+			continue
+		}
+
+		c := model.Component{
+			Name:        e.Metadata.Title,
+			Description: e.Metadata.Description,
+			Git:         strings.TrimPrefix(e.Metadata.Annotations[SourceLocationAnnotation], "url:"),
+			TypeKey:     importer.Key(e.Spec.Type),
+			TeamKey:     refName(e.Spec.Owner),
+			AreaKey:     refName(e.Spec.System),
+			Tags:        e.Metadata.Tags,
+		}
+		for _, l := range e.Metadata.Links {
+			c.Links = append(c.Links, model.Link{Name: l.Title, URL: l.URL})
+		}
+		if c.Name == "" {
+			c.Name = importer.Name(e.Metadata.Name)
+		}
+
+		c.Lifecycle.Status = lifecycles[e.Spec.Lifecycle]
+		if s := e.Metadata.Annotations[LifecycleAnnotation]; s != "" {
+			c.Lifecycle.Status = s
+		}
+
+		if c.TeamKey == "" {
+			c.TeamKey = opts.TeamKey
+			if c.TeamKey == "" {
+				c.TeamKey = importer.Unassigned
+			}
+		}
+		if _, exists := d.Teams[c.TeamKey]; !exists {
+			d.Teams[c.TeamKey] = model.Team{Name: importer.Name(c.TeamKey)}
+		}
+
+		if c.AreaKey == "" {
+			c.AreaKey = opts.AreaKey
+			if c.AreaKey == "" {
+				c.AreaKey = importer.Unassigned
+			}
+		}
+		if _, exists := d.Areas[c.AreaKey]; !exists {
+			d.Areas[c.AreaKey] = model.Area{Name: importer.Name(c.AreaKey)}
+		}
+
+		if c.TypeKey != "" {
+			if t, exists := importer.Types[c.TypeKey]; exists {
+				d.Types[c.TypeKey] = t
+			} else if _, exists := d.Types[c.TypeKey]; !exists {
+				d.Types[c.TypeKey] = model.Type{Name: importer.Name(c.TypeKey)}
+			}
+		}
+
+		deps := []string{}
+		add := func(key string) {
+			if key != e.Metadata.Name && !contains(deps, key) {
+				deps = append(deps, key)
+			}
+		}
+		for _, ref := range e.Spec.DependsOn {
+			add(refName(ref))
+		}
+		for _, ref := range e.Spec.ConsumesAPIs {
+			for _, p := range providers[refName(ref)] {
+				add(p)
+			}
+		}
+		sort.Strings(deps)
+		c.DependencyKeys = deps
+
+		d.Components[e.Metadata.Name] = c
+	}
+
+	if len(d.Components) == 0 {
+		return model.Diagram{}, fmt.Errorf("no components or resources found in '%s'", root)
+	}
+
+	return d, nil
+}
+
+// read reads every entity from every yaml file under root
+func read(fs afero.Fs, root string) ([]entity, error) {
+	files, err := input.NewReader(fs).FindFiles(root)
+	if err != nil {
+		return nil, err
+	}
+
+	entities := []entity{}
+	for _, name := range files {
+		f, err := fs.Open(name)
+		if err != nil {
+			return nil, err
+		}
+
+		dec := yaml.NewDecoder(f)
+		for {
+			var e entity
+			err := dec.Decode(&e)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				f.Close()
+				return nil, fmt.Errorf("unable to read '%s': %s", name, err)
+			}
+
+			if e.Kind != "" && e.Metadata.Name != "" {
+				entities = append(entities, e)
+			}
+		}
+		f.Close()
+	}
+
+	return entities, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
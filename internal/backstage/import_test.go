@@ -0,0 +1,192 @@
+package backstage_test
+
+import (
+	"os"
+
+	"github.com/abramsimon/gomponere/internal/backstage"
+	"github.com/abramsimon/gomponere/internal/importer"
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"github.com/spf13/afero"
+)
+
+var _ = Describe("Import", func() {
+	var (
+		err  error
+		fs   afero.Fs
+		opts importer.Options
+		d    model.Diagram
+	)
+
+	BeforeEach(func() {
+		fs = afero.NewMemMapFs()
+		opts = importer.Options{}
+	})
+
+	JustBeforeEach(func() {
+		d, err = backstage.Import(fs, "/catalog", opts)
+	})
+
+	Context("with an empty filesystem", func() {
+		It("does error", func() {
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("with invalid yaml", func() {
+		BeforeEach(func() {
+			afero.WriteFile(fs, "/catalog/catalog-info.yaml", []byte("kind: ["), os.ModePerm)
+		})
+
+		It("does error", func() {
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("with a catalog", func() {
+		BeforeEach(func() {
+			afero.WriteFile(fs, "/catalog/org.yaml", []byte(catalogOrg), os.ModePerm)
+			afero.WriteFile(fs, "/catalog/shop/catalog-info.yaml", []byte(catalogShop), os.ModePerm)
+		})
+
+		It("does not error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("maps domains and systems to nested areas", func() {
+			Expect(d.Areas).To(HaveKeyWithValue("commerce", model.Area{Name: "Commerce"}))
+			Expect(d.Areas).To(HaveKeyWithValue("retail", model.Area{Name: "Retail", ParentKey: "commerce"}))
+			Expect(d.Areas).To(HaveKeyWithValue("shop", model.Area{Name: "Online Shop", ParentKey: "retail"}))
+		})
+		It("maps groups to teams", func() {
+			Expect(d.Teams).To(HaveKeyWithValue("orders", model.Team{
+				Name:        "Order Squad",
+				TeamContact: model.TeamContact{Name: "Order Squad", Email: "orders@example.com"},
+			}))
+		})
+		It("maps components and resources to components", func() {
+			Expect(d.Components).To(HaveLen(3))
+			Expect(d.Components["web"]).To(MatchFields(IgnoreExtras, Fields{
+				"Name":        Equal("Web Shop"),
+				"Description": Equal("The storefront"),
+				"Git":         Equal("https://github.com/example/web/tree/main/"),
+				"TypeKey":     Equal("website"),
+				"TeamKey":     Equal("orders"),
+				"AreaKey":     Equal("shop"),
+			}))
+			Expect(d.Components["orders-db"]).To(MatchFields(IgnoreExtras, Fields{
+				"Name":    Equal("Orders Db"),
+				"TypeKey": Equal(importer.Database),
+			}))
+		})
+		It("adds the types used", func() {
+			Expect(d.Types).To(HaveKeyWithValue("website", model.Type{Name: "Website"}))
+			Expect(d.Types).To(HaveKeyWithValue(importer.Database, importer.Types[importer.Database]))
+		})
+		It("maps lifecycles", func() {
+			Expect(d.Components["web"].Lifecycle.Status).To(BeEmpty())
+			Expect(d.Components["orders-api"].Lifecycle.Status).To(Equal(model.InDevelopment))
+		})
+		It("takes dependencies from dependsOn and consumed apis", func() {
+			Expect(d.Components["web"].DependencyKeys).To(Equal([]string{"orders-api"}))
+			Expect(d.Components["orders-api"].DependencyKeys).To(Equal([]string{"orders-db"}))
+		})
+		It("gives components without an owner or system the placeholders", func() {
+			Expect(d.Components["orders-db"].TeamKey).To(Equal(importer.Unassigned))
+			Expect(d.Components["orders-db"].AreaKey).To(Equal(importer.Unassigned))
+			Expect(d.Teams).To(HaveKey(importer.Unassigned))
+			Expect(d.Areas).To(HaveKey(importer.Unassigned))
+		})
+	})
+})
+
+const catalogOrg string = `
+apiVersion: backstage.io/v1alpha1
+kind: Domain
+metadata:
+  name: commerce
+spec:
+  owner: group:orders
+---
+apiVersion: backstage.io/v1alpha1
+kind: Domain
+metadata:
+  name: retail
+spec:
+  owner: group:orders
+  subdomainOf: commerce
+---
+apiVersion: backstage.io/v1alpha1
+kind: Group
+metadata:
+  name: orders
+spec:
+  type: team
+  profile:
+    displayName: Order Squad
+    email: orders@example.com
+  children: []
+---
+apiVersion: backstage.io/v1alpha1
+kind: User
+metadata:
+  name: jane
+spec:
+  memberOf: [orders]
+`
+
+const catalogShop string = `
+apiVersion: backstage.io/v1alpha1
+kind: System
+metadata:
+  name: shop
+  title: Online Shop
+spec:
+  owner: group:orders
+  domain: domain:default/retail
+---
+apiVersion: backstage.io/v1alpha1
+kind: Component
+metadata:
+  name: web
+  title: Web Shop
+  description: The storefront
+  annotations:
+    backstage.io/source-location: url:https://github.com/example/web/tree/main/
+spec:
+  type: website
+  lifecycle: production
+  owner: orders
+  system: shop
+  consumesApis: [orders-api]
+---
+apiVersion: backstage.io/v1alpha1
+kind: Component
+metadata:
+  name: orders-api
+spec:
+  type: service
+  lifecycle: experimental
+  owner: group:default/orders
+  system: system:shop
+  providesApis: [api:orders-api]
+  dependsOn: [resource:default/orders-db]
+---
+apiVersion: backstage.io/v1alpha1
+kind: API
+metadata:
+  name: orders-api
+spec:
+  type: openapi
+  lifecycle: production
+  owner: orders
+  definition: "openapi: 3.0.0"
+---
+apiVersion: backstage.io/v1alpha1
+kind: Resource
+metadata:
+  name: orders-db
+spec:
+  type: database
+`
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/abramsimon/gomponere/internal/lint"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+)
+
+// Name is the name of the config file, which is looked for in the input directory
+const Name = ".gomponere.yaml"
+
+// Config holds the project settings that are not part of the model
+type Config struct {
+	Lint lint.Config `yaml:"lint,omitempty"`
+
+	// Theme is the theme diagrams are rendered with unless another one is asked for
+	Theme string `yaml:"theme,omitempty"`
+
+	// Themes are theme files to load, relative to the input directory
+	Themes []string `yaml:"themes,omitempty"`
+}
+
+// Load reads the config file at path, returning an empty config when the file does not exist
+func Load(fs afero.Fs, path string) (Config, error) {
+	b, err := afero.ReadFile(fs, path)
+	if os.IsNotExist(err) {
+		return Config{}, nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+
+	c := Config{}
+	if err := yaml.UnmarshalStrict(b, &c); err != nil {
+		return Config{}, fmt.Errorf("unable to read '%s': %s", path, err)
+	}
+
+	return c, nil
+}
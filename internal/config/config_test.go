@@ -0,0 +1,81 @@
+package config_test
+
+import (
+	"github.com/abramsimon/gomponere/internal/config"
+	"github.com/abramsimon/gomponere/internal/lint"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+var _ = Describe("Load", func() {
+	var (
+		fs  afero.Fs
+		c   config.Config
+		err error
+	)
+
+	BeforeEach(func() {
+		fs = afero.NewMemMapFs()
+	})
+
+	JustBeforeEach(func() {
+		c, err = config.Load(fs, "arch/"+config.Name)
+	})
+
+	Context("without a config file", func() {
+		It("returns an empty config", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c).To(Equal(config.Config{}))
+		})
+	})
+
+	Context("with lint rules", func() {
+		BeforeEach(func() {
+			afero.WriteFile(fs, "arch/.gomponere.yaml", []byte(`
+lint:
+  rules:
+    - name: database-level
+      for: component
+      where: type == "database"
+      assert: level == "component"
+      message: databases must be in the component level
+      severity: error
+`), 0644)
+		})
+
+		It("reads them", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Lint.Rules).To(Equal([]lint.RuleConfig{{
+				Name:     "database-level",
+				For:      "component",
+				Where:    `type == "database"`,
+				Assert:   `level == "component"`,
+				Message:  "databases must be in the component level",
+				Severity: "error",
+			}}))
+		})
+	})
+
+	Context("with themes", func() {
+		BeforeEach(func() {
+			afero.WriteFile(fs, "arch/.gomponere.yaml", []byte("theme: brand\nthemes: [themes/brand.yaml]\n"), 0644)
+		})
+
+		It("reads them", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Theme).To(Equal("brand"))
+			Expect(c.Themes).To(Equal([]string{"themes/brand.yaml"}))
+		})
+	})
+
+	Context("with an unknown setting", func() {
+		BeforeEach(func() {
+			afero.WriteFile(fs, "arch/.gomponere.yaml", []byte("lint:\n  ruels: []\n"), 0644)
+		})
+
+		It("fails", func() {
+			Expect(err).To(MatchError(ContainSubstring("unable to read 'arch/.gomponere.yaml'")))
+		})
+	})
+})
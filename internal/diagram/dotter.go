@@ -1,21 +1,49 @@
 package diagram
 
 import (
-	"../model"
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/abramsimon/gomponere/internal/theme"
 	"github.com/emicklei/dot"
 )
 
+// Options allow callers to decorate the graph as it is made
+type Options struct {
+	// NodeStyle is called for each component node after the default attributes have been set
+	NodeStyle func(key string, component model.Component, node dot.Node)
+
+	// EdgeStyle is called for each dependency edge after the default attributes have been set
+	EdgeStyle func(fromKey string, toKey string, edge dot.Edge)
+
+	// Theme sets the colors and fonts, the empty theme keeps the graphviz defaults
+	Theme theme.Theme
+
+	// Legend is where the legend is drawn, LegendOn when empty
+	Legend string
+
+	// Scale replaces the teams in the legend when nodes are filled with colors that mean something else, like the
+	// shades of a heat map
+	Scale *Scale
+
+	// AreaURL is the address area clusters link to, with AreaKeyPlaceholder replaced by the area's key
+	// areas link to their first link when empty
+	AreaURL string
+}
+
 func MakeDot(diagram model.Diagram) (string, error) {
+	return MakeDotWithOptions(diagram, Options{})
+}
+
+func MakeDotWithOptions(diagram model.Diagram, opts Options) (string, error) {
+	if opts.Legend == LegendOnly {
+		return MakeLegendDot(diagram, opts)
+	}
+
 	// start our directed graph
 	g := dot.NewGraph(dot.Directed)
 
 	// rank direction of LR is important for our layout
 	g.Attr("rankdir", "LR")
-
-	// add the teams to the graph (kinda like a legend)
-	if err := MakeTeams(diagram, g); err != nil {
-		return "", err
-	}
+	MakeTheme(g, opts.Theme)
 
 	// keep a running list of nodes to make edges from
 	nodes := make(map[string]dot.Node, len(diagram.Components))
@@ -23,7 +51,7 @@ func MakeDot(diagram model.Diagram) (string, error) {
 	// add areas and components to the graph, starting with root areas (no parents) and traversing recursively
 	for k, a := range diagram.Areas {
 		if a.ParentKey == "" {
-			n, err := MakeArea(diagram, g, a, k)
+			n, err := MakeArea(diagram, g, a, k, opts)
 			if err != nil {
 				return "", err
 			}
@@ -38,23 +66,52 @@ func MakeDot(diagram model.Diagram) (string, error) {
 	// add the edges for all component dependencies
 	for lk, lc := range diagram.Components {
 		for _, rk := range lc.DependencyKeys {
-			nodes[lk].Edge(nodes[rk]).Attr("constraint", "false")
+			// components outside of any known area are never added to the graph
+			ln, lok := nodes[lk]
+			rn, rok := nodes[rk]
+			if !lok || !rok {
+				continue
+			}
+
+			e := ln.Edge(rn).Attr("constraint", "false")
+			if opts.Theme.EdgeColor != "" {
+				e.Attr("color", opts.Theme.EdgeColor)
+			}
+
+			// nothing should still be using a decommissioned component
+			if diagram.Components[rk].Lifecycle.Current() == model.Decommissioned {
+				e.Attr("style", "dashed")
+			}
+
+			for _, s := range diagram.ComponentStyles(rk) {
+				MakeEdgeStyle(e, s.Edge)
+			}
+
+			if opts.EdgeStyle != nil {
+				opts.EdgeStyle(lk, rk, e)
+			}
 		}
 	}
 
+	if opts.Legend != LegendOff {
+		MakeLegend(diagram, g, opts)
+	}
+
 	return g.String(), nil
 }
 
-func MakeArea(diagram model.Diagram, graph *dot.Graph, area model.Area, areaKey string) (map[string]dot.Node, error) {
+func MakeArea(diagram model.Diagram, graph *dot.Graph, area model.Area, areaKey string, opts Options) (map[string]dot.Node, error) {
 	nodes := map[string]dot.Node{}
 
-	// add the area to the graph
+	// add the area to the graph, colored for how deep it is
 	g := graph.Subgraph(area.Name, dot.ClusterOption{})
+	MakeBox(g, opts.Theme.Area(len(diagram.AreaAncestors(areaKey))-1))
+	MakeAreaLink(g, area, areaKey, opts.AreaURL)
 
 	// add child areas to the graph
 	for k, a := range diagram.Areas {
 		if a.ParentKey == areaKey {
-			n, err := MakeArea(diagram, g, a, k)
+			n, err := MakeArea(diagram, g, a, k, opts)
 			if err != nil {
 				return nil, err
 			}
@@ -80,7 +137,7 @@ func MakeArea(diagram model.Diagram, graph *dot.Graph, area model.Area, areaKey
 
 	// create a subgraph for each level with it's components
 	for k, c := range componentsByLevel {
-		n, err := MakeLevels(diagram, g, diagram.Levels[k], k, c)
+		n, err := MakeLevels(diagram, g, diagram.Levels[k], k, c, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -94,7 +151,7 @@ func MakeArea(diagram model.Diagram, graph *dot.Graph, area model.Area, areaKey
 	return nodes, nil
 }
 
-func MakeLevels(diagram model.Diagram, graph *dot.Graph, level model.Level, levelKey string, components map[string]model.Component) (map[string]dot.Node, error) {
+func MakeLevels(diagram model.Diagram, graph *dot.Graph, level model.Level, levelKey string, components map[string]model.Component, opts Options) (map[string]dot.Node, error) {
 	nodes := make(map[string]dot.Node, len(components))
 
 	// create a subgraph so the components can be ranked the same
@@ -107,39 +164,24 @@ func MakeLevels(diagram model.Diagram, graph *dot.Graph, level model.Level, leve
 		t := diagram.Teams[c.TeamKey]
 
 		n := g.Node(c.Name)
-		n.Attr("style", "filled").
-			Attr("color", t.Display.BackgroundColor).
-			Attr("fontcolor", t.Display.ForegroundColor)
+		n.Attr("style", "filled")
+		MakeNodeColors(n, t, opts.Theme)
 
-		// create an invisible edge to the previous node
-		// this is the trick that makes left-right ranking work
-		if prev != nil {
-			prev.Edge(n).
-				Attr("style", "invisible").
-				Attr("dir", "none")
+		if shape := diagram.Types[c.TypeKey].Shape; shape != "" {
+			n.Attr("shape", shape)
 		}
 
-		// make the current the previous so we can create an edge to it
-		prev = &n
-
-		// capture the node for creating edges later
-		nodes[k] = n
-	}
-
-	return nodes, nil
-}
+		MakeLifecycle(c, n)
+		MakeComponentLink(c, n)
 
-func MakeTeams(diagram model.Diagram, graph *dot.Graph) error {
-	// add the teams subgraph to the graph
-	g := graph.Subgraph("Teams", dot.ClusterOption{})
-	g.Attr("rank", "same")
+		// styles come after the defaults so they can override them, and before the options so callers get the last word
+		for _, s := range diagram.ComponentStyles(k) {
+			MakeStyle(n, s)
+		}
 
-	var prev *dot.Node
-	for _, t := range diagram.Teams {
-		n := g.Node(t.Name)
-		n.Attr("style", "filled").
-			Attr("color", t.Display.BackgroundColor).
-			Attr("fontcolor", t.Display.ForegroundColor)
+		if opts.NodeStyle != nil {
+			opts.NodeStyle(k, c, n)
+		}
 
 		// create an invisible edge to the previous node
 		// this is the trick that makes left-right ranking work
@@ -149,8 +191,12 @@ func MakeTeams(diagram model.Diagram, graph *dot.Graph) error {
 				Attr("dir", "none")
 		}
 
+		// make the current the previous so we can create an edge to it
 		prev = &n
+
+		// capture the node for creating edges later
+		nodes[k] = n
 	}
 
-	return nil
+	return nodes, nil
 }
@@ -1,8 +1,10 @@
 package diagram_test
 
 import (
-	"../diagram"
-	"../model"
+	"github.com/abramsimon/gomponere/internal/diagram"
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/abramsimon/gomponere/internal/theme"
+	dotlib "github.com/emicklei/dot"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -31,4 +33,392 @@ var _ = Describe("MakeDot", func() {
 			Expect(dot).To(ContainSubstring("digraph"))
 		})
 	})
+
+	Context("with a component outside of any area", func() {
+		BeforeEach(func() {
+			d = model.Diagram{
+				Areas: map[string]model.Area{
+					"area": {Name: "Area"},
+				},
+				Components: map[string]model.Component{
+					"inside":  {Name: "Inside", AreaKey: "area", DependencyKeys: []string{"outside", "missing"}},
+					"outside": {Name: "Outside", AreaKey: "nowhere"},
+				},
+			}
+		})
+
+		It("does not error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("skips the dependency", func() {
+			Expect(dot).ToNot(ContainSubstring("->"))
+		})
+	})
+})
+
+var _ = Describe("MakeDotWithOptions", func() {
+	var (
+		err   error
+		d     model.Diagram
+		opts  diagram.Options
+		dot   string
+		nodes []string
+		edges []string
+	)
+
+	BeforeEach(func() {
+		nodes, edges = nil, nil
+		d = model.Diagram{
+			Areas: map[string]model.Area{
+				"area": {Name: "Area"},
+			},
+			Components: map[string]model.Component{
+				"web": {Name: "Web", AreaKey: "area", DependencyKeys: []string{"api"}},
+				"api": {Name: "Api", AreaKey: "area"},
+			},
+		}
+		opts = diagram.Options{
+			NodeStyle: func(key string, c model.Component, n dotlib.Node) {
+				nodes = append(nodes, key)
+				n.Attr("shape", "star")
+			},
+			EdgeStyle: func(from string, to string, e dotlib.Edge) {
+				edges = append(edges, from+"->"+to)
+				e.Attr("color", "pink")
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		dot, err = diagram.MakeDotWithOptions(d, opts)
+	})
+
+	It("does not error", func() {
+		Expect(err).To(BeNil())
+	})
+	It("calls the hooks", func() {
+		Expect(nodes).To(ConsistOf("web", "api"))
+		Expect(edges).To(ConsistOf("web->api"))
+	})
+	It("applies the hook attributes", func() {
+		Expect(dot).To(ContainSubstring(`shape="star"`))
+		Expect(dot).To(ContainSubstring(`color="pink"`))
+	})
+})
+
+var _ = Describe("MakeLifecycle", func() {
+	var (
+		err error
+		d   model.Diagram
+		dot string
+	)
+
+	BeforeEach(func() {
+		d = model.Diagram{
+			Areas: map[string]model.Area{
+				"area": {Name: "Area"},
+			},
+			Teams: map[string]model.Team{
+				"team": {Name: "Team", Display: model.Display{BackgroundColor: "coral"}},
+			},
+			Components: map[string]model.Component{
+				"active":  {Name: "Active", AreaKey: "area", TeamKey: "team", DependencyKeys: []string{"gone"}},
+				"planned": {Name: "Planned", AreaKey: "area", TeamKey: "team", Lifecycle: model.Lifecycle{Status: model.Planned}},
+				"old":     {Name: "Old", AreaKey: "area", TeamKey: "team", Lifecycle: model.Lifecycle{Status: model.Deprecated}},
+				"gone":    {Name: "Gone", AreaKey: "area", TeamKey: "team", Lifecycle: model.Lifecycle{Status: model.Decommissioned}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		dot, err = diagram.MakeDot(d)
+	})
+
+	It("does not error", func() {
+		Expect(err).To(BeNil())
+	})
+	It("leaves active components alone", func() {
+		Expect(dot).To(ContainSubstring(`[color="coral",fontcolor="",label="Active",style="filled"]`))
+	})
+	It("draws planned components with a dashed border", func() {
+		Expect(dot).To(ContainSubstring(`color="gray30",fillcolor="coral",fontcolor="",label="Planned\n(planned)",style="filled,dashed"`))
+	})
+	It("greys out deprecated components", func() {
+		Expect(dot).To(ContainSubstring(`color="gray60",fillcolor="gray85",fontcolor="gray40",label="Old\n(deprecated)"`))
+	})
+	It("dashes edges to decommissioned components", func() {
+		Expect(dot).To(MatchRegexp(`n\d+->n\d+\[constraint="false",style="dashed"\]`))
+	})
+})
+
+var _ = Describe("MakeStyle", func() {
+	var (
+		err error
+		d   model.Diagram
+		dot string
+	)
+
+	BeforeEach(func() {
+		d = model.Diagram{
+			Areas: map[string]model.Area{
+				"area": {Name: "Area"},
+			},
+			Teams: map[string]model.Team{
+				"team": {Name: "Team", Display: model.Display{BackgroundColor: "coral"}},
+			},
+			Types: map[string]model.Type{
+				"database": {Name: "Database", Shape: "cylinder"},
+			},
+			Components: map[string]model.Component{
+				"web": {Name: "Web", AreaKey: "area", TeamKey: "team", Tags: []string{"pci"}, DependencyKeys: []string{"db"}},
+				"db":  {Name: "Db", AreaKey: "area", TeamKey: "team", TypeKey: "database", Lifecycle: model.Lifecycle{Status: model.Planned}},
+			},
+			Styles: []model.Style{
+				{Select: model.Selector{Tag: "pci"}, BorderColor: "red", BorderStyle: model.Bold, Icon: "lock.png"},
+				{Select: model.Selector{Team: "team"}, BorderColor: "blue", Font: "Helvetica"},
+				{Select: model.Selector{Type: "database"}, BorderStyle: model.Solid, Edge: model.EdgeStyle{Color: "gray", Style: model.Dotted, Width: "2"}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		dot, err = diagram.MakeDot(d)
+	})
+
+	It("does not error", func() {
+		Expect(err).To(BeNil())
+	})
+	It("lets the most specific style win while keeping the team fill", func() {
+		Expect(dot).To(ContainSubstring(`[color="red",fillcolor="coral",fontcolor="",fontname="Helvetica",image="lock.png",imagescale="true",label="Web",labelloc="b",style="filled,bold"]`))
+	})
+	It("uses the shape of the type and overrides the lifecycle border", func() {
+		Expect(dot).To(ContainSubstring(`[color="blue",fillcolor="coral",fontcolor="",fontname="Helvetica",label="Db\n(planned)",shape="cylinder",style="filled"]`))
+	})
+	It("styles the dependencies on a component", func() {
+		Expect(dot).To(MatchRegexp(`n\d+->n\d+\[color="gray",constraint="false",penwidth="2",style="dotted"\]`))
+	})
+})
+
+var _ = Describe("MakeTheme", func() {
+	var (
+		err error
+		d   model.Diagram
+		t   theme.Theme
+		dot string
+	)
+
+	BeforeEach(func() {
+		d = model.Diagram{
+			Areas: map[string]model.Area{
+				"outer": {Name: "Outer"},
+				"inner": {Name: "Inner", ParentKey: "outer"},
+			},
+			Teams: map[string]model.Team{
+				"team": {Name: "Team", Display: model.Display{BackgroundColor: "coral", ForegroundColor: "white"}},
+			},
+			Components: map[string]model.Component{
+				"web": {Name: "Web", AreaKey: "inner", TeamKey: "team", DependencyKeys: []string{"db"}},
+				"db":  {Name: "Db", AreaKey: "outer"},
+			},
+		}
+		t = theme.Theme{
+			Background: "black",
+			Font:       theme.Font{Name: "Helvetica", Color: "white"},
+			Areas:      []theme.Box{{Fill: "gray10", Border: "gray30"}, {Fill: "gray20"}},
+			Unowned:    theme.Box{Fill: "gray40", Border: "gray60"},
+			EdgeColor:  "gray80",
+			Legend:     theme.Box{Fill: "gray5"},
+		}
+	})
+
+	JustBeforeEach(func() {
+		dot, err = diagram.MakeDotWithOptions(d, diagram.Options{Theme: t})
+	})
+
+	It("does not error", func() {
+		Expect(err).To(BeNil())
+	})
+	It("sets the background and font", func() {
+		Expect(dot).To(ContainSubstring(`bgcolor="black";fontcolor="white";fontname="Helvetica";rankdir="LR";`))
+	})
+	It("colors areas by how deep they are", func() {
+		Expect(dot).To(ContainSubstring(`color="gray30";fillcolor="gray10";label="Outer";style="filled";`))
+		Expect(dot).To(ContainSubstring(`fillcolor="gray20";label="Inner";style="filled";`))
+		Expect(dot).To(ContainSubstring(`fillcolor="gray5";label="Legend";style="filled";`))
+	})
+	It("keeps team colors and gives unowned components the theme's", func() {
+		Expect(dot).To(ContainSubstring(`[color="coral",fontcolor="white",fontname="Helvetica",label="Web",style="filled"]`))
+		Expect(dot).To(ContainSubstring(`[color="gray60",fillcolor="gray40",fontcolor="white",fontname="Helvetica",label="Db",style="filled"]`))
+	})
+	It("colors the edges", func() {
+		Expect(dot).To(MatchRegexp(`n\d+->n\d+\[color="gray80",constraint="false"\]`))
+	})
+
+	Context("when the theme turns team colors off", func() {
+		BeforeEach(func() {
+			off := false
+			t.TeamColors = &off
+		})
+
+		It("draws every component with the unowned colors", func() {
+			Expect(dot).To(ContainSubstring(`[color="gray60",fillcolor="gray40",fontcolor="white",fontname="Helvetica",label="Web",style="filled"]`))
+		})
+	})
+})
+
+var _ = Describe("MakeLegend", func() {
+	var (
+		err  error
+		d    model.Diagram
+		opts diagram.Options
+		dot  string
+	)
+
+	BeforeEach(func() {
+		opts = diagram.Options{}
+		d = model.Diagram{
+			Areas: map[string]model.Area{
+				"area": {Name: "Area"},
+			},
+			Teams: map[string]model.Team{
+				"shop":  {Name: "Shop", Display: model.Display{BackgroundColor: "coral"}},
+				"infra": {Name: "Web"},
+			},
+			Types: map[string]model.Type{
+				"database": {Name: "Database", Shape: "cylinder"},
+				"service":  {Name: "Service"},
+			},
+			Components: map[string]model.Component{
+				"web":    {Name: "Web", AreaKey: "area", TeamKey: "shop", DependencyKeys: []string{"db", "legacy"}},
+				"db":     {Name: "Db", AreaKey: "area", TypeKey: "database", Tags: []string{"pci"}},
+				"legacy": {Name: "Legacy", AreaKey: "area", Lifecycle: model.Lifecycle{Status: model.Decommissioned}},
+			},
+			Styles: []model.Style{
+				{Select: model.Selector{Tag: "pci", Type: "database"}, Edge: model.EdgeStyle{Color: "red"}},
+				{Select: model.Selector{Team: "infra"}, Edge: model.EdgeStyle{Color: "blue"}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		dot, err = diagram.MakeDotWithOptions(d, opts)
+	})
+
+	It("does not error", func() {
+		Expect(err).To(BeNil())
+	})
+	It("shows the team colors apart from the components", func() {
+		Expect(dot).To(ContainSubstring(`[color="coral",fontcolor="",label="Shop",shape="box",style="filled"]`))
+		Expect(dot).To(ContainSubstring(`[color="",fontcolor="",label="Web",shape="box",style="filled"]`))
+		Expect(dot).To(ContainSubstring(`[color="coral",fontcolor="",label="Web",style="filled"]`))
+	})
+	It("shows the types with shapes", func() {
+		Expect(dot).To(ContainSubstring(`[label="Database",shape="cylinder"]`))
+		Expect(dot).ToNot(ContainSubstring(`label="Service"`))
+	})
+	It("explains the edges that are drawn", func() {
+		Expect(dot).To(MatchRegexp(`n\d+->n\d+\[label="depends on"\]`))
+		Expect(dot).To(MatchRegexp(`n\d+->n\d+\[label="on a decommissioned component",style="dashed"\]`))
+		Expect(dot).To(MatchRegexp(`n\d+->n\d+\[color="red",label="on tag pci, type database"\]`))
+		Expect(dot).ToNot(ContainSubstring("team infra"))
+	})
+
+	Context("when turned off", func() {
+		BeforeEach(func() {
+			opts.Legend = diagram.LegendOff
+		})
+
+		It("leaves it out", func() {
+			Expect(dot).ToNot(ContainSubstring(`label="Legend"`))
+		})
+	})
+
+	Context("when it is the only thing drawn", func() {
+		BeforeEach(func() {
+			opts.Legend = diagram.LegendOnly
+		})
+
+		It("leaves the diagram out", func() {
+			Expect(dot).To(ContainSubstring(`label="Legend"`))
+			Expect(dot).ToNot(ContainSubstring(`label="Area"`))
+			Expect(dot).ToNot(ContainSubstring(`label="Legacy`))
+		})
+	})
+})
+
+var _ = Describe("MakeComponentLink", func() {
+	var (
+		err  error
+		d    model.Diagram
+		opts diagram.Options
+		dot  string
+	)
+
+	BeforeEach(func() {
+		opts = diagram.Options{}
+		d = model.Diagram{
+			Areas: map[string]model.Area{
+				"area":  {Name: "Area"},
+				"other": {Name: "Other", Links: []model.Link{{Name: "wiki", URL: "https://wiki.example.com/other"}}},
+			},
+			Teams: map[string]model.Team{
+				"team": {Name: "Team", TeamContact: model.TeamContact{Name: "Shop", Email: "shop@example.com"}},
+			},
+			Components: map[string]model.Component{
+				"web": {Name: "Web", AreaKey: "area", TeamKey: "team", Description: "the web shop", Git: "git@github.com:org/web.git"},
+				"api": {Name: "Api", AreaKey: "other", Links: []model.Link{{URL: "https://docs.example.com/api"}}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		dot, err = diagram.MakeDotWithOptions(d, opts)
+	})
+
+	It("does not error", func() {
+		Expect(err).To(BeNil())
+	})
+	It("links components to their repository with their description as the tooltip", func() {
+		Expect(dot).To(ContainSubstring(`URL="https://github.com/org/web"`))
+		Expect(dot).To(ContainSubstring(`tooltip="the web shop"`))
+	})
+	It("links components without a repository to their first link", func() {
+		Expect(dot).To(ContainSubstring(`URL="https://docs.example.com/api"`))
+	})
+	It("links areas to their first link", func() {
+		Expect(dot).To(ContainSubstring(`URL="https://wiki.example.com/other"`))
+		Expect(dot).To(ContainSubstring(`tooltip="Other"`))
+	})
+	It("links the teams in the legend to their contact", func() {
+		Expect(dot).To(ContainSubstring(`URL="mailto:shop@example.com"`))
+		Expect(dot).To(ContainSubstring(`tooltip="Shop <shop@example.com>"`))
+	})
+
+	Context("with an area address", func() {
+		BeforeEach(func() {
+			opts.AreaURL = "https://arch.example.com/areas/{key}"
+		})
+
+		It("links every area to its page", func() {
+			Expect(dot).To(ContainSubstring(`URL="https://arch.example.com/areas/area"`))
+			Expect(dot).To(ContainSubstring(`URL="https://arch.example.com/areas/other"`))
+			Expect(dot).ToNot(ContainSubstring(`URL="https://wiki.example.com/other"`))
+		})
+	})
+})
+
+var _ = Describe("RepoURL", func() {
+	for git, url := range map[string]string{
+		"":                                     "",
+		"git@github.com:org/repo.git":          "https://github.com/org/repo",
+		"ssh://git@gitlab.com/org/repo.git":    "https://gitlab.com/org/repo",
+		"ssh://git@github.com:22/acme/web.git": "https://github.com/acme/web",
+		"https://github.com/org/repo.git":      "https://github.com/org/repo",
+		"https://bitbucket.org/org/repo":       "https://bitbucket.org/org/repo",
+	} {
+		git, url := git, url
+		It("turns '"+git+"' into '"+url+"'", func() {
+			Expect(diagram.RepoURL(git)).To(Equal(url))
+		})
+	}
 })
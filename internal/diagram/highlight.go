@@ -0,0 +1,19 @@
+package diagram
+
+import (
+	"github.com/emicklei/dot"
+)
+
+// Outline keeps the node's fill but draws a thick border in the given color, to highlight it
+func Outline(n dot.Node, color string) {
+	keepFill(n)
+	n.Attr("color", color).
+		Attr("penwidth", "3")
+}
+
+// keepFill makes the fill explicit so the border color can change without changing it
+func keepFill(node dot.Node) {
+	if node.Value("fillcolor") == nil {
+		node.Attr("fillcolor", node.Value("color"))
+	}
+}
@@ -0,0 +1,217 @@
+package diagram
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/emicklei/dot"
+)
+
+// where the legend is drawn
+const (
+	// LegendOn draws the legend in a cluster of its own next to the diagram, the default
+	LegendOn = "on"
+
+	// LegendOff leaves the legend out
+	LegendOff = "off"
+
+	// LegendOnly draws only the legend, so it can be rendered as a separate image
+	LegendOnly = "only"
+)
+
+// Scale is what the fill colors of the nodes mean, shown in the legend instead of the teams
+type Scale struct {
+	Title string
+	Steps []ScaleStep
+}
+
+// ScaleStep is a fill color and what it stands for
+type ScaleStep struct {
+	Label     string
+	FillColor string
+	FontColor string
+}
+
+// legendEdge is an example edge in the legend and what it means
+type legendEdge struct {
+	meaning string
+	style   model.EdgeStyle
+}
+
+// MakeLegendDot draws only the legend of the diagram
+func MakeLegendDot(diagram model.Diagram, opts Options) (string, error) {
+	g := dot.NewGraph(dot.Directed)
+	g.Attr("rankdir", "LR")
+	MakeTheme(g, opts.Theme)
+
+	MakeLegend(diagram, g, opts)
+
+	return g.String(), nil
+}
+
+// MakeLegend adds a cluster to the graph with the colors of the teams, or the scale of the options instead, the shapes
+// of the types and what the styles of the edges mean
+// the legend's nodes are not connected to the diagram's, so they never change where components are drawn
+func MakeLegend(diagram model.Diagram, graph *dot.Graph, opts Options) {
+	t := opts.Theme
+	teams := sortedKeys(diagram.Teams)
+	scale := []ScaleStep{}
+	if opts.Scale != nil {
+		teams, scale = nil, opts.Scale.Steps
+	}
+	types := []string{}
+	for _, k := range sortedKeys(diagram.Types) {
+		if diagram.Types[k].Shape != "" {
+			types = append(types, k)
+		}
+	}
+	edges := legendEdges(diagram)
+
+	if len(teams) == 0 && len(scale) == 0 && len(types) == 0 && len(edges) == 0 {
+		return
+	}
+
+	// the id is not a valid area name, so the legend is never mixed up with an area called "Legend"
+	g := graph.Subgraph(" legend", dot.ClusterOption{})
+	g.Attr("label", "Legend")
+	MakeBox(g, t.Legend)
+
+	if len(teams) > 0 {
+		column := legendColumn(legendSection(g, "Teams"), "teams")
+		for _, k := range teams {
+			n := column.Node("legend-team-"+k).Label(diagram.Teams[k].Name).
+				Attr("shape", "box").
+				Attr("style", "filled")
+			MakeNodeColors(n, diagram.Teams[k], t)
+			MakeTeamLink(n, diagram.Teams[k])
+		}
+	}
+
+	if len(scale) > 0 {
+		column := legendColumn(legendSection(g, opts.Scale.Title), "scale")
+		for i, step := range scale {
+			n := column.Node(fmt.Sprintf("legend-scale-%d", i)).Label(step.Label).
+				Attr("shape", "box").
+				Attr("style", "filled")
+			attrs(n.AttributesMap, "fillcolor", step.FillColor, "fontcolor", step.FontColor, "fontname", t.Font.Name, "fontsize", t.Font.Size)
+		}
+	}
+
+	if len(types) > 0 {
+		column := legendColumn(legendSection(g, "Types"), "types")
+		for _, k := range types {
+			n := column.Node("legend-type-"+k).Label(diagram.Types[k].Name).
+				Attr("shape", diagram.Types[k].Shape)
+			attrs(n.AttributesMap, "color", t.Legend.Border, "fontcolor", t.Legend.FontColor, "fontname", t.Font.Name, "fontsize", t.Font.Size)
+		}
+	}
+
+	if len(edges) > 0 {
+		s := legendSection(g, "Dependencies")
+		from, to := legendColumn(s, "from"), legendColumn(s, "to")
+		for i, le := range edges {
+			f := from.Node(fmt.Sprintf("legend-edge-%d-from", i)).Label("").Attr("shape", "point").Attr("width", "0.05")
+			n := to.Node(fmt.Sprintf("legend-edge-%d-to", i)).Label("").Attr("shape", "point").Attr("width", "0.05")
+			e := f.Edge(n).Attr("label", le.meaning)
+			attrs(e.AttributesMap, "color", t.EdgeColor, "fontcolor", t.Font.Color, "fontname", t.Font.Name, "fontsize", t.Font.Size)
+			MakeEdgeStyle(e, le.style)
+		}
+	}
+}
+
+// legendSection adds a titled section to the legend, drawn without a border of its own
+func legendSection(legend *dot.Graph, title string) *dot.Graph {
+	s := legend.Subgraph(title, dot.ClusterOption{})
+	s.Attr("peripheries", "0")
+
+	return s
+}
+
+// legendColumn adds a subgraph to the section whose nodes are drawn above each other
+func legendColumn(section *dot.Graph, name string) *dot.Graph {
+	column := section.Subgraph(name)
+	column.Delete("label")
+	column.Attr("rank", "same")
+
+	return column
+}
+
+// legendEdges returns the kinds of edges drawn in the diagram, starting with plain dependencies and followed by the
+// lifecycle and the styles that change how they look
+func legendEdges(diagram model.Diagram) []legendEdge {
+	edges := []legendEdge{}
+
+	plain, decommissioned := false, false
+	styled := map[int]bool{}
+	for _, k := range diagram.ComponentKeys() {
+		if !drawn(diagram, k) || !hasDrawnDependent(diagram, k) {
+			continue
+		}
+
+		decommissioned = decommissioned || diagram.Components[k].Lifecycle.Current() == model.Decommissioned
+		plain = plain || diagram.Components[k].Lifecycle.Current() != model.Decommissioned
+		for i, s := range diagram.Styles {
+			if s.Edge != (model.EdgeStyle{}) && s.Select.MatchesComponent(diagram, k) {
+				styled[i] = true
+			}
+		}
+	}
+
+	if plain {
+		edges = append(edges, legendEdge{"depends on", model.EdgeStyle{}})
+	}
+	if decommissioned {
+		edges = append(edges, legendEdge{"on a decommissioned component", model.EdgeStyle{Style: model.Dashed}})
+	}
+	for i, s := range diagram.Styles {
+		if styled[i] {
+			edges = append(edges, legendEdge{"on " + describe(s.Select), s.Edge})
+		}
+	}
+
+	return edges
+}
+
+// drawn returns true when the component is in an area that is drawn, which are the areas with a chain of parents up
+// to an area without one
+func drawn(diagram model.Diagram, key string) bool {
+	ancestors := diagram.AreaAncestors(diagram.Components[key].AreaKey)
+	return len(ancestors) > 0 && diagram.Areas[ancestors[len(ancestors)-1]].ParentKey == ""
+}
+
+func hasDrawnDependent(diagram model.Diagram, key string) bool {
+	for _, k := range diagram.Dependents(key) {
+		if drawn(diagram, k) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// describe writes what a selector picks, like "tag pci, team orders"
+func describe(s model.Selector) string {
+	parts := []string{}
+	for _, p := range [][2]string{{"tag", s.Tag}, {"type", s.Type}, {"level", s.Level}, {"team", s.Team}, {"area", s.Area}, {"lifecycle", s.Lifecycle}} {
+		if p[1] != "" {
+			parts = append(parts, p[0]+" "+p[1])
+		}
+	}
+	if len(parts) == 0 {
+		return "everything"
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,37 @@
+package diagram
+
+import (
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/emicklei/dot"
+)
+
+// MakeLifecycle changes the look of the node to reflect the lifecycle status of the component
+// active components keep their team colors, everything else is drawn differently and labeled with its status
+func MakeLifecycle(component model.Component, node dot.Node) {
+	status := component.Lifecycle.Current()
+	if status == model.Active {
+		return
+	}
+
+	node.Label(component.Name + "\n(" + status + ")")
+
+	switch status {
+	case model.Planned:
+		// keep the team fill but draw a dashed outline around it
+		keepFill(node)
+		node.Attr("color", "gray30").
+			Attr("style", "filled,dashed")
+	case model.InDevelopment:
+		keepFill(node)
+		node.Attr("color", "gray30").
+			Attr("style", "filled,dotted")
+	case model.Deprecated:
+		node.Attr("fillcolor", "gray85").
+			Attr("color", "gray60").
+			Attr("fontcolor", "gray40")
+	case model.Decommissioned:
+		node.Attr("color", "gray70").
+			Attr("fontcolor", "gray60").
+			Attr("style", "dashed")
+	}
+}
@@ -0,0 +1,77 @@
+package diagram
+
+import (
+	"strings"
+
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/emicklei/dot"
+)
+
+// AreaKeyPlaceholder is replaced by the key of the area in Options.AreaURL
+const AreaKeyPlaceholder = "{key}"
+
+// MakeComponentLink gives the node of a component its description as a tooltip and links it to its repository, or
+// to its first link when it has no repository, so clicking it in an svg opens it
+func MakeComponentLink(component model.Component, node dot.Node) {
+	if component.Description != "" {
+		node.Attr("tooltip", component.Description)
+	}
+
+	url := RepoURL(component.Git)
+	if url == "" && len(component.Links) > 0 {
+		url = component.Links[0].URL
+	}
+	if url != "" {
+		node.Attr("URL", url)
+	}
+}
+
+// MakeAreaLink links the cluster of an area to its page, made from the template with AreaKeyPlaceholder replaced by
+// the area's key, or to the area's first link without a template
+func MakeAreaLink(cluster *dot.Graph, area model.Area, areaKey string, template string) {
+	url := ""
+	if template != "" {
+		url = strings.ReplaceAll(template, AreaKeyPlaceholder, areaKey)
+	} else if len(area.Links) > 0 {
+		url = area.Links[0].URL
+	}
+
+	if url != "" {
+		cluster.Attr("URL", url)
+		cluster.Attr("tooltip", area.Name)
+	}
+}
+
+// MakeTeamLink links the node of a team to a mail to its team contact
+func MakeTeamLink(node dot.Node, team model.Team) {
+	c := team.TeamContact
+	if c.Email == "" {
+		return
+	}
+
+	node.Attr("URL", "mailto:"+c.Email)
+	if c.Name != "" {
+		node.Attr("tooltip", c.Name+" <"+c.Email+">")
+	} else {
+		node.Attr("tooltip", c.Email)
+	}
+}
+
+// RepoURL returns the web address of a git repository, turning ssh remotes like git@github.com:org/repo.git or
+// ssh://git@github.com:22/org/repo.git into https://github.com/org/repo
+// anything else is returned without the .git suffix
+func RepoURL(git string) string {
+	url := strings.TrimSuffix(strings.TrimSpace(git), ".git")
+
+	switch {
+	case strings.HasPrefix(url, "ssh://"):
+		// the port is the one ssh listens on, which means nothing to https
+		host, path, _ := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(url, "ssh://"), "git@"), "/")
+		host, _, _ = strings.Cut(host, ":")
+		url = "https://" + host + "/" + path
+	case strings.HasPrefix(url, "git@"):
+		url = "https://" + strings.Replace(strings.TrimPrefix(url, "git@"), ":", "/", 1)
+	}
+
+	return url
+}
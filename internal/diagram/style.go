@@ -0,0 +1,68 @@
+package diagram
+
+import (
+	"strings"
+
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/emicklei/dot"
+)
+
+// MakeStyle draws the node with the attributes the style sets, leaving the others as they are
+func MakeStyle(node dot.Node, style model.Style) {
+	if style.Fill != "" {
+		node.Attr("fillcolor", style.Fill)
+	}
+	if style.BorderColor != "" {
+		keepFill(node)
+		node.Attr("color", style.BorderColor)
+	}
+	if style.BorderStyle != "" {
+		node.Attr("style", borderStyle(node.Value("style"), style.BorderStyle))
+	}
+	if style.Font != "" {
+		node.Attr("fontname", style.Font)
+	}
+	if style.FontColor != "" {
+		node.Attr("fontcolor", style.FontColor)
+	}
+	if style.Shape != "" {
+		node.Attr("shape", style.Shape)
+	}
+	if style.Icon != "" {
+		node.Attr("image", style.Icon).
+			Attr("imagescale", "true").
+			Attr("labelloc", "b")
+	}
+}
+
+// MakeEdgeStyle draws the edge with the attributes the style sets, leaving the others as they are
+func MakeEdgeStyle(edge dot.Edge, style model.EdgeStyle) {
+	if style.Color != "" {
+		edge.Attr("color", style.Color)
+	}
+	if style.Style != "" {
+		edge.Attr("style", style.Style)
+	}
+	if style.Width != "" {
+		edge.Attr("penwidth", style.Width)
+	}
+}
+
+// borderStyle replaces the border part of a graphviz style list, like "filled,dashed", keeping the rest of it
+func borderStyle(current interface{}, border string) string {
+	s, _ := current.(string)
+
+	parts := []string{}
+	for _, p := range strings.Split(s, ",") {
+		switch p {
+		case "", model.Solid, model.Dashed, model.Dotted, model.Bold:
+		default:
+			parts = append(parts, p)
+		}
+	}
+	if border != model.Solid {
+		parts = append(parts, border)
+	}
+
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,50 @@
+package diagram
+
+import (
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/abramsimon/gomponere/internal/theme"
+	"github.com/emicklei/dot"
+)
+
+// MakeTheme sets the background of the graph and its font, which the labels of clusters are drawn with
+func MakeTheme(graph *dot.Graph, t theme.Theme) {
+	attrs(graph.AttributesMap, "bgcolor", t.Background, "fontname", t.Font.Name, "fontsize", t.Font.Size, "fontcolor", t.Font.Color)
+}
+
+// MakeBox fills a cluster with the colors of the box
+func MakeBox(graph *dot.Graph, box theme.Box) {
+	if box.Fill != "" {
+		graph.Attr("style", "filled")
+	}
+	attrs(graph.AttributesMap, "fillcolor", box.Fill, "color", box.Border, "fontcolor", box.FontColor, "penwidth", box.Width)
+}
+
+// MakeNodeColors colors the node for its team and sets its font
+// components of teams without colors get the theme's unowned colors, as do all of them when the theme turns team
+// colors off
+func MakeNodeColors(node dot.Node, team model.Team, t theme.Theme) {
+	box := theme.Box{Fill: team.Display.BackgroundColor, FontColor: team.Display.ForegroundColor}
+	if (box.Fill == "" || !t.UsesTeamColors()) && t.Unowned != (theme.Box{}) {
+		box = t.Unowned
+	}
+	if box.FontColor == "" {
+		box.FontColor = t.Font.Color
+	}
+
+	node.Attr("color", box.Fill).
+		Attr("fontcolor", box.FontColor)
+	if box.Border != "" {
+		node.Attr("fillcolor", box.Fill).
+			Attr("color", box.Border)
+	}
+	attrs(node.AttributesMap, "penwidth", box.Width, "fontname", t.Font.Name, "fontsize", t.Font.Size)
+}
+
+// attrs sets the attributes given as name and value pairs, skipping the empty values
+func attrs(m dot.AttributesMap, pairs ...string) {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			m.Attr(pairs[i], pairs[i+1])
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package diff
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/abramsimon/gomponere/internal/model"
+)
+
+type Kind string
+
+const (
+	Added    Kind = "added"
+	Removed  Kind = "removed"
+	Modified Kind = "modified"
+)
+
+// Change describes an entity that differs between the old and the new diagram
+type Change struct {
+	Kind   Kind     `json:"kind"`
+	Key    string   `json:"key"`
+	Name   string   `json:"name"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+// DependencyChange describes a dependency edge that only exists in one of the diagrams
+type DependencyChange struct {
+	Kind Kind   `json:"kind"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type Diff struct {
+	Components   []Change           `json:"components"`
+	Teams        []Change           `json:"teams"`
+	Areas        []Change           `json:"areas"`
+	Dependencies []DependencyChange `json:"dependencies"`
+}
+
+// Compare finds everything that was added, removed or modified going from old to new
+func Compare(old model.Diagram, new model.Diagram) Diff {
+	d := Diff{
+		Components:   []Change{},
+		Teams:        []Change{},
+		Areas:        []Change{},
+		Dependencies: []DependencyChange{},
+	}
+
+	// dependencies are compared as edges below rather than as a field of the component
+	for _, k := range unionKeys(old.Components, new.Components) {
+		o, inOld := old.Components[k]
+		n, inNew := new.Components[k]
+		if c, changed := compare(k, o, n, inOld, inNew, o.Name, n.Name, "dependencies"); changed {
+			d.Components = append(d.Components, c)
+		}
+	}
+
+	for _, k := range unionKeys(old.Teams, new.Teams) {
+		o, inOld := old.Teams[k]
+		n, inNew := new.Teams[k]
+		if c, changed := compare(k, o, n, inOld, inNew, o.Name, n.Name); changed {
+			d.Teams = append(d.Teams, c)
+		}
+	}
+
+	for _, k := range unionKeys(old.Areas, new.Areas) {
+		o, inOld := old.Areas[k]
+		n, inNew := new.Areas[k]
+		if c, changed := compare(k, o, n, inOld, inNew, o.Name, n.Name); changed {
+			d.Areas = append(d.Areas, c)
+		}
+	}
+
+	oldEdges, newEdges := edges(old), edges(new)
+	for _, e := range unionKeys(oldEdges, newEdges) {
+		from, to := splitEdge(e)
+		switch {
+		case !oldEdges[e]:
+			d.Dependencies = append(d.Dependencies, DependencyChange{Added, from, to})
+		case !newEdges[e]:
+			d.Dependencies = append(d.Dependencies, DependencyChange{Removed, from, to})
+		}
+	}
+
+	return d
+}
+
+// Empty returns true when the diagrams were the same
+func (d Diff) Empty() bool {
+	return len(d.Components) == 0 && len(d.Teams) == 0 && len(d.Areas) == 0 && len(d.Dependencies) == 0
+}
+
+// ComponentKind returns the kind of change for the component, or an empty kind if it did not change
+func (d Diff) ComponentKind(key string) Kind {
+	for _, c := range d.Components {
+		if c.Key == key {
+			return c.Kind
+		}
+	}
+
+	return ""
+}
+
+// DependencyKind returns the kind of change for the dependency, or an empty kind if it did not change
+func (d Diff) DependencyKind(from string, to string) Kind {
+	for _, c := range d.Dependencies {
+		if c.From == from && c.To == to {
+			return c.Kind
+		}
+	}
+
+	return ""
+}
+
+func compare(key string, old interface{}, new interface{}, inOld bool, inNew bool, oldName string, newName string, skip ...string) (Change, bool) {
+	switch {
+	case !inOld:
+		return Change{Kind: Added, Key: key, Name: newName}, true
+	case !inNew:
+		return Change{Kind: Removed, Key: key, Name: oldName}, true
+	}
+
+	fields := changedFields(old, new, skip...)
+	if len(fields) == 0 {
+		return Change{}, false
+	}
+
+	return Change{Kind: Modified, Key: key, Name: newName, Fields: fields}, true
+}
+
+// changedFields returns the yaml names of the fields that differ between two values of the same struct type
+func changedFields(old interface{}, new interface{}, skip ...string) []string {
+	ov, nv := reflect.ValueOf(old), reflect.ValueOf(new)
+
+	fields := []string{}
+	for i := 0; i < ov.NumField(); i++ {
+		name := strings.Split(ov.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		if name == "" || contains(skip, name) {
+			continue
+		}
+
+		if !reflect.DeepEqual(ov.Field(i).Interface(), nv.Field(i).Interface()) {
+			fields = append(fields, name)
+		}
+	}
+
+	return fields
+}
+
+// edges returns every dependency in the diagram as "from\x00to"
+func edges(d model.Diagram) map[string]bool {
+	e := map[string]bool{}
+	for k, c := range d.Components {
+		for _, dk := range c.DependencyKeys {
+			e[k+"\x00"+dk] = true
+		}
+	}
+
+	return e
+}
+
+func splitEdge(e string) (string, string) {
+	parts := strings.SplitN(e, "\x00", 2)
+	return parts[0], parts[1]
+}
+
+func unionKeys[V any](a map[string]V, b map[string]V) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		seen[k] = true
+	}
+	for k := range b {
+		seen[k] = true
+	}
+
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,177 @@
+package diff_test
+
+import (
+	"bytes"
+
+	"github.com/abramsimon/gomponere/internal/diff"
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diff", func() {
+	var (
+		old model.Diagram
+		new model.Diagram
+	)
+
+	BeforeEach(func() {
+		old = model.Diagram{
+			Areas: map[string]model.Area{
+				"area": {Name: "Area"},
+				"gone": {Name: "Gone"},
+			},
+			Teams: map[string]model.Team{
+				"team": {Name: "Team"},
+			},
+			Components: map[string]model.Component{
+				"web":    {Name: "Web", AreaKey: "area", TeamKey: "team", DependencyKeys: []string{"api"}},
+				"api":    {Name: "Api", AreaKey: "area", TeamKey: "team", DependencyKeys: []string{"legacy"}},
+				"legacy": {Name: "Legacy", AreaKey: "area"},
+			},
+		}
+		new = model.Diagram{
+			Areas: map[string]model.Area{
+				"area": {Name: "Area"},
+			},
+			Teams: map[string]model.Team{
+				"team": {Name: "Team", Display: model.Display{BackgroundColor: "coral"}},
+				"new":  {Name: "New Team"},
+			},
+			Components: map[string]model.Component{
+				"web": {Name: "Web", AreaKey: "area", TeamKey: "team", DependencyKeys: []string{"api"}},
+				"api": {Name: "Api", AreaKey: "area", TeamKey: "new", DependencyKeys: []string{"db"}},
+				"db":  {Name: "Database", AreaKey: "area"},
+			},
+		}
+	})
+
+	Describe("Compare", func() {
+		var (
+			d diff.Diff
+		)
+
+		JustBeforeEach(func() {
+			d = diff.Compare(old, new)
+		})
+
+		It("finds component changes", func() {
+			Expect(d.Components).To(Equal([]diff.Change{
+				{Kind: diff.Modified, Key: "api", Name: "Api", Fields: []string{"team"}},
+				{Kind: diff.Added, Key: "db", Name: "Database"},
+				{Kind: diff.Removed, Key: "legacy", Name: "Legacy"},
+			}))
+		})
+		It("finds team changes", func() {
+			Expect(d.Teams).To(Equal([]diff.Change{
+				{Kind: diff.Added, Key: "new", Name: "New Team"},
+				{Kind: diff.Modified, Key: "team", Name: "Team", Fields: []string{"display"}},
+			}))
+		})
+		It("finds area changes", func() {
+			Expect(d.Areas).To(Equal([]diff.Change{
+				{Kind: diff.Removed, Key: "gone", Name: "Gone"},
+			}))
+		})
+		It("finds dependency changes", func() {
+			Expect(d.Dependencies).To(Equal([]diff.DependencyChange{
+				{Kind: diff.Added, From: "api", To: "db"},
+				{Kind: diff.Removed, From: "api", To: "legacy"},
+			}))
+		})
+		It("looks up kinds", func() {
+			Expect(d.ComponentKind("db")).To(Equal(diff.Added))
+			Expect(d.ComponentKind("web")).To(BeEmpty())
+			Expect(d.DependencyKind("api", "legacy")).To(Equal(diff.Removed))
+			Expect(d.DependencyKind("web", "api")).To(BeEmpty())
+		})
+
+		Context("with the same diagram", func() {
+			BeforeEach(func() {
+				new = old
+			})
+
+			It("is empty", func() {
+				Expect(d.Empty()).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("Merge", func() {
+		var (
+			m model.Diagram
+		)
+
+		JustBeforeEach(func() {
+			m = diff.Merge(old, new)
+		})
+
+		It("contains everything", func() {
+			Expect(m.Components).To(HaveLen(4))
+			Expect(m.Areas).To(HaveLen(2))
+			Expect(m.Teams).To(HaveLen(2))
+		})
+		It("prefers the new version", func() {
+			Expect(m.Components["api"].TeamKey).To(Equal("new"))
+		})
+		It("keeps removed dependencies", func() {
+			Expect(m.Components["api"].DependencyKeys).To(ConsistOf("db", "legacy"))
+		})
+	})
+
+	Describe("MakeDot", func() {
+		var (
+			err error
+			dot string
+		)
+
+		JustBeforeEach(func() {
+			dot, err = diff.MakeDot(old, new)
+		})
+
+		It("does not error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("highlights the changes", func() {
+			Expect(dot).To(ContainSubstring(`color="green3"`))
+			Expect(dot).To(ContainSubstring(`color="red"`))
+			Expect(dot).To(ContainSubstring(`color="orange"`))
+			Expect(dot).To(ContainSubstring(`style="filled,dashed"`))
+		})
+	})
+
+	Describe("WriteText", func() {
+		var (
+			err error
+			buf *bytes.Buffer
+		)
+
+		BeforeEach(func() {
+			buf = &bytes.Buffer{}
+		})
+
+		JustBeforeEach(func() {
+			err = diff.WriteText(buf, diff.Compare(old, new))
+		})
+
+		It("does not error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("lists the changes", func() {
+			Expect(buf.String()).To(ContainSubstring("  ~ api (Api): team\n"))
+			Expect(buf.String()).To(ContainSubstring("  + db (Database)\n"))
+			Expect(buf.String()).To(ContainSubstring("  - legacy (Legacy)\n"))
+			Expect(buf.String()).To(ContainSubstring("  - api -> legacy\n"))
+		})
+
+		Context("with the same diagram", func() {
+			BeforeEach(func() {
+				new = old
+			})
+
+			It("says so", func() {
+				Expect(buf.String()).To(Equal("no changes\n"))
+			})
+		})
+	})
+})
@@ -0,0 +1,86 @@
+package diff
+
+import (
+	"github.com/abramsimon/gomponere/internal/diagram"
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/emicklei/dot"
+)
+
+const (
+	addedColor    = "green3"
+	removedColor  = "red"
+	modifiedColor = "orange"
+	ghostColor    = "gray90"
+	ghostFont     = "gray50"
+)
+
+// Merge returns a diagram containing everything in both diagrams so the differences can be drawn together
+// where an entity exists in both, the new version is used
+func Merge(old model.Diagram, new model.Diagram) model.Diagram {
+	m := model.Diagram{
+		Areas:      merge(old.Areas, new.Areas),
+		Components: merge(old.Components, new.Components),
+		Levels:     merge(old.Levels, new.Levels),
+		Teams:      merge(old.Teams, new.Teams),
+		Types:      merge(old.Types, new.Types),
+		Styles:     new.Styles,
+	}
+
+	// keep removed dependencies around so they can be drawn
+	for k, c := range m.Components {
+		if o, exists := old.Components[k]; exists {
+			deps := append([]string{}, c.DependencyKeys...)
+			for _, dk := range o.DependencyKeys {
+				if !contains(deps, dk) {
+					deps = append(deps, dk)
+				}
+			}
+			c.DependencyKeys = deps
+			m.Components[k] = c
+		}
+	}
+
+	return m
+}
+
+// MakeDot renders the merged diagram with added elements in green, removed elements in red and ghosted,
+// and modified elements highlighted
+func MakeDot(old model.Diagram, new model.Diagram) (string, error) {
+	d := Compare(old, new)
+
+	return diagram.MakeDotWithOptions(Merge(old, new), diagram.Options{
+		NodeStyle: func(key string, _ model.Component, n dot.Node) {
+			switch d.ComponentKind(key) {
+			case Added:
+				diagram.Outline(n, addedColor)
+			case Removed:
+				n.Attr("style", "filled,dashed").
+					Attr("color", removedColor).
+					Attr("fillcolor", ghostColor).
+					Attr("fontcolor", ghostFont)
+			case Modified:
+				diagram.Outline(n, modifiedColor)
+			}
+		},
+		EdgeStyle: func(from string, to string, e dot.Edge) {
+			switch d.DependencyKind(from, to) {
+			case Added:
+				e.Attr("color", addedColor).Attr("penwidth", "2")
+			case Removed:
+				e.Attr("color", removedColor).Attr("style", "dashed")
+			}
+		},
+	})
+}
+
+func merge[V any](old map[string]V, new map[string]V) map[string]V {
+	m := make(map[string]V, len(old)+len(new))
+	for k, v := range old {
+		m[k] = v
+	}
+	for k, v := range new {
+		m[k] = v
+	}
+
+	return m
+}
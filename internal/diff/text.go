@@ -0,0 +1,62 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+var symbols = map[Kind]string{
+	Added:    "+",
+	Removed:  "-",
+	Modified: "~",
+}
+
+// WriteText writes a human readable summary of the diff
+func WriteText(w io.Writer, d Diff) error {
+	if d.Empty() {
+		_, err := fmt.Fprintln(w, "no changes")
+		return err
+	}
+
+	sections := []struct {
+		title   string
+		changes []Change
+	}{
+		{"components", d.Components},
+		{"teams", d.Teams},
+		{"areas", d.Areas},
+	}
+
+	for _, s := range sections {
+		if len(s.changes) == 0 {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "%s:\n", s.title); err != nil {
+			return err
+		}
+		for _, c := range s.changes {
+			line := fmt.Sprintf("  %s %s (%s)", symbols[c.Kind], c.Key, c.Name)
+			if len(c.Fields) > 0 {
+				line += ": " + strings.Join(c.Fields, ", ")
+			}
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(d.Dependencies) > 0 {
+		if _, err := fmt.Fprintln(w, "dependencies:"); err != nil {
+			return err
+		}
+		for _, c := range d.Dependencies {
+			if _, err := fmt.Fprintf(w, "  %s %s -> %s\n", symbols[c.Kind], c.From, c.To); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
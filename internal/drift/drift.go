@@ -0,0 +1,94 @@
+package drift
+
+import (
+	"github.com/abramsimon/gomponere/internal/diff"
+	"github.com/abramsimon/gomponere/internal/model"
+)
+
+// Component is a component that is only declared or only deployed
+type Component struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+// Dependency is a dependency that is only declared or only deployed
+type Dependency struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Ownership is a component that is actually owned by a different team than the one declared
+type Ownership struct {
+	Key      string `json:"key"`
+	Declared string `json:"declared"`
+	Actual   string `json:"actual"`
+}
+
+// Report is how the actual architecture has drifted from the declared one
+type Report struct {
+	// Undeclared are the components that are deployed but not declared
+	Undeclared []Component `json:"undeclared"`
+
+	// Missing are the components that are declared but not deployed
+	Missing []Component `json:"missing"`
+
+	// UndeclaredDependencies are the dependencies that exist in reality but are not declared
+	UndeclaredDependencies []Dependency `json:"undeclared-dependencies"`
+
+	// MissingDependencies are the dependencies that are declared but do not exist in reality
+	MissingDependencies []Dependency `json:"missing-dependencies"`
+
+	// Ownership are the components owned by a different team than declared
+	Ownership []Ownership `json:"ownership"`
+}
+
+// Compare finds how the actual diagram differs from the declared one
+// components the actual diagram gives no team are not reported as ownership mismatches
+func Compare(declared model.Diagram, actual model.Diagram) Report {
+	r := Report{
+		Undeclared:             []Component{},
+		Missing:                []Component{},
+		UndeclaredDependencies: []Dependency{},
+		MissingDependencies:    []Dependency{},
+		Ownership:              []Ownership{},
+	}
+
+	d := diff.Compare(declared, actual)
+	for _, c := range d.Components {
+		switch c.Kind {
+		case diff.Added:
+			r.Undeclared = append(r.Undeclared, Component{c.Key, c.Name})
+		case diff.Removed:
+			r.Missing = append(r.Missing, Component{c.Key, c.Name})
+		}
+	}
+
+	for _, c := range d.Dependencies {
+		switch c.Kind {
+		case diff.Added:
+			r.UndeclaredDependencies = append(r.UndeclaredDependencies, Dependency{c.From, c.To})
+		case diff.Removed:
+			r.MissingDependencies = append(r.MissingDependencies, Dependency{c.From, c.To})
+		}
+	}
+
+	for _, k := range declared.ComponentKeys() {
+		a, exists := actual.Components[k]
+		if !exists || a.TeamKey == "" {
+			continue
+		}
+
+		if t := declared.Components[k].TeamKey; t != a.TeamKey {
+			r.Ownership = append(r.Ownership, Ownership{k, t, a.TeamKey})
+		}
+	}
+
+	return r
+}
+
+// Empty returns true when the actual architecture matches the declared one
+func (r Report) Empty() bool {
+	return len(r.Undeclared) == 0 && len(r.Missing) == 0 &&
+		len(r.UndeclaredDependencies) == 0 && len(r.MissingDependencies) == 0 &&
+		len(r.Ownership) == 0
+}
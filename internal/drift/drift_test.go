@@ -0,0 +1,169 @@
+package drift_test
+
+import (
+	"bytes"
+
+	"github.com/abramsimon/gomponere/internal/drift"
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Drift", func() {
+	var (
+		declared model.Diagram
+		actual   model.Diagram
+	)
+
+	BeforeEach(func() {
+		declared = model.Diagram{
+			Areas: map[string]model.Area{
+				"area": {Name: "Area"},
+			},
+			Teams: map[string]model.Team{
+				"web":    {Name: "Web"},
+				"orders": {Name: "Orders"},
+			},
+			Components: map[string]model.Component{
+				"web":    {Name: "Web", AreaKey: "area", TeamKey: "web", DependencyKeys: []string{"api"}},
+				"api":    {Name: "Api", AreaKey: "area", TeamKey: "orders", DependencyKeys: []string{"legacy"}},
+				"db":     {Name: "Database", AreaKey: "area", TeamKey: "orders"},
+				"legacy": {Name: "Legacy", AreaKey: "area", TeamKey: "orders"},
+			},
+		}
+		actual = model.Diagram{
+			Components: map[string]model.Component{
+				"web":   {Name: "web", TeamKey: "web", DependencyKeys: []string{"api", "db"}},
+				"api":   {Name: "api", TeamKey: "web", DependencyKeys: []string{"db", "cache"}},
+				"db":    {Name: "db"},
+				"cache": {Name: "cache"},
+			},
+		}
+	})
+
+	Describe("Compare", func() {
+		var r drift.Report
+
+		JustBeforeEach(func() {
+			r = drift.Compare(declared, actual)
+		})
+
+		It("finds components that are deployed but not declared", func() {
+			Expect(r.Undeclared).To(Equal([]drift.Component{{Key: "cache", Name: "cache"}}))
+		})
+		It("finds components that are declared but not deployed", func() {
+			Expect(r.Missing).To(Equal([]drift.Component{{Key: "legacy", Name: "Legacy"}}))
+		})
+		It("finds dependencies that are deployed but not declared", func() {
+			Expect(r.UndeclaredDependencies).To(Equal([]drift.Dependency{
+				{From: "api", To: "cache"},
+				{From: "api", To: "db"},
+				{From: "web", To: "db"},
+			}))
+		})
+		It("finds dependencies that are declared but not deployed", func() {
+			Expect(r.MissingDependencies).To(Equal([]drift.Dependency{{From: "api", To: "legacy"}}))
+		})
+		It("finds ownership mismatches, ignoring components without an actual team", func() {
+			Expect(r.Ownership).To(Equal([]drift.Ownership{{Key: "api", Declared: "orders", Actual: "web"}}))
+		})
+		It("is not empty", func() {
+			Expect(r.Empty()).To(BeFalse())
+		})
+
+		Context("when nothing drifted", func() {
+			BeforeEach(func() {
+				actual = declared
+			})
+
+			It("is empty", func() {
+				Expect(r.Empty()).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("MakeDot", func() {
+		var (
+			err error
+			dot string
+		)
+
+		JustBeforeEach(func() {
+			dot, err = drift.MakeDot(declared, actual)
+		})
+
+		It("does not error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("draws components as declared", func() {
+			Expect(dot).To(ContainSubstring(`label="Database"`))
+			Expect(dot).ToNot(ContainSubstring(`label="db"`))
+		})
+		It("highlights the drift", func() {
+			Expect(dot).To(ContainSubstring(`color="red"`))
+			Expect(dot).To(ContainSubstring(`color="orange"`))
+			Expect(dot).To(ContainSubstring(`style="filled,dashed"`))
+		})
+		It("draws components in no known area in an area of their own", func() {
+			Expect(dot).To(ContainSubstring(`label="Not in a known area"`))
+			Expect(dot).To(ContainSubstring(`label="cache"`))
+		})
+
+		Context("with an area whose parent is in neither diagram", func() {
+			BeforeEach(func() {
+				declared.Areas["orphan"] = model.Area{Name: "Orphan", ParentKey: "gone"}
+				declared.Components["db"] = model.Component{Name: "Database", AreaKey: "orphan", TeamKey: "orders"}
+			})
+
+			It("draws its components in the area of their own", func() {
+				Expect(dot).To(ContainSubstring(`label="Orphan"`))
+				Expect(dot).To(ContainSubstring(`label="Database"`))
+			})
+			It("leaves the diagrams alone", func() {
+				Expect(declared.Areas["orphan"].ParentKey).To(Equal("gone"))
+			})
+		})
+	})
+
+	Describe("WriteText", func() {
+		var (
+			err error
+			buf *bytes.Buffer
+		)
+
+		BeforeEach(func() {
+			buf = &bytes.Buffer{}
+		})
+
+		JustBeforeEach(func() {
+			err = drift.WriteText(buf, drift.Compare(declared, actual))
+		})
+
+		It("writes a section per kind of drift", func() {
+			Expect(err).To(BeNil())
+			Expect(buf.String()).To(Equal(`deployed but not declared:
+  + cache (cache)
+declared but not deployed:
+  - legacy (Legacy)
+dependencies deployed but not declared:
+  + api -> cache
+  + api -> db
+  + web -> db
+dependencies declared but not deployed:
+  - api -> legacy
+ownership:
+  ~ api: declared team 'orders', actually 'web'
+`))
+		})
+
+		Context("when nothing drifted", func() {
+			BeforeEach(func() {
+				actual = declared
+			})
+
+			It("says so", func() {
+				Expect(buf.String()).To(Equal("no drift\n"))
+			})
+		})
+	})
+})
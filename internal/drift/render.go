@@ -0,0 +1,90 @@
+package drift
+
+import (
+	"github.com/abramsimon/gomponere/internal/diagram"
+	"github.com/abramsimon/gomponere/internal/diff"
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/emicklei/dot"
+)
+
+const (
+	undeclaredColor = "red"
+	ownershipColor  = "orange"
+	missingColor    = "gray50"
+	ghostColor      = "gray90"
+
+	// unknownArea is where components are drawn when neither diagram puts them in a known area
+	unknownArea = "drift-unknown-area"
+)
+
+// MakeDot renders the declared and actual diagrams together, with undeclared components and dependencies in red,
+// missing ones ghosted and components owned by another team outlined in orange
+// where a component exists in both, it is drawn as declared, and components in no known area, or in an area whose
+// parent is in neither diagram, get one of their own so that nothing deployed goes unseen
+func MakeDot(declared model.Diagram, actual model.Diagram) (string, error) {
+	r := Compare(declared, actual)
+
+	merged := diff.Merge(actual, declared)
+	for k, c := range merged.Components {
+		if _, exists := merged.Areas[c.AreaKey]; !exists {
+			merged.Areas[unknownArea] = model.Area{Name: "Not in a known area"}
+			c.AreaKey = unknownArea
+			merged.Components[k] = c
+		}
+	}
+
+	// only areas with a chain of parents up to an area without one are drawn, so where the chain breaks off, at a
+	// parent in neither diagram or in a cycle, it is hung under the unknown area instead
+	for k := range merged.Areas {
+		ancestors := merged.AreaAncestors(k)
+		top := ancestors[len(ancestors)-1]
+		if a := merged.Areas[top]; a.ParentKey != "" {
+			merged.Areas[unknownArea] = model.Area{Name: "Not in a known area"}
+			a.ParentKey = unknownArea
+			merged.Areas[top] = a
+		}
+	}
+
+	undeclared, missing, owned := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	for _, c := range r.Undeclared {
+		undeclared[c.Key] = true
+	}
+	for _, c := range r.Missing {
+		missing[c.Key] = true
+	}
+	for _, o := range r.Ownership {
+		owned[o.Key] = true
+	}
+
+	undeclaredDeps, missingDeps := map[Dependency]bool{}, map[Dependency]bool{}
+	for _, d := range r.UndeclaredDependencies {
+		undeclaredDeps[d] = true
+	}
+	for _, d := range r.MissingDependencies {
+		missingDeps[d] = true
+	}
+
+	return diagram.MakeDotWithOptions(merged, diagram.Options{
+		NodeStyle: func(key string, _ model.Component, n dot.Node) {
+			switch {
+			case undeclared[key]:
+				diagram.Outline(n, undeclaredColor)
+			case missing[key]:
+				n.Attr("style", "filled,dashed").
+					Attr("color", missingColor).
+					Attr("fillcolor", ghostColor).
+					Attr("fontcolor", missingColor)
+			case owned[key]:
+				diagram.Outline(n, ownershipColor)
+			}
+		},
+		EdgeStyle: func(from string, to string, e dot.Edge) {
+			switch {
+			case undeclaredDeps[Dependency{from, to}]:
+				e.Attr("color", undeclaredColor).Attr("penwidth", "2")
+			case missingDeps[Dependency{from, to}]:
+				e.Attr("color", missingColor).Attr("style", "dashed")
+			}
+		},
+	})
+}
@@ -0,0 +1,69 @@
+package drift
+
+import (
+	"fmt"
+	"io"
+)
+
+// WriteText writes a human readable summary of the report
+func WriteText(w io.Writer, r Report) error {
+	if r.Empty() {
+		_, err := fmt.Fprintln(w, "no drift")
+		return err
+	}
+
+	sections := []struct {
+		title string
+		lines []string
+	}{
+		{"deployed but not declared", componentLines("+", r.Undeclared)},
+		{"declared but not deployed", componentLines("-", r.Missing)},
+		{"dependencies deployed but not declared", dependencyLines("+", r.UndeclaredDependencies)},
+		{"dependencies declared but not deployed", dependencyLines("-", r.MissingDependencies)},
+		{"ownership", ownershipLines(r.Ownership)},
+	}
+
+	for _, s := range sections {
+		if len(s.lines) == 0 {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "%s:\n", s.title); err != nil {
+			return err
+		}
+		for _, l := range s.lines {
+			if _, err := fmt.Fprintf(w, "  %s\n", l); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func componentLines(symbol string, components []Component) []string {
+	lines := []string{}
+	for _, c := range components {
+		lines = append(lines, fmt.Sprintf("%s %s (%s)", symbol, c.Key, c.Name))
+	}
+
+	return lines
+}
+
+func dependencyLines(symbol string, dependencies []Dependency) []string {
+	lines := []string{}
+	for _, d := range dependencies {
+		lines = append(lines, fmt.Sprintf("%s %s -> %s", symbol, d.From, d.To))
+	}
+
+	return lines
+}
+
+func ownershipLines(ownership []Ownership) []string {
+	lines := []string{}
+	for _, o := range ownership {
+		lines = append(lines, fmt.Sprintf("~ %s: declared team '%s', actually '%s'", o.Key, o.Declared, o.Actual))
+	}
+
+	return lines
+}
@@ -0,0 +1,53 @@
+package expr
+
+import "github.com/abramsimon/gomponere/internal/model"
+
+// ComponentFields are the fields of a component that expressions can use
+var ComponentFields = Fields{
+	"key":          String,
+	"name":         String,
+	"description":  String,
+	"git":          String,
+	"type":         String,
+	"level":        String,
+	"team":         String,
+	"area":         String,
+	"status":       String,
+	"dependencies": Number,
+	"dependents":   Number,
+	"tags":         List,
+	"properties.*": String,
+}
+
+// ComponentEnv returns the values of the fields of the component with the key
+func ComponentEnv(d model.Diagram, key string) Env {
+	c := d.Components[key]
+	e := Env{
+		"key":          key,
+		"name":         c.Name,
+		"description":  c.Description,
+		"git":          c.Git,
+		"type":         c.TypeKey,
+		"level":        c.LevelKey,
+		"team":         c.TeamKey,
+		"area":         c.AreaKey,
+		"status":       c.Lifecycle.Current(),
+		"dependencies": len(d.Dependencies(key)),
+		"dependents":   len(d.Dependents(key)),
+	}
+	e.SetMetadata(c.Tags, c.Properties)
+
+	return e
+}
+
+// Prefixed returns the fields once with each of the prefixes, like "from." and "to." for both ends of a dependency
+func Prefixed(fields Fields, prefixes ...string) Fields {
+	all := Fields{}
+	for _, p := range prefixes {
+		for name, t := range fields {
+			all[p+name] = t
+		}
+	}
+
+	return all
+}
@@ -0,0 +1,432 @@
+package expr
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/abramsimon/gomponere/internal/model"
+)
+
+// Type is the type of a field or the value of an expression
+type Type string
+
+// the types of values
+const (
+	String  Type = "string"
+	Number  Type = "number"
+	Boolean Type = "boolean"
+	List    Type = "list"
+)
+
+// Fields maps the names of the fields an expression can use to their types
+// a name ending in ".*", like "properties.*", allows any field starting with what comes before the "*"
+type Fields map[string]Type
+
+// Env holds the values of the fields of the entity an expression is evaluated for, which are strings, ints, bools
+// and string slices
+// fields that are missing have the zero value of their type
+type Env map[string]interface{}
+
+// SetMetadata sets the tags field and a "properties." field for each of the properties
+func (e Env) SetMetadata(tags []string, properties map[string]string) {
+	e["tags"] = tags
+	for name, value := range properties {
+		e["properties."+name] = value
+	}
+}
+
+// Expr is a compiled expression, which is true or false
+// the zero Expr is always true
+type Expr struct {
+	root node
+}
+
+// Eval returns whether the expression is true for the entity with the values in e
+func (x Expr) Eval(d model.Diagram, e Env) bool {
+	if x.root == nil {
+		return true
+	}
+
+	return x.root.eval(d, e).(bool)
+}
+
+// node is a part of a compiled expression
+type node interface {
+	typ() Type
+	eval(d model.Diagram, e Env) interface{}
+}
+
+type literal struct {
+	t     Type
+	value interface{}
+}
+
+func (l literal) typ() Type {
+	return l.t
+}
+
+func (l literal) eval(model.Diagram, Env) interface{} {
+	return l.value
+}
+
+type field struct {
+	t    Type
+	name string
+}
+
+func (f field) typ() Type {
+	return f.t
+}
+
+func (f field) eval(_ model.Diagram, e Env) interface{} {
+	if v, exists := e[f.name]; exists {
+		return v
+	}
+
+	switch f.t {
+	case String:
+		return ""
+	case Number:
+		return 0
+	case Boolean:
+		return false
+	}
+	return []string(nil)
+}
+
+type not struct {
+	x node
+}
+
+func (n not) typ() Type {
+	return Boolean
+}
+
+func (n not) eval(d model.Diagram, e Env) interface{} {
+	return !n.x.eval(d, e).(bool)
+}
+
+type binary struct {
+	op   string
+	x, y node
+}
+
+func (b binary) typ() Type {
+	return Boolean
+}
+
+func (b binary) eval(d model.Diagram, e Env) interface{} {
+	// the logical operators only evaluate what they need to
+	switch b.op {
+	case "&&":
+		return b.x.eval(d, e).(bool) && b.y.eval(d, e).(bool)
+	case "||":
+		return b.x.eval(d, e).(bool) || b.y.eval(d, e).(bool)
+	}
+
+	x, y := b.x.eval(d, e), b.y.eval(d, e)
+	switch b.op {
+	case "==":
+		return x == y
+	case "!=":
+		return x != y
+	case "<":
+		return x.(int) < y.(int)
+	case "<=":
+		return x.(int) <= y.(int)
+	case ">":
+		return x.(int) > y.(int)
+	case ">=":
+		return x.(int) >= y.(int)
+	case "matches":
+		matched, err := path.Match(y.(string), x.(string))
+		return err == nil && matched
+	case "within":
+		return d.InArea(x.(string), y.(string))
+	case "contains":
+		if list, ok := x.([]string); ok {
+			return model.HasTag(list, y.(string))
+		}
+		return strings.Contains(x.(string), y.(string))
+	}
+
+	return false
+}
+
+// Compile parses an expression over the fields, which must be true or false
+//
+// Expressions compare fields with strings, numbers, true and false using ==, !=, <, <=, > and >=, and combine the
+// comparisons with &&, || and !, in that order of precedence, and parentheses. "name matches pattern" matches a
+// string against a glob like "prod-*", and "area within key" is true for the area with the key and the areas inside
+// it. "tags contains value" is true when value is one of a list, and when used on a string it looks for value within
+// the string.
+func Compile(s string, fields Fields) (Expr, error) {
+	tokens, err := scan(s)
+	if err != nil {
+		return Expr{}, err
+	}
+
+	p := &parser{tokens: tokens, fields: fields}
+	x, err := p.or()
+	if err != nil {
+		return Expr{}, err
+	}
+	if t := p.peek(); t.kind != endToken {
+		return Expr{}, fmt.Errorf("unexpected '%s' at column %d", t.text, t.pos+1)
+	}
+	if x.typ() != Boolean {
+		return Expr{}, fmt.Errorf("expression is a %s, not true or false", x.typ())
+	}
+
+	return Expr{x}, nil
+}
+
+type tokenKind int
+
+const (
+	endToken tokenKind = iota
+	identToken
+	stringToken
+	intToken
+	opToken
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// operators are the symbols of the language, longest first so "<=" is not read as "<"
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")"}
+
+func scan(s string) ([]token, error) {
+	tokens := []token{}
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(s) && s[end] != '"' {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return nil, fmt.Errorf("unterminated string at column %d", i+1)
+			}
+			text, err := strconv.Unquote(s[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at column %d", i+1)
+			}
+			tokens = append(tokens, token{stringToken, text, i})
+			i = end + 1
+		case unicode.IsDigit(c):
+			end := i
+			for end < len(s) && unicode.IsDigit(rune(s[end])) {
+				end++
+			}
+			tokens = append(tokens, token{intToken, s[i:end], i})
+			i = end
+		case unicode.IsLetter(c) || c == '_':
+			end := i
+			for end < len(s) && (unicode.IsLetter(rune(s[end])) || unicode.IsDigit(rune(s[end])) || strings.ContainsRune("_.-", rune(s[end]))) {
+				end++
+			}
+			tokens = append(tokens, token{identToken, s[i:end], i})
+			i = end
+		default:
+			found := false
+			for _, op := range operators {
+				if strings.HasPrefix(s[i:], op) {
+					tokens = append(tokens, token{opToken, op, i})
+					i += len(op)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unexpected '%c' at column %d", c, i+1)
+			}
+		}
+	}
+
+	return append(tokens, token{endToken, "end of expression", len(s)}), nil
+}
+
+type parser struct {
+	tokens []token
+	fields Fields
+}
+
+func (p *parser) peek() token {
+	return p.tokens[0]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[0]
+	if t.kind != endToken {
+		p.tokens = p.tokens[1:]
+	}
+
+	return t
+}
+
+// accept consumes the next token when it is one of the operators or keywords
+func (p *parser) accept(ops ...string) (token, bool) {
+	t := p.peek()
+	if t.kind != opToken && t.kind != identToken {
+		return t, false
+	}
+
+	for _, op := range ops {
+		if t.text == op {
+			return p.next(), true
+		}
+	}
+
+	return t, false
+}
+
+func (p *parser) or() (node, error) {
+	return p.logical(p.and, "||")
+}
+
+func (p *parser) and() (node, error) {
+	return p.logical(p.not, "&&")
+}
+
+func (p *parser) logical(operand func() (node, error), op string) (node, error) {
+	x, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t, ok := p.accept(op)
+		if !ok {
+			return x, nil
+		}
+
+		y, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		if x.typ() != Boolean || y.typ() != Boolean {
+			return nil, fmt.Errorf("'%s' at column %d needs true or false on both sides", op, t.pos+1)
+		}
+		x = binary{op, x, y}
+	}
+}
+
+func (p *parser) not() (node, error) {
+	t, ok := p.accept("!")
+	if !ok {
+		return p.comparison()
+	}
+
+	x, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	if x.typ() != Boolean {
+		return nil, fmt.Errorf("'!' at column %d needs true or false", t.pos+1)
+	}
+
+	return not{x}, nil
+}
+
+func (p *parser) comparison() (node, error) {
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	t, ok := p.accept("==", "!=", "<", "<=", ">", ">=", "matches", "within", "contains")
+	if !ok {
+		return x, nil
+	}
+
+	y, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t.text {
+	case "==", "!=":
+		if x.typ() != y.typ() {
+			return nil, fmt.Errorf("'%s' at column %d compares a %s with a %s", t.text, t.pos+1, x.typ(), y.typ())
+		}
+		if x.typ() == List {
+			return nil, fmt.Errorf("'%s' at column %d cannot compare lists, use contains", t.text, t.pos+1)
+		}
+	case "<", "<=", ">", ">=":
+		if x.typ() != Number || y.typ() != Number {
+			return nil, fmt.Errorf("'%s' at column %d needs numbers on both sides", t.text, t.pos+1)
+		}
+	case "contains":
+		if (x.typ() != List && x.typ() != String) || y.typ() != String {
+			return nil, fmt.Errorf("'contains' at column %d needs a list or string on the left and a string on the right", t.pos+1)
+		}
+	case "matches", "within":
+		if x.typ() != String || y.typ() != String {
+			return nil, fmt.Errorf("'%s' at column %d needs strings on both sides", t.text, t.pos+1)
+		}
+		if l, ok := y.(literal); ok && t.text == "matches" {
+			if _, err := path.Match(l.value.(string), ""); err != nil {
+				return nil, fmt.Errorf("invalid pattern '%s' at column %d", l.value, t.pos+1)
+			}
+		}
+	}
+
+	return binary{t.text, x, y}, nil
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case stringToken:
+		return literal{String, t.text}, nil
+	case intToken:
+		n, err := strconv.Atoi(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number '%s' at column %d", t.text, t.pos+1)
+		}
+		return literal{Number, n}, nil
+	case identToken:
+		switch t.text {
+		case "true":
+			return literal{Boolean, true}, nil
+		case "false":
+			return literal{Boolean, false}, nil
+		}
+		if ft, exists := p.fields[t.text]; exists {
+			return field{ft, t.text}, nil
+		}
+		for name, ft := range p.fields {
+			if prefix := strings.TrimSuffix(name, "*"); strings.HasSuffix(name, ".*") && strings.HasPrefix(t.text, prefix) && len(t.text) > len(prefix) {
+				return field{ft, t.text}, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown field '%s' at column %d", t.text, t.pos+1)
+	case opToken:
+		if t.text == "(" {
+			x, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.accept(")"); !ok {
+				return nil, fmt.Errorf("expected ')' at column %d", p.peek().pos+1)
+			}
+			return x, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected '%s' at column %d", t.text, t.pos+1)
+}
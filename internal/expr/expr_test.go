@@ -0,0 +1,76 @@
+package expr_test
+
+import (
+	"github.com/abramsimon/gomponere/internal/expr"
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Expr", func() {
+	var (
+		d model.Diagram
+	)
+
+	BeforeEach(func() {
+		d = model.Diagram{
+			Areas: map[string]model.Area{
+				"prod":    {Name: "Production"},
+				"prod-eu": {Name: "Production EU", ParentKey: "prod"},
+			},
+			Components: map[string]model.Component{
+				"web": {Name: "Web", TypeKey: "app", AreaKey: "prod-eu", DependencyKeys: []string{"api"}, Tags: []string{"public", "pci"}, Properties: map[string]string{"tier": "1"}},
+				"api": {Name: "Api", AreaKey: "prod", Lifecycle: model.Lifecycle{Status: model.Deprecated}},
+			},
+		}
+	})
+
+	eval := func(s string, key string) bool {
+		x, err := expr.Compile(s, expr.ComponentFields)
+		Expect(err).NotTo(HaveOccurred())
+		return x.Eval(d, expr.ComponentEnv(d, key))
+	}
+
+	It("compares fields", func() {
+		Expect(eval(`type == "app"`, "web")).To(BeTrue())
+		Expect(eval(`type != "app"`, "api")).To(BeTrue())
+		Expect(eval(`status == "deprecated"`, "api")).To(BeTrue())
+		Expect(eval(`dependencies >= 1 && dependents < 1`, "web")).To(BeTrue())
+		Expect(eval(`dependencies > 0`, "api")).To(BeFalse())
+	})
+	It("matches globs", func() {
+		Expect(eval(`area matches "prod-*"`, "web")).To(BeTrue())
+		Expect(eval(`area matches "prod-*"`, "api")).To(BeFalse())
+	})
+	It("checks areas within others", func() {
+		Expect(eval(`area within "prod"`, "web")).To(BeTrue())
+		Expect(eval(`area within "prod-eu"`, "api")).To(BeFalse())
+	})
+	It("gives && precedence over ||", func() {
+		Expect(eval(`true || false && false`, "web")).To(BeTrue())
+		Expect(eval(`(true || false) && false`, "web")).To(BeFalse())
+		Expect(eval(`!false && !(name == "Api")`, "web")).To(BeTrue())
+	})
+	It("treats the zero expression as true", func() {
+		Expect(expr.Expr{}.Eval(d, expr.Env{})).To(BeTrue())
+	})
+	It("allows escapes in strings", func() {
+		d.Components["web"] = model.Component{Name: `say "hi"`}
+		Expect(eval(`name == "say \"hi\""`, "web")).To(BeTrue())
+	})
+	It("looks for values in lists and strings", func() {
+		Expect(eval(`tags contains "pci"`, "web")).To(BeTrue())
+		Expect(eval(`tags contains "pci"`, "api")).To(BeFalse())
+		Expect(eval(`name contains "eb"`, "web")).To(BeTrue())
+	})
+	It("reads any property", func() {
+		Expect(eval(`properties.tier == "1"`, "web")).To(BeTrue())
+		Expect(eval(`properties.tier == ""`, "api")).To(BeTrue())
+	})
+	It("does not compile invalid uses of lists and properties", func() {
+		for _, s := range []string{`tags == "pci"`, `tags contains 1`, `properties == "1"`, `properties. == "1"`} {
+			_, err := expr.Compile(s, expr.ComponentFields)
+			Expect(err).To(HaveOccurred(), s)
+		}
+	})
+})
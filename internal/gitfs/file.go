@@ -0,0 +1,144 @@
+package gitfs
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path"
+	"syscall"
+	"time"
+)
+
+// File is a file or directory opened from a git tree
+type File struct {
+	fs     *Fs
+	entry  *entry
+	name   string
+	reader *bytes.Reader
+	offset int
+}
+
+func (f *File) Name() string {
+	return f.name
+}
+
+func (f *File) Stat() (os.FileInfo, error) {
+	return f.fs.info(f.entry), nil
+}
+
+func (f *File) Close() error {
+	return nil
+}
+
+func (f *File) Read(p []byte) (int, error) {
+	if f.entry.isDir {
+		return 0, &os.PathError{Op: "read", Path: f.name, Err: syscall.EISDIR}
+	}
+
+	return f.reader.Read(p)
+}
+
+func (f *File) ReadAt(p []byte, off int64) (int, error) {
+	if f.entry.isDir {
+		return 0, &os.PathError{Op: "read", Path: f.name, Err: syscall.EISDIR}
+	}
+
+	return f.reader.ReadAt(p, off)
+}
+
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	if f.entry.isDir {
+		return 0, &os.PathError{Op: "seek", Path: f.name, Err: syscall.EISDIR}
+	}
+
+	return f.reader.Seek(offset, whence)
+}
+
+// Readdir returns the entries in the directory, continuing from where the last call left off
+func (f *File) Readdir(count int) ([]os.FileInfo, error) {
+	if !f.entry.isDir {
+		return nil, &os.PathError{Op: "readdir", Path: f.name, Err: syscall.ENOTDIR}
+	}
+
+	children := f.entry.children[f.offset:]
+	if count > 0 {
+		if len(children) == 0 {
+			return nil, io.EOF
+		}
+		if len(children) > count {
+			children = children[:count]
+		}
+	}
+	f.offset += len(children)
+
+	infos := make([]os.FileInfo, 0, len(children))
+	for _, c := range children {
+		infos = append(infos, f.fs.info(f.fs.entries[c]))
+	}
+
+	return infos, nil
+}
+
+func (f *File) Readdirnames(n int) ([]string, error) {
+	infos, err := f.Readdir(n)
+
+	names := make([]string, 0, len(infos))
+	for _, i := range infos {
+		names = append(names, i.Name())
+	}
+
+	return names, err
+}
+
+func (f *File) Write(p []byte) (int, error) {
+	return 0, &os.PathError{Op: "write", Path: f.name, Err: syscall.EPERM}
+}
+
+func (f *File) WriteAt(p []byte, off int64) (int, error) {
+	return 0, &os.PathError{Op: "write", Path: f.name, Err: syscall.EPERM}
+}
+
+func (f *File) WriteString(s string) (int, error) {
+	return 0, &os.PathError{Op: "write", Path: f.name, Err: syscall.EPERM}
+}
+
+func (f *File) Sync() error {
+	return nil
+}
+
+func (f *File) Truncate(size int64) error {
+	return &os.PathError{Op: "truncate", Path: f.name, Err: syscall.EPERM}
+}
+
+type fileInfo struct {
+	entry   *entry
+	modTime time.Time
+}
+
+func (i fileInfo) Name() string {
+	return path.Base(i.entry.name)
+}
+
+func (i fileInfo) Size() int64 {
+	return i.entry.size
+}
+
+func (i fileInfo) Mode() os.FileMode {
+	if i.entry.isDir {
+		return os.ModeDir | 0555
+	}
+
+	return 0444
+}
+
+func (i fileInfo) ModTime() time.Time {
+	return i.modTime
+}
+
+func (i fileInfo) IsDir() bool {
+	return i.entry.isDir
+}
+
+func (i fileInfo) Sys() interface{} {
+	return nil
+}
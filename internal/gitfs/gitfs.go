@@ -0,0 +1,321 @@
+package gitfs
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// Fs is a read-only afero.Fs over the tree of a single commit in a local git repository
+// files are read straight from the object database so the working copy is never touched
+//
+// the tree is mounted at the repository's top level directory, so absolute paths into the
+// working copy resolve to the same files at the revision, and relative paths are resolved
+// from the top level directory
+type Fs struct {
+	root    string
+	prefix  string
+	commit  string
+	modTime time.Time
+	entries map[string]*entry
+}
+
+type entry struct {
+	name     string
+	object   string
+	size     int64
+	isDir    bool
+	children []string
+}
+
+// New opens the revision of the repository containing dir
+// rev can be anything git understands as a commit: a branch, a tag, a sha, HEAD~2 and so on
+// dir does not have to exist in the working copy, so a directory that was moved or deleted since rev can still be
+// read, the repository is found from the closest of its parents that does exist
+func New(dir string, rev string) (*Fs, error) {
+	existing, missing, err := splitExisting(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	top, err := git(existing, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	if top, err = filepath.EvalSymlinks(top); err != nil {
+		return nil, err
+	}
+
+	prefix, err := filepath.Rel(top, filepath.Join(existing, missing))
+	if err != nil || prefix == ".." || strings.HasPrefix(prefix, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("'%s' is not in the repository at '%s'", dir, top)
+	}
+
+	commit, err := git(top, "rev-parse", "--verify", "--end-of-options", rev+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("unknown revision '%s': %s", rev, err)
+	}
+
+	ts, err := git(top, "show", "-s", "--format=%ct", commit)
+	if err != nil {
+		return nil, err
+	}
+	secs, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	fs := &Fs{
+		root:    filepath.Clean(top),
+		prefix:  path.Clean(filepath.ToSlash(prefix)),
+		commit:  commit,
+		modTime: time.Unix(secs, 0),
+		entries: map[string]*entry{
+			".": {name: ".", isDir: true},
+		},
+	}
+
+	if err := fs.readTree(); err != nil {
+		return nil, err
+	}
+
+	return fs, nil
+}
+
+// splitExisting splits dir into its closest parent that exists, with symlinks resolved, and the rest of the path below
+// it that does not exist
+func splitExisting(dir string) (string, string, error) {
+	existing, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	missing := ""
+	for {
+		if _, err := os.Stat(existing); err == nil {
+			break
+		}
+
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return "", "", fmt.Errorf("'%s' is not in a repository", dir)
+		}
+		missing = filepath.Join(filepath.Base(existing), missing)
+		existing = parent
+	}
+
+	existing, err = filepath.EvalSymlinks(existing)
+	if err != nil {
+		return "", "", err
+	}
+
+	return existing, missing, nil
+}
+
+// Commit returns the full sha of the commit the filesystem reads from
+func (fs *Fs) Commit() string {
+	return fs.commit
+}
+
+// Prefix returns the directory passed to New relative to the top level of the repository
+// it is the path to read from when loading the files at that directory
+func (fs *Fs) Prefix() string {
+	return fs.prefix
+}
+
+// Root returns the directory the tree is mounted at
+func (fs *Fs) Root() string {
+	return fs.root
+}
+
+// readTree lists every file and directory in the commit
+// each line of ls-tree -l looks like "<mode> <type> <object> <size>\t<path>"
+func (fs *Fs) readTree() error {
+	out, err := git(fs.root, "ls-tree", "-r", "-t", "-l", "-z", fs.commit)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(out, "\x00") {
+		if line == "" {
+			continue
+		}
+
+		tab := strings.IndexByte(line, '\t')
+		if tab < 0 {
+			return fmt.Errorf("unexpected ls-tree output '%s'", line)
+		}
+		fields := strings.Fields(line[:tab])
+		p := line[tab+1:]
+		if len(fields) != 4 {
+			return fmt.Errorf("unexpected ls-tree output '%s'", line)
+		}
+
+		e := &entry{name: path.Base(p), object: fields[2]}
+		switch fields[1] {
+		case "tree":
+			e.isDir = true
+		case "blob":
+			if e.size, err = strconv.ParseInt(fields[3], 10, 64); err != nil {
+				return err
+			}
+		default:
+			// submodules are not part of this repository's objects
+			continue
+		}
+
+		fs.entries[p] = e
+	}
+
+	// link every entry to its parent directory
+	for p := range fs.entries {
+		if p == "." {
+			continue
+		}
+		parent := fs.entries[path.Dir(p)]
+		parent.children = append(parent.children, p)
+	}
+	for _, e := range fs.entries {
+		sort.Strings(e.children)
+	}
+
+	return nil
+}
+
+// lookup converts a name to its path within the tree and returns the entry for it
+func (fs *Fs) lookup(op string, name string) (*entry, error) {
+	p := filepath.Clean(name)
+	if filepath.IsAbs(p) {
+		rel, err := filepath.Rel(fs.root, p)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+			return nil, &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+		}
+		p = rel
+	}
+
+	e, exists := fs.entries[filepath.ToSlash(p)]
+	if !exists {
+		return nil, &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+	}
+
+	return e, nil
+}
+
+func (fs *Fs) readBlob(e *entry) ([]byte, error) {
+	cmd := exec.Command("git", "cat-file", "blob", e.object)
+	cmd.Dir = fs.root
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file %s: %s", e.object, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
+
+func (fs *Fs) Open(name string) (afero.File, error) {
+	e, err := fs.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &File{fs: fs, entry: e, name: name}
+	if !e.isDir {
+		data, err := fs.readBlob(e)
+		if err != nil {
+			return nil, &os.PathError{Op: "open", Path: name, Err: err}
+		}
+		f.reader = bytes.NewReader(data)
+	}
+
+	return f, nil
+}
+
+func (fs *Fs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_APPEND|os.O_CREATE|os.O_TRUNC) != 0 {
+		return nil, &os.PathError{Op: "open", Path: name, Err: syscall.EPERM}
+	}
+
+	return fs.Open(name)
+}
+
+func (fs *Fs) Stat(name string) (os.FileInfo, error) {
+	e, err := fs.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.info(e), nil
+}
+
+func (fs *Fs) Name() string {
+	return "gitfs"
+}
+
+func (fs *Fs) Create(name string) (afero.File, error) {
+	return nil, &os.PathError{Op: "create", Path: name, Err: syscall.EPERM}
+}
+
+func (fs *Fs) Mkdir(name string, perm os.FileMode) error {
+	return &os.PathError{Op: "mkdir", Path: name, Err: syscall.EPERM}
+}
+
+func (fs *Fs) MkdirAll(path string, perm os.FileMode) error {
+	return &os.PathError{Op: "mkdir", Path: path, Err: syscall.EPERM}
+}
+
+func (fs *Fs) Remove(name string) error {
+	return &os.PathError{Op: "remove", Path: name, Err: syscall.EPERM}
+}
+
+func (fs *Fs) RemoveAll(path string) error {
+	return &os.PathError{Op: "remove", Path: path, Err: syscall.EPERM}
+}
+
+func (fs *Fs) Rename(oldname string, newname string) error {
+	return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: syscall.EPERM}
+}
+
+func (fs *Fs) Chmod(name string, mode os.FileMode) error {
+	return &os.PathError{Op: "chmod", Path: name, Err: syscall.EPERM}
+}
+
+func (fs *Fs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	return &os.PathError{Op: "chtimes", Path: name, Err: syscall.EPERM}
+}
+
+func (fs *Fs) info(e *entry) os.FileInfo {
+	return fileInfo{entry: e, modTime: fs.modTime}
+}
+
+// git runs a git command in dir and returns its trimmed output
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
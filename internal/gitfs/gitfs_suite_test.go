@@ -0,0 +1,13 @@
+package gitfs_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestGitfs(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Gitfs Suite")
+}
@@ -0,0 +1,202 @@
+package gitfs_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/abramsimon/gomponere/internal/gitfs"
+	"github.com/abramsimon/gomponere/internal/input"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+var _ = Describe("Fs", func() {
+	var (
+		err  error
+		repo string
+		fs   *gitfs.Fs
+	)
+
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@test.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@test.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			Fail(string(out))
+		}
+	}
+
+	write := func(name string, content string) {
+		p := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+			Fail(err.Error())
+		}
+		if err := ioutil.WriteFile(p, []byte(content), os.ModePerm); err != nil {
+			Fail(err.Error())
+		}
+	}
+
+	BeforeEach(func() {
+		if _, err := exec.LookPath("git"); err != nil {
+			Skip("git is not installed")
+		}
+
+		if repo, err = ioutil.TempDir("", "gitfs"); err != nil {
+			Fail(err.Error())
+		}
+		if repo, err = filepath.EvalSymlinks(repo); err != nil {
+			Fail(err.Error())
+		}
+
+		run("init", "-q")
+		write("arch/areas.yaml", "areas:\n  area-1:\n    name: The First Area\n")
+		write("arch/nested/components.yaml", "components:\n  component-1:\n    name: The First Component\n")
+		write("README.md", "not part of the model")
+		run("add", "-A")
+		run("commit", "-q", "-m", "first")
+		run("tag", "v1")
+
+		write("arch/areas.yaml", "areas:\n  area-1:\n    name: The Renamed Area\n")
+		run("commit", "-q", "-am", "second")
+
+		// uncommitted changes in the working copy must never be read
+		write("arch/areas.yaml", "this is not yaml: [")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(repo)
+	})
+
+	Describe("New", func() {
+		Context("with an unknown revision", func() {
+			It("does error", func() {
+				_, err = gitfs.New(repo, "nope")
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		Context("outside of a repository", func() {
+			It("does error", func() {
+				_, err = gitfs.New(os.TempDir(), "HEAD")
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		Context("from a sub directory", func() {
+			It("knows the prefix", func() {
+				fs, err = gitfs.New(filepath.Join(repo, "arch"), "v1")
+				Expect(err).To(BeNil())
+				Expect(fs.Prefix()).To(Equal("arch"))
+				Expect(fs.Root()).To(Equal(repo))
+				Expect(fs.Commit()).To(HaveLen(40))
+			})
+		})
+
+		Context("from a directory that is gone from the working copy", func() {
+			BeforeEach(func() {
+				run("mv", "arch", "model")
+				run("commit", "-q", "-m", "third")
+			})
+
+			It("reads it at the revision", func() {
+				fs, err = gitfs.New(filepath.Join(repo, "arch", "nested"), "v1")
+				Expect(err).To(BeNil())
+				Expect(fs.Prefix()).To(Equal("arch/nested"))
+				Expect(fs.Root()).To(Equal(repo))
+
+				b, err := afero.ReadFile(fs, filepath.Join(fs.Prefix(), "components.yaml"))
+				Expect(err).To(BeNil())
+				Expect(string(b)).To(ContainSubstring("The First Component"))
+			})
+		})
+	})
+
+	Context("with a revision", func() {
+		BeforeEach(func() {
+			if fs, err = gitfs.New(repo, "v1"); err != nil {
+				Fail(err.Error())
+			}
+		})
+
+		It("stats files and directories", func() {
+			info, err := fs.Stat("arch/areas.yaml")
+			Expect(err).To(BeNil())
+			Expect(info.IsDir()).To(BeFalse())
+			Expect(info.Name()).To(Equal("areas.yaml"))
+
+			info, err = fs.Stat(filepath.Join(repo, "arch", "nested"))
+			Expect(err).To(BeNil())
+			Expect(info.IsDir()).To(BeTrue())
+		})
+		It("does not find missing files", func() {
+			_, err := fs.Stat("arch/missing.yaml")
+			Expect(os.IsNotExist(err)).To(BeTrue())
+
+			_, err = fs.Open(filepath.Join(os.TempDir(), "elsewhere"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+		It("reads files as they were at the revision", func() {
+			b, err := afero.ReadFile(fs, "arch/areas.yaml")
+			Expect(err).To(BeNil())
+			Expect(string(b)).To(ContainSubstring("The First Area"))
+		})
+		It("reads directories", func() {
+			f, err := fs.Open("arch")
+			Expect(err).To(BeNil())
+			names, err := f.Readdirnames(-1)
+			Expect(err).To(BeNil())
+			Expect(names).To(Equal([]string{"areas.yaml", "nested"}))
+		})
+		It("is read only", func() {
+			Expect(afero.WriteFile(fs, "arch/areas.yaml", []byte("x"), os.ModePerm)).ToNot(BeNil())
+			Expect(fs.Remove("arch/areas.yaml")).ToNot(BeNil())
+			Expect(fs.Mkdir("arch/new", os.ModePerm)).ToNot(BeNil())
+		})
+		It("can be loaded", func() {
+			d, err := input.Load(fs, "arch")
+			Expect(err).To(BeNil())
+			Expect(d.Areas["area-1"].Name).To(Equal("The First Area"))
+			Expect(d.Components).To(HaveLen(1))
+		})
+	})
+
+	Context("with a later revision", func() {
+		BeforeEach(func() {
+			if fs, err = gitfs.New(repo, "HEAD"); err != nil {
+				Fail(err.Error())
+			}
+		})
+
+		It("reads the later files", func() {
+			d, err := input.Load(fs, filepath.Join(repo, "arch"))
+			Expect(err).To(BeNil())
+			Expect(d.Areas["area-1"].Name).To(Equal("The Renamed Area"))
+		})
+	})
+
+	Describe("Log", func() {
+		It("lists the commits touching the directory, oldest first", func() {
+			commits, err := gitfs.Log(filepath.Join(repo, "arch"))
+			Expect(err).To(BeNil())
+			Expect(commits).To(HaveLen(2))
+			Expect(commits[0].Subject).To(Equal("first"))
+			Expect(commits[0].Author).To(Equal("test"))
+			Expect(commits[0].Short()).To(HaveLen(7))
+			Expect(commits[1].Subject).To(Equal("second"))
+		})
+		It("skips commits that did not touch the directory", func() {
+			run("checkout", "-q", "--", "arch")
+			write("README.md", "still not part of the model")
+			run("commit", "-q", "-am", "third")
+
+			commits, err := gitfs.Log(filepath.Join(repo, "arch"))
+			Expect(err).To(BeNil())
+			Expect(commits).To(HaveLen(2))
+		})
+	})
+})
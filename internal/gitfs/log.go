@@ -0,0 +1,67 @@
+package gitfs
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commit is a single commit from the history of a repository
+type Commit struct {
+	Hash    string    `json:"hash"`
+	Time    time.Time `json:"time"`
+	Author  string    `json:"author"`
+	Subject string    `json:"subject"`
+}
+
+// Short returns the abbreviated hash of the commit
+func (c Commit) Short() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+
+	return c.Hash
+}
+
+// Log returns the commits that touched dir, oldest first
+// like New, dir does not have to exist in the working copy, so the history of a deleted directory can still be read
+func Log(dir string) ([]Commit, error) {
+	existing, missing, err := splitExisting(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := git(existing, "log", "--reverse", "--format=%H%x1f%ct%x1f%an%x1f%s%x1e", "--", filepath.Join(".", missing))
+	if err != nil {
+		return nil, err
+	}
+
+	commits := []Commit{}
+	for _, record := range strings.Split(out, "\x1e") {
+		record = strings.TrimSpace(record)
+		if record == "" {
+			continue
+		}
+
+		fields := strings.Split(record, "\x1f")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("unexpected git log output '%s'", record)
+		}
+
+		secs, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		commits = append(commits, Commit{
+			Hash:    fields[0],
+			Time:    time.Unix(secs, 0).UTC(),
+			Author:  fields[2],
+			Subject: fields[3],
+		})
+	}
+
+	return commits, nil
+}
@@ -0,0 +1,123 @@
+package history
+
+import (
+	"sort"
+
+	"github.com/abramsimon/gomponere/internal/diff"
+	"github.com/abramsimon/gomponere/internal/gitfs"
+	"github.com/abramsimon/gomponere/internal/input"
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/spf13/afero"
+)
+
+// Snapshot is the model as it was at a single commit, along with what changed since the previous snapshot
+type Snapshot struct {
+	Commit gitfs.Commit `json:"commit"`
+	Diff   diff.Diff    `json:"diff"`
+
+	// Error is set when the model could not be loaded at this commit, the snapshot is otherwise empty
+	Error string `json:"error,omitempty"`
+
+	Diagram  model.Diagram `json:"-"`
+	Previous model.Diagram `json:"-"`
+}
+
+// Lifetime records when a component first appeared, when it last changed and when it disappeared
+// changes to its dependencies and team count as changes, but are only told apart in the diffs of the snapshots
+type Lifetime struct {
+	Key         string         `json:"key"`
+	Name        string         `json:"name"`
+	Appeared    gitfs.Commit   `json:"appeared"`
+	Changed     []gitfs.Commit `json:"changed,omitempty"`
+	Disappeared *gitfs.Commit  `json:"disappeared,omitempty"`
+}
+
+type Timeline struct {
+	Snapshots  []Snapshot `json:"snapshots"`
+	Components []Lifetime `json:"components"`
+}
+
+// Loader loads the model as it was at the given commit
+type Loader func(commit gitfs.Commit) (model.Diagram, error)
+
+// GitLoader loads the model from dir in the local git repository
+// a commit where dir does not exist, like the one deleting it, has an empty model, so everything in it disappears
+func GitLoader(dir string) Loader {
+	return func(c gitfs.Commit) (model.Diagram, error) {
+		fs, err := gitfs.New(dir, c.Hash)
+		if err != nil {
+			return model.Diagram{}, err
+		}
+
+		exists, err := afero.DirExists(fs, fs.Prefix())
+		if err != nil {
+			return model.Diagram{}, err
+		}
+		if !exists {
+			return model.Diagram{}, nil
+		}
+
+		return input.Load(fs, fs.Prefix())
+	}
+}
+
+// Build loads the model at each commit, oldest first, and records what changed between them
+// commits where the model cannot be loaded are kept in the timeline with their error and otherwise skipped
+func Build(commits []gitfs.Commit, load Loader) Timeline {
+	t := Timeline{
+		Snapshots:  []Snapshot{},
+		Components: []Lifetime{},
+	}
+
+	lifetimes := map[string]*Lifetime{}
+
+	previous := model.Diagram{}
+	for _, c := range commits {
+		d, err := load(c)
+		if err != nil {
+			t.Snapshots = append(t.Snapshots, Snapshot{Commit: c, Error: err.Error()})
+			continue
+		}
+
+		s := Snapshot{
+			Commit:   c,
+			Diff:     diff.Compare(previous, d),
+			Diagram:  d,
+			Previous: previous,
+		}
+
+		for _, ch := range s.Diff.Components {
+			switch ch.Kind {
+			case diff.Added:
+				// a component can come back after being removed, it keeps its original appearance
+				if l, exists := lifetimes[ch.Key]; exists {
+					l.Name = ch.Name
+					l.Disappeared = nil
+					l.Changed = append(l.Changed, c)
+				} else {
+					lifetimes[ch.Key] = &Lifetime{Key: ch.Key, Name: ch.Name, Appeared: c}
+				}
+			case diff.Modified:
+				lifetimes[ch.Key].Name = ch.Name
+				lifetimes[ch.Key].Changed = append(lifetimes[ch.Key].Changed, c)
+			case diff.Removed:
+				removed := c
+				lifetimes[ch.Key].Disappeared = &removed
+			}
+		}
+
+		t.Snapshots = append(t.Snapshots, s)
+		previous = d
+	}
+
+	keys := make([]string, 0, len(lifetimes))
+	for k := range lifetimes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		t.Components = append(t.Components, *lifetimes[k])
+	}
+
+	return t
+}
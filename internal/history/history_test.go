@@ -0,0 +1,246 @@
+package history_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/abramsimon/gomponere/internal/diff"
+	"github.com/abramsimon/gomponere/internal/gitfs"
+	"github.com/abramsimon/gomponere/internal/history"
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+var _ = Describe("History", func() {
+	var (
+		commits  []gitfs.Commit
+		diagrams map[string]model.Diagram
+		timeline history.Timeline
+	)
+
+	commit := func(i int, subject string) gitfs.Commit {
+		return gitfs.Commit{
+			Hash:    fmt.Sprintf("%d%039d", i, 0),
+			Time:    time.Date(2019, 1, i, 0, 0, 0, 0, time.UTC),
+			Author:  "The Author",
+			Subject: subject,
+		}
+	}
+
+	load := func(c gitfs.Commit) (model.Diagram, error) {
+		d, exists := diagrams[c.Hash]
+		if !exists {
+			return model.Diagram{}, fmt.Errorf("broken yaml")
+		}
+		return d, nil
+	}
+
+	BeforeEach(func() {
+		commits = []gitfs.Commit{
+			commit(1, "first"),
+			commit(2, "broken"),
+			commit(3, "second"),
+			commit(4, "third"),
+		}
+		diagrams = map[string]model.Diagram{
+			commits[0].Hash: {
+				Components: map[string]model.Component{
+					"web": {Name: "Web", TeamKey: "a", DependencyKeys: []string{"api"}},
+					"api": {Name: "Api", TeamKey: "a"},
+				},
+			},
+			commits[2].Hash: {
+				Components: map[string]model.Component{
+					"web": {Name: "Web", TeamKey: "b", DependencyKeys: []string{"db"}},
+					"db":  {Name: "Database"},
+				},
+			},
+			commits[3].Hash: {
+				Components: map[string]model.Component{
+					"web": {Name: "Web", TeamKey: "b", DependencyKeys: []string{"db"}},
+					"db":  {Name: "Database"},
+					"api": {Name: "Api Again"},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		timeline = history.Build(commits, load)
+	})
+
+	Describe("Build", func() {
+		It("has a snapshot per commit", func() {
+			Expect(timeline.Snapshots).To(HaveLen(4))
+		})
+		It("keeps commits that could not be loaded", func() {
+			Expect(timeline.Snapshots[1].Error).To(Equal("broken yaml"))
+		})
+		It("compares with the last loaded commit", func() {
+			Expect(timeline.Snapshots[2].Diff.Components).To(Equal([]diff.Change{
+				{Kind: diff.Removed, Key: "api", Name: "Api"},
+				{Kind: diff.Added, Key: "db", Name: "Database"},
+				{Kind: diff.Modified, Key: "web", Name: "Web", Fields: []string{"team"}},
+			}))
+		})
+		It("records component lifetimes", func() {
+			Expect(timeline.Components).To(HaveLen(3))
+
+			api := timeline.Components[0]
+			Expect(api.Key).To(Equal("api"))
+			Expect(api.Appeared.Subject).To(Equal("first"))
+			Expect(api.Disappeared).To(BeNil())
+			Expect(api.Changed).To(HaveLen(1))
+			Expect(api.Name).To(Equal("Api Again"))
+
+			web := timeline.Components[2]
+			Expect(web.Changed).To(HaveLen(1))
+			Expect(web.Changed[0].Subject).To(Equal("second"))
+		})
+
+		Context("when a component is removed", func() {
+			BeforeEach(func() {
+				commits = commits[:3]
+			})
+
+			It("records when it disappeared", func() {
+				Expect(timeline.Components[0].Disappeared).ToNot(BeNil())
+				Expect(timeline.Components[0].Disappeared.Subject).To(Equal("second"))
+			})
+		})
+	})
+
+	Describe("WriteMarkdown", func() {
+		var (
+			err error
+			buf *bytes.Buffer
+		)
+
+		BeforeEach(func() {
+			buf = &bytes.Buffer{}
+		})
+
+		JustBeforeEach(func() {
+			err = history.WriteMarkdown(buf, timeline, func(s history.Snapshot) string {
+				return s.Commit.Short() + ".dot"
+			})
+		})
+
+		It("does not error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("writes the newest commit first", func() {
+			Expect(buf.String()).To(MatchRegexp(`(?s)third.*second.*broken.*first`))
+		})
+		It("describes the changes", func() {
+			Expect(buf.String()).To(ContainSubstring("- added component `db` (Database)\n"))
+			Expect(buf.String()).To(ContainSubstring("- removed component `api` (Api)\n"))
+			Expect(buf.String()).To(ContainSubstring("- moved component `web` from team `a` to team `b`\n"))
+			Expect(buf.String()).To(ContainSubstring("- added dependency `web` → `db`\n"))
+			Expect(buf.String()).To(ContainSubstring("- unable to load the model: broken yaml\n"))
+		})
+		It("links the diagrams", func() {
+			Expect(buf.String()).To(ContainSubstring("[diagram](3000000.dot)"))
+		})
+		It("lists the component lifetimes", func() {
+			Expect(buf.String()).To(ContainSubstring("| `db` Database | 2019-01-03 (3000000) |"))
+		})
+	})
+
+	Describe("WritePages", func() {
+		var (
+			err   error
+			fs    afero.Fs
+			pages map[string]string
+		)
+
+		BeforeEach(func() {
+			fs = afero.NewMemMapFs()
+		})
+
+		JustBeforeEach(func() {
+			pages, err = history.WritePages(fs, "/out", timeline)
+		})
+
+		It("does not error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("writes a page per loaded commit", func() {
+			Expect(pages).To(HaveLen(3))
+			Expect(pages[commits[0].Hash]).To(Equal("0001-1000000.dot"))
+			Expect(pages[commits[3].Hash]).To(Equal("0004-4000000.dot"))
+
+			b, err := afero.ReadFile(fs, "/out/0003-3000000.dot")
+			Expect(err).To(BeNil())
+			Expect(string(b)).To(ContainSubstring("digraph"))
+		})
+	})
+})
+
+var _ = Describe("GitLoader", func() {
+	var (
+		repo     string
+		timeline history.Timeline
+	)
+
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@test.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@test.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			Fail(string(out))
+		}
+	}
+
+	BeforeEach(func() {
+		if _, err := exec.LookPath("git"); err != nil {
+			Skip("git is not installed")
+		}
+
+		var err error
+		if repo, err = ioutil.TempDir("", "history"); err != nil {
+			Fail(err.Error())
+		}
+
+		run("init", "-q")
+		if err := os.MkdirAll(filepath.Join(repo, "arch"), os.ModePerm); err != nil {
+			Fail(err.Error())
+		}
+		if err := ioutil.WriteFile(filepath.Join(repo, "arch", "components.yaml"), []byte("components:\n  web:\n    name: Web\n"), os.ModePerm); err != nil {
+			Fail(err.Error())
+		}
+		run("add", "-A")
+		run("commit", "-q", "-m", "add the model")
+		run("rm", "-q", "-r", "arch")
+		run("commit", "-q", "-m", "delete the model")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(repo)
+	})
+
+	JustBeforeEach(func() {
+		dir := filepath.Join(repo, "arch")
+		commits, err := gitfs.Log(dir)
+		Expect(err).To(BeNil())
+
+		timeline = history.Build(commits, history.GitLoader(dir))
+	})
+
+	It("records the components of a deleted directory as disappeared", func() {
+		Expect(timeline.Snapshots).To(HaveLen(2))
+		Expect(timeline.Snapshots[1].Error).To(BeEmpty())
+		Expect(timeline.Components).To(HaveLen(1))
+		Expect(timeline.Components[0].Disappeared).ToNot(BeNil())
+		Expect(timeline.Components[0].Disappeared.Subject).To(Equal("delete the model"))
+	})
+})
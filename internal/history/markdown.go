@@ -0,0 +1,108 @@
+package history
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/abramsimon/gomponere/internal/diff"
+)
+
+const dateFormat = "2006-01-02"
+
+// WriteMarkdown writes the timeline as a changelog, newest commit first, followed by the lifetime of every component
+// when pages is not nil it is used to link each commit to the diagram written for it
+func WriteMarkdown(w io.Writer, t Timeline, pages func(s Snapshot) string) error {
+	b := &strings.Builder{}
+
+	fmt.Fprintln(b, "# Architecture history")
+
+	for i := len(t.Snapshots) - 1; i >= 0; i-- {
+		s := t.Snapshots[i]
+
+		fmt.Fprintf(b, "\n## %s %s %s\n\n", s.Commit.Time.Format(dateFormat), s.Commit.Short(), s.Commit.Subject)
+		fmt.Fprintf(b, "_%s_", s.Commit.Author)
+		if pages != nil && s.Error == "" {
+			fmt.Fprintf(b, " · [diagram](%s)", pages(s))
+		}
+		fmt.Fprint(b, "\n\n")
+
+		if s.Error != "" {
+			fmt.Fprintf(b, "- unable to load the model: %s\n", s.Error)
+			continue
+		}
+		if s.Diff.Empty() {
+			fmt.Fprintln(b, "- no changes to the model")
+			continue
+		}
+
+		for _, line := range changes(s) {
+			fmt.Fprintf(b, "- %s\n", line)
+		}
+	}
+
+	if len(t.Components) > 0 {
+		fmt.Fprint(b, "\n## Components\n\n")
+		fmt.Fprintln(b, "| Component | Appeared | Last changed | Disappeared |")
+		fmt.Fprintln(b, "| --- | --- | --- | --- |")
+		for _, l := range t.Components {
+			changed, disappeared := "", ""
+			if len(l.Changed) > 0 {
+				last := l.Changed[len(l.Changed)-1]
+				changed = fmt.Sprintf("%s (%s)", last.Time.Format(dateFormat), last.Short())
+			}
+			if l.Disappeared != nil {
+				disappeared = fmt.Sprintf("%s (%s)", l.Disappeared.Time.Format(dateFormat), l.Disappeared.Short())
+			}
+			fmt.Fprintf(b, "| `%s` %s | %s (%s) | %s | %s |\n", l.Key, l.Name, l.Appeared.Time.Format(dateFormat), l.Appeared.Short(), changed, disappeared)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// changes describes each change in the snapshot as a sentence
+func changes(s Snapshot) []string {
+	lines := []string{}
+
+	describe := func(entity string, changes []diff.Change) {
+		for _, c := range changes {
+			line := fmt.Sprintf("%s %s `%s` (%s)", c.Kind, entity, c.Key, c.Name)
+			if len(c.Fields) > 0 {
+				line += ": " + strings.Join(c.Fields, ", ")
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	describe("component", s.Diff.Components)
+
+	// team assignments get their own line since that is usually what people are looking for
+	for _, c := range s.Diff.Components {
+		if c.Kind != diff.Modified {
+			continue
+		}
+		from, to := s.Previous.Components[c.Key].TeamKey, s.Diagram.Components[c.Key].TeamKey
+		if from != to {
+			lines = append(lines, fmt.Sprintf("moved component `%s` from team %s to team %s", c.Key, teamName(from), teamName(to)))
+		}
+	}
+
+	describe("team", s.Diff.Teams)
+	describe("area", s.Diff.Areas)
+
+	for _, c := range s.Diff.Dependencies {
+		lines = append(lines, fmt.Sprintf("%s dependency `%s` → `%s`", c.Kind, c.From, c.To))
+	}
+
+	return lines
+}
+
+func teamName(key string) string {
+	if key == "" {
+		return "_none_"
+	}
+
+	return "`" + key + "`"
+}
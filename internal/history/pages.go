@@ -0,0 +1,56 @@
+package history
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/abramsimon/gomponere/internal/diagram"
+	"github.com/abramsimon/gomponere/internal/diff"
+	"github.com/spf13/afero"
+)
+
+// PageName returns the file name of the diagram for the snapshot
+// pages are numbered so they sort in the order the commits were made
+func PageName(index int, s Snapshot) string {
+	return fmt.Sprintf("%04d-%s.dot", index+1, s.Commit.Short())
+}
+
+// WritePages writes a dot diagram for every snapshot that could be loaded into dir
+// each diagram highlights what changed since the previous snapshot, except for the first which is drawn plainly
+func WritePages(fs afero.Fs, dir string, t Timeline) (map[string]string, error) {
+	if err := fs.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	// names maps each commit hash to the page written for it
+	names := map[string]string{}
+
+	first := true
+	for i, s := range t.Snapshots {
+		if s.Error != "" {
+			continue
+		}
+
+		var (
+			dot string
+			err error
+		)
+		if first {
+			dot, err = diagram.MakeDot(s.Diagram)
+			first = false
+		} else {
+			dot, err = diff.MakeDot(s.Previous, s.Diagram)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := PageName(i, s)
+		if err := afero.WriteFile(fs, filepath.Join(dir, name), []byte(dot), 0644); err != nil {
+			return nil, err
+		}
+		names[s.Commit.Hash] = name
+	}
+
+	return names, nil
+}
@@ -0,0 +1,164 @@
+package importer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/abramsimon/gomponere/internal/model"
+	"gopkg.in/yaml.v2"
+)
+
+type composeFile struct {
+	Services map[string]composeService `yaml:"services"`
+}
+
+type composeService struct {
+	Image     string       `yaml:"image"`
+	DependsOn stringsOrMap `yaml:"depends_on"`
+	Links     []string     `yaml:"links"`
+	Networks  stringsOrMap `yaml:"networks"`
+}
+
+// stringsOrMap reads compose fields that can be either a list of names or a map keyed by name
+type stringsOrMap []string
+
+func (s *stringsOrMap) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var list []string
+	if err := unmarshal(&list); err == nil {
+		*s = list
+		return nil
+	}
+
+	var m map[string]interface{}
+	if err := unmarshal(&m); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	*s = keys
+
+	return nil
+}
+
+// Compose creates a component for each service in a docker-compose file
+//
+// dependencies come from depends_on and links, and from sharing a network: services on the same
+// explicitly declared network are taken to depend on any databases, caches, queues and the like on it
+// types are chosen from the image name, services that are built rather than pulled are services
+// services that would get the same key are an error
+func Compose(data []byte, opts Options) (model.Diagram, error) {
+	var f composeFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return model.Diagram{}, err
+	}
+	if len(f.Services) == 0 {
+		return model.Diagram{}, fmt.Errorf("no services found")
+	}
+
+	d := newDiagram()
+	addTeam(d, opts.teamKey())
+	addArea(d, opts.areaKey(), opts.AreaName, "")
+
+	names := make([]string, 0, len(f.Services))
+	for name := range f.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// keys maps service names to component keys, no two services may share one
+	keys := make(map[string]string, len(names))
+	services := make(map[string]string, len(names))
+	for _, name := range names {
+		k := Key(name)
+		if k == "" {
+			return model.Diagram{}, fmt.Errorf("service '%s' has no letters or digits to make a key from", name)
+		}
+		if other, exists := services[k]; exists {
+			return model.Diagram{}, fmt.Errorf("services '%s' and '%s' both have the key '%s'", other, name, k)
+		}
+		keys[name], services[k] = k, name
+	}
+
+	for _, name := range names {
+		s := f.Services[name]
+
+		t := Service
+		if s.Image != "" {
+			t = TypeForImage(s.Image)
+		}
+		addType(d, t)
+
+		deps := []string{}
+		for _, dep := range s.DependsOn {
+			deps = appendKey(deps, keys, dep)
+		}
+		for _, link := range s.Links {
+			// links are written as "service" or "service:alias"
+			deps = appendKey(deps, keys, strings.SplitN(link, ":", 2)[0])
+		}
+
+		d.Components[keys[name]] = model.Component{
+			Name:           Name(name),
+			Description:    s.Image,
+			TypeKey:        t,
+			TeamKey:        opts.teamKey(),
+			AreaKey:        opts.areaKey(),
+			DependencyKeys: deps,
+		}
+	}
+
+	// services sharing a network depend on the backing services on that network
+	for _, name := range names {
+		c := d.Components[keys[name]]
+		if c.TypeKey != Service && c.TypeKey != Proxy {
+			continue
+		}
+
+		for _, other := range names {
+			o := d.Components[keys[other]]
+			if other == name || o.TypeKey == Service || o.TypeKey == Proxy {
+				continue
+			}
+			if shareNetwork(f.Services[name].Networks, f.Services[other].Networks) {
+				c.DependencyKeys = appendKey(c.DependencyKeys, keys, other)
+			}
+		}
+
+		d.Components[keys[name]] = c
+	}
+
+	return d, nil
+}
+
+// appendKey appends the component key of the service, if the service is in the file and not already a dependency
+func appendKey(deps []string, keys map[string]string, service string) []string {
+	k, exists := keys[service]
+	if !exists {
+		return deps
+	}
+
+	for _, dk := range deps {
+		if dk == k {
+			return deps
+		}
+	}
+
+	return append(deps, k)
+}
+
+func shareNetwork(a []string, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+
+	return false
+}
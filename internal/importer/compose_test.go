@@ -0,0 +1,142 @@
+package importer_test
+
+import (
+	"github.com/abramsimon/gomponere/internal/importer"
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("Compose", func() {
+	var (
+		err  error
+		data []byte
+		opts importer.Options
+		d    model.Diagram
+	)
+
+	BeforeEach(func() {
+		opts = importer.Options{AreaKey: "shop"}
+	})
+
+	JustBeforeEach(func() {
+		d, err = importer.Compose(data, opts)
+	})
+
+	Context("with invalid yaml", func() {
+		BeforeEach(func() {
+			data = []byte("services: [")
+		})
+
+		It("does error", func() {
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("without services", func() {
+		BeforeEach(func() {
+			data = []byte("version: '3'\n")
+		})
+
+		It("does error", func() {
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("with services that get the same key", func() {
+		BeforeEach(func() {
+			data = []byte("services:\n  my_svc:\n    image: nginx\n  my-svc:\n    image: redis\n")
+		})
+
+		It("does error", func() {
+			Expect(err).To(MatchError("services 'my-svc' and 'my_svc' both have the key 'my-svc'"))
+		})
+	})
+
+	Context("with a service without letters or digits", func() {
+		BeforeEach(func() {
+			data = []byte("services:\n  ___:\n    image: nginx\n")
+		})
+
+		It("does error", func() {
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("with services", func() {
+		BeforeEach(func() {
+			data = []byte(composeFile)
+		})
+
+		It("does not error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("creates a component per service", func() {
+			Expect(d.Components).To(HaveLen(6))
+			Expect(d.Components["web"]).To(MatchFields(IgnoreExtras, Fields{
+				"Name":    Equal("Web"),
+				"TypeKey": Equal(importer.Service),
+				"TeamKey": Equal(importer.Unassigned),
+				"AreaKey": Equal("shop"),
+			}))
+		})
+		It("types components by image", func() {
+			Expect(d.Components["db"].TypeKey).To(Equal(importer.Database))
+			Expect(d.Components["db"].Description).To(Equal("postgres:12"))
+			Expect(d.Components["cache"].TypeKey).To(Equal(importer.Cache))
+			Expect(d.Components["proxy"].TypeKey).To(Equal(importer.Proxy))
+			Expect(d.Types).To(HaveKey(importer.Database))
+			Expect(d.Types).ToNot(HaveKey(importer.Search))
+		})
+		It("creates dependencies from depends_on", func() {
+			Expect(d.Components["proxy"].DependencyKeys).To(Equal([]string{"web"}))
+		})
+		It("creates dependencies from depends_on maps and links", func() {
+			Expect(d.Components["web"].DependencyKeys).To(Equal([]string{"db", "cache"}))
+		})
+		It("creates dependencies from shared networks", func() {
+			Expect(d.Components["worker"].DependencyKeys).To(Equal([]string{"queue"}))
+		})
+		It("adds the placeholder team and area", func() {
+			Expect(d.Teams).To(HaveKey(importer.Unassigned))
+			Expect(d.Areas).To(HaveKeyWithValue("shop", model.Area{Name: "Shop"}))
+		})
+	})
+})
+
+const composeFile string = `
+version: "3.8"
+services:
+  proxy:
+    image: nginx:1.19
+    depends_on:
+      - web
+  web:
+    build: ./web
+    depends_on:
+      db:
+        condition: service_healthy
+    links:
+      - cache:redis
+      - nowhere
+    networks:
+      - front
+  worker:
+    build: ./worker
+    networks:
+      - jobs
+  queue:
+    image: rabbitmq:3
+    networks:
+      jobs:
+        aliases:
+          - mq
+  db:
+    image: postgres:12
+  cache:
+    image: redis
+networks:
+  front:
+  jobs:
+`
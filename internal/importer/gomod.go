@@ -0,0 +1,230 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/spf13/afero"
+)
+
+// CodeLevel is the key of the level given to components imported from code
+const CodeLevel = "code"
+
+// goModule is what the importer needs from a go.mod file
+type goModule struct {
+	path     string
+	requires []string
+	dir      string
+}
+
+// GoModules creates a component per go module found in the repositories under root
+//
+// Only modules whose path starts with prefix are imported, and dependencies come from the requires between them.
+// Modules with the prefix that are required but not found under root are imported too, so the graph is complete. Without
+// a prefix only the modules found are imported. Indirect requires are left out, as they are not used by the module's
+// own code.
+// Each directory directly under root is taken to be a repository and becomes an area. Components are put in the code
+// level, and keyed by their module path without the prefix, so "github.com/acme/orders/api" with the prefix
+// "github.com/acme/" is "orders-api". A module whose path is the prefix itself is keyed by the last element of its
+// path, and modules that would get the same key are an error.
+func GoModules(fs afero.Fs, root string, prefix string, opts Options) (model.Diagram, error) {
+	modules := []goModule{}
+	err := afero.Walk(fs, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			name := info.Name()
+			if path != root && (name == "vendor" || name == "testdata" || name == "node_modules" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.Name() != "go.mod" {
+			return nil
+		}
+
+		m, err := readGoMod(fs, path)
+		if err != nil {
+			return err
+		}
+		if m.path != "" && strings.HasPrefix(m.path, prefix) {
+			modules = append(modules, m)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Diagram{}, err
+	}
+
+	if len(modules) == 0 {
+		return model.Diagram{}, fmt.Errorf("no go modules starting with '%s' found in '%s'", prefix, root)
+	}
+
+	d := newDiagram()
+	d.Levels = map[string]model.Level{CodeLevel: {Name: "Code"}}
+	addTeam(d, opts.teamKey())
+	addType(d, Module)
+	if opts.AreaKey != "" {
+		addArea(d, opts.AreaKey, opts.AreaName, "")
+	}
+
+	found := map[string]bool{}
+	for _, m := range modules {
+		found[m.path] = true
+	}
+
+	// every module that becomes a component gets its key up front, so two modules never end up as one component
+	keys := map[string]string{}
+	paths := map[string]string{}
+	assign := func(p string) error {
+		if _, exists := keys[p]; exists {
+			return nil
+		}
+
+		k := Key(strings.TrimPrefix(p, prefix))
+		if k == "" {
+			k = Key(path.Base(p))
+		}
+		if other, exists := paths[k]; exists {
+			return fmt.Errorf("modules '%s' and '%s' both have the key '%s'", other, p, k)
+		}
+
+		keys[p], paths[k] = k, p
+		return nil
+	}
+	for _, m := range modules {
+		if err := assign(m.path); err != nil {
+			return model.Diagram{}, err
+		}
+	}
+	if prefix != "" {
+		for _, m := range modules {
+			for _, r := range m.requires {
+				if !strings.HasPrefix(r, prefix) {
+					continue
+				}
+				if err := assign(r); err != nil {
+					return model.Diagram{}, err
+				}
+			}
+		}
+	}
+	key := func(p string) string {
+		return keys[p]
+	}
+
+	for _, m := range modules {
+		deps := []string{}
+		for _, r := range m.requires {
+			if strings.HasPrefix(r, prefix) && (prefix != "" || found[r]) && r != m.path && !contains(deps, key(r)) {
+				deps = append(deps, key(r))
+			}
+		}
+		sort.Strings(deps)
+
+		// the repository is the first directory under root
+		rel, err := filepath.Rel(root, m.dir)
+		if err != nil {
+			return model.Diagram{}, err
+		}
+		repo := strings.Split(filepath.ToSlash(rel), "/")[0]
+
+		areaKey := opts.areaKey()
+		if repo != "." {
+			areaKey = Key(repo)
+			addArea(d, areaKey, repo, opts.AreaKey)
+		} else {
+			addArea(d, areaKey, opts.AreaName, "")
+		}
+
+		d.Components[key(m.path)] = model.Component{
+			Name:           Name(key(m.path)),
+			Description:    m.path,
+			LevelKey:       CodeLevel,
+			TypeKey:        Module,
+			TeamKey:        opts.teamKey(),
+			AreaKey:        areaKey,
+			DependencyKeys: deps,
+		}
+	}
+
+	// required modules of our own that were not found still get a component, which needs a prefix to tell them apart
+	if prefix == "" {
+		return d, nil
+	}
+
+	for _, m := range modules {
+		for _, r := range m.requires {
+			k := key(r)
+			if _, exists := d.Components[k]; exists || !strings.HasPrefix(r, prefix) {
+				continue
+			}
+
+			addArea(d, opts.areaKey(), opts.AreaName, "")
+			d.Components[k] = model.Component{
+				Name:        Name(k),
+				Description: r,
+				LevelKey:    CodeLevel,
+				TypeKey:     Module,
+				TeamKey:     opts.teamKey(),
+				AreaKey:     opts.areaKey(),
+			}
+		}
+	}
+
+	return d, nil
+}
+
+// readGoMod reads the module path and the direct requires from a go.mod file
+func readGoMod(fs afero.Fs, path string) (goModule, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return goModule{}, err
+	}
+	defer f.Close()
+
+	m := goModule{dir: filepath.Dir(path)}
+
+	inRequire := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		indirect := strings.Contains(line, "// indirect")
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch {
+		case inRequire && fields[0] == ")":
+			inRequire = false
+		case inRequire:
+			if !indirect {
+				m.requires = append(m.requires, strings.Trim(fields[0], `"`))
+			}
+		case fields[0] == "module" && len(fields) > 1:
+			m.path = strings.Trim(fields[1], `"`)
+		case fields[0] == "require" && len(fields) > 1 && fields[1] == "(":
+			inRequire = true
+		case fields[0] == "require" && len(fields) > 2:
+			if !indirect {
+				m.requires = append(m.requires, strings.Trim(fields[1], `"`))
+			}
+		}
+	}
+
+	return m, scanner.Err()
+}
@@ -0,0 +1,138 @@
+package importer_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/abramsimon/gomponere/internal/importer"
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"github.com/spf13/afero"
+)
+
+var _ = Describe("GoModules", func() {
+	var (
+		err    error
+		fs     afero.Fs
+		prefix string
+		opts   importer.Options
+		d      model.Diagram
+	)
+
+	BeforeEach(func() {
+		fs = afero.NewMemMapFs()
+		prefix = "github.com/acme/"
+		opts = importer.Options{}
+	})
+
+	write := func(name string, content string) {
+		if err := afero.WriteFile(fs, filepath.Join("/repos", name), []byte(content), os.ModePerm); err != nil {
+			Fail(err.Error())
+		}
+	}
+
+	JustBeforeEach(func() {
+		d, err = importer.GoModules(fs, "/repos", prefix, opts)
+	})
+
+	Context("without modules", func() {
+		It("does error", func() {
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("with modules", func() {
+		BeforeEach(func() {
+			write("orders/go.mod", `module github.com/acme/orders
+
+go 1.22
+
+require (
+	github.com/acme/platform/log v1.2.0
+	github.com/acme/billing v0.3.0 // indirect
+	github.com/spf13/afero v1.2.2
+)
+
+require github.com/acme/payments/client v0.1.0
+`)
+			write("orders/tools/go.mod", "module github.com/acme/orders/tools\n\nrequire \"github.com/acme/orders\" v0.0.0\n")
+			write("orders/vendor/github.com/acme/platform/log/go.mod", "module github.com/acme/platform/log\n")
+			write("platform/log/go.mod", "module github.com/acme/platform/log // logging\n\nrequire github.com/sirupsen/logrus v1.9.0\n")
+			write("other/go.mod", "module example.com/other\n\nrequire github.com/acme/orders v1.0.0\n")
+			write(".cache/go.mod", "module github.com/acme/cached\n")
+		})
+
+		It("does not error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("creates a component per module with the prefix", func() {
+			Expect(d.Components).To(HaveLen(4))
+			Expect(d.Components["orders"]).To(MatchFields(IgnoreExtras, Fields{
+				"Name":        Equal("Orders"),
+				"Description": Equal("github.com/acme/orders"),
+				"LevelKey":    Equal(importer.CodeLevel),
+				"TypeKey":     Equal(importer.Module),
+				"TeamKey":     Equal(importer.Unassigned),
+			}))
+			Expect(d.Components).To(HaveKey("orders-tools"))
+			Expect(d.Components).To(HaveKey("platform-log"))
+		})
+		It("adds the code level and module type", func() {
+			Expect(d.Levels).To(HaveKey(importer.CodeLevel))
+			Expect(d.Types).To(HaveKey(importer.Module))
+		})
+		It("takes dependencies from direct requires of own modules", func() {
+			Expect(d.Components["orders"].DependencyKeys).To(Equal([]string{"payments-client", "platform-log"}))
+			Expect(d.Components["orders-tools"].DependencyKeys).To(Equal([]string{"orders"}))
+			Expect(d.Components["platform-log"].DependencyKeys).To(BeEmpty())
+		})
+		It("puts modules in an area per repository", func() {
+			Expect(d.Areas).To(HaveKeyWithValue("orders", model.Area{Name: "orders"}))
+			Expect(d.Components["orders-tools"].AreaKey).To(Equal("orders"))
+			Expect(d.Components["platform-log"].AreaKey).To(Equal("platform"))
+		})
+		It("creates components for required modules that were not found", func() {
+			Expect(d.Components["payments-client"]).To(MatchFields(IgnoreExtras, Fields{
+				"Description": Equal("github.com/acme/payments/client"),
+				"AreaKey":     Equal(importer.Unassigned),
+			}))
+		})
+
+		Context("with a module that is the prefix", func() {
+			BeforeEach(func() {
+				prefix = "github.com/acme/orders"
+			})
+
+			It("keys it by the last element of its path", func() {
+				Expect(d.Components).To(HaveKey("orders"))
+				Expect(d.Components).To(HaveKey("tools"))
+				Expect(d.Components["tools"].DependencyKeys).To(Equal([]string{"orders"}))
+			})
+		})
+
+		Context("with modules that would get the same key", func() {
+			BeforeEach(func() {
+				write("orders-tools/go.mod", "module github.com/acme/orders-tools\n")
+			})
+
+			It("does error", func() {
+				Expect(err).To(MatchError("modules 'github.com/acme/orders/tools' and 'github.com/acme/orders-tools' both have the key 'orders-tools'"))
+			})
+		})
+
+		Context("without a prefix", func() {
+			BeforeEach(func() {
+				prefix = ""
+			})
+
+			It("imports every module found", func() {
+				Expect(d.Components).To(HaveLen(4))
+				Expect(d.Components).To(HaveKey("example-com-other"))
+				Expect(d.Components["example-com-other"].DependencyKeys).To(Equal([]string{"github-com-acme-orders"}))
+				Expect(d.Components["github-com-acme-platform-log"].DependencyKeys).To(BeEmpty())
+			})
+		})
+	})
+})
@@ -0,0 +1,96 @@
+package importer
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/abramsimon/gomponere/internal/model"
+)
+
+// Unassigned is the key of the placeholder team and area given to imported components
+// it is meant to be replaced by hand once the import has been reviewed
+const Unassigned = model.Unassigned
+
+// Options control the placeholders given to imported components
+type Options struct {
+	// TeamKey is the team every imported component is given, Unassigned when empty
+	TeamKey string
+
+	// AreaKey is the area every imported component is put in when the source does not say, Unassigned when empty
+	AreaKey string
+
+	// AreaName is the name of the area when it has to be created
+	AreaName string
+}
+
+func (o Options) teamKey() string {
+	if o.TeamKey == "" {
+		return Unassigned
+	}
+
+	return o.TeamKey
+}
+
+func (o Options) areaKey() string {
+	if o.AreaKey == "" {
+		return Unassigned
+	}
+
+	return o.AreaKey
+}
+
+// newDiagram returns an empty diagram to import into
+func newDiagram() model.Diagram {
+	return model.Diagram{
+		Areas:      map[string]model.Area{},
+		Components: map[string]model.Component{},
+		Teams:      map[string]model.Team{},
+		Types:      map[string]model.Type{},
+	}
+}
+
+// addTeam adds a team with the given key to the diagram if it is not already there
+func addTeam(d model.Diagram, key string) {
+	if _, exists := d.Teams[key]; !exists {
+		d.Teams[key] = model.Team{Name: Name(key)}
+	}
+}
+
+// addArea adds an area with the given key to the diagram if it is not already there
+func addArea(d model.Diagram, key string, name string, parentKey string) {
+	if _, exists := d.Areas[key]; !exists {
+		if name == "" {
+			name = Name(key)
+		}
+		d.Areas[key] = model.Area{Name: name, ParentKey: parentKey}
+	}
+}
+
+// addType adds the type with the given key to the diagram if it is one of the known types
+func addType(d model.Diagram, key string) {
+	if t, exists := Types[key]; exists {
+		d.Types[key] = t
+	}
+}
+
+var invalidKey = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Key converts a name into a lower case, dash separated key
+func Key(name string) string {
+	return strings.Trim(invalidKey.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// Name converts a key into something more readable
+func Name(key string) string {
+	words := strings.FieldsFunc(key, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.' || r == '/'
+	})
+	for i, w := range words {
+		r, size := utf8.DecodeRuneInString(w)
+		words[i] = string(unicode.ToTitle(r)) + w[size:]
+	}
+
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,511 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/abramsimon/gomponere/internal/input"
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+)
+
+// labels that name the team owning a resource, in order of preference
+var teamLabels = []string{"team", "app.kubernetes.io/team", "owner"}
+
+// label that names the larger application a resource is part of
+const partOfLabel = "app.kubernetes.io/part-of"
+
+type k8sObject struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   k8sMetadata       `yaml:"metadata"`
+	Spec       k8sSpec           `yaml:"spec"`
+	Data       map[string]string `yaml:"data"`
+	Items      []k8sObject       `yaml:"items"`
+}
+
+type k8sMetadata struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace"`
+	Labels      map[string]string `yaml:"labels"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// k8sSpec holds the parts of the specs of every supported kind that the importer needs
+type k8sSpec struct {
+	// workloads
+	Template    k8sPodTemplate `yaml:"template"`
+	JobTemplate struct {
+		Spec struct {
+			Template k8sPodTemplate `yaml:"template"`
+		} `yaml:"spec"`
+	} `yaml:"jobTemplate"`
+
+	// services
+	Type         string        `yaml:"type"`
+	Selector     yaml.MapSlice `yaml:"selector"`
+	ExternalName string        `yaml:"externalName"`
+
+	// ingresses
+	DefaultBackend k8sBackend `yaml:"defaultBackend"`
+	Backend        k8sBackend `yaml:"backend"`
+	Rules          []struct {
+		HTTP struct {
+			Paths []struct {
+				Backend k8sBackend `yaml:"backend"`
+			} `yaml:"paths"`
+		} `yaml:"http"`
+	} `yaml:"rules"`
+}
+
+type k8sPodTemplate struct {
+	Metadata k8sMetadata `yaml:"metadata"`
+	Spec     struct {
+		Containers     []k8sContainer `yaml:"containers"`
+		InitContainers []k8sContainer `yaml:"initContainers"`
+	} `yaml:"spec"`
+}
+
+type k8sContainer struct {
+	Image string `yaml:"image"`
+	Env   []struct {
+		Name      string `yaml:"name"`
+		Value     string `yaml:"value"`
+		ValueFrom struct {
+			ConfigMapKeyRef struct {
+				Name string `yaml:"name"`
+				Key  string `yaml:"key"`
+			} `yaml:"configMapKeyRef"`
+		} `yaml:"valueFrom"`
+	} `yaml:"env"`
+	EnvFrom []struct {
+		ConfigMapRef struct {
+			Name string `yaml:"name"`
+		} `yaml:"configMapRef"`
+	} `yaml:"envFrom"`
+}
+
+// k8sBackend covers both networking.k8s.io/v1 and the older extensions/v1beta1 ingress backends
+type k8sBackend struct {
+	Service struct {
+		Name string `yaml:"name"`
+	} `yaml:"service"`
+	ServiceName string `yaml:"serviceName"`
+}
+
+func (b k8sBackend) name() string {
+	if b.Service.Name != "" {
+		return b.Service.Name
+	}
+
+	return b.ServiceName
+}
+
+// k8sWorkload is anything that runs pods
+type k8sWorkload struct {
+	object    k8sObject
+	namespace string
+	key       string
+	template  k8sPodTemplate
+}
+
+// Kubernetes creates components from a directory of kubernetes manifests
+//
+// Deployments, StatefulSets, DaemonSets, Jobs and CronJobs become components, typed by the image of their first container.
+// Ingresses become proxies depending on the workloads behind their services, and Services that select no workload,
+// like ExternalName services, become components of their own. Resources of different kinds that share a name keep
+// their own components, with the kind added to the key of the Service or Ingress.
+// Each namespace becomes an area, nested under the area in opts when it is given, and workloads labeled with
+// app.kubernetes.io/part-of are put in an area for that application within their namespace. Teams come from the
+// team, app.kubernetes.io/team or owner labels.
+// Dependencies are inferred from environment variables, including those from ConfigMaps, that reference a Service
+// by its DNS name: "name", "name.namespace", "name.namespace.svc" or "name.namespace.svc.cluster.local".
+// A bare name is only taken as a reference in a url or in a variable that looks like it holds an address.
+func Kubernetes(fs afero.Fs, root string, opts Options) (model.Diagram, error) {
+	objects, err := readK8sObjects(fs, root)
+	if err != nil {
+		return model.Diagram{}, err
+	}
+
+	d := newDiagram()
+	if opts.AreaKey != "" {
+		addArea(d, opts.AreaKey, opts.AreaName, "")
+	}
+
+	configMaps := map[string]map[string]string{}
+	services := map[string]k8sObject{}
+	workloads := []k8sWorkload{}
+	ingresses := []k8sObject{}
+	namespaces := map[string]bool{}
+
+	for _, o := range objects {
+		ns := namespace(o)
+		namespaces[ns] = true
+
+		switch o.Kind {
+		case "ConfigMap":
+			configMaps[ns+"/"+o.Metadata.Name] = o.Data
+		case "Service":
+			services[ns+"/"+o.Metadata.Name] = o
+		case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job":
+			workloads = append(workloads, k8sWorkload{object: o, namespace: ns, template: o.Spec.Template})
+		case "CronJob":
+			workloads = append(workloads, k8sWorkload{object: o, namespace: ns, template: o.Spec.JobTemplate.Spec.Template})
+		case "Ingress":
+			ingresses = append(ingresses, o)
+		}
+	}
+
+	// names used in more than one namespace get the namespace added to their key
+	namespacesByName := map[string]map[string]bool{}
+	count := func(o k8sObject) {
+		if namespacesByName[o.Metadata.Name] == nil {
+			namespacesByName[o.Metadata.Name] = map[string]bool{}
+		}
+		namespacesByName[o.Metadata.Name][namespace(o)] = true
+	}
+	for _, w := range workloads {
+		count(w.object)
+	}
+	for _, s := range services {
+		count(s)
+	}
+	for _, i := range ingresses {
+		count(i)
+	}
+	key := func(ns string, name string) string {
+		if len(namespacesByName[name]) > 1 {
+			return Key(ns + "-" + name)
+		}
+		return Key(name)
+	}
+
+	// resources of different kinds can share a name, like a Deployment with its Service and Ingress, so the ones that
+	// come later get the kind added to their key
+	taken := map[string]bool{}
+	unique := func(ns string, name string, kind string) string {
+		k := key(ns, name)
+		if taken[k] {
+			k = Key(k + "-" + kind)
+		}
+		for i, base := 2, k; taken[k]; i++ {
+			k = fmt.Sprintf("%s-%d", base, i)
+		}
+		taken[k] = true
+
+		return k
+	}
+
+	// the keys of the namespace areas, which the areas of applications must not take
+	namespaceKeys := map[string]bool{}
+	for ns := range namespaces {
+		namespaceKeys[Key(ns)] = true
+	}
+	area := func(ns string, labels map[string]string) string {
+		return area(d, opts, namespaceKeys, ns, labels)
+	}
+
+	// serviceTargets maps each service to the components it routes to
+	serviceTargets := map[string][]string{}
+
+	for i := range workloads {
+		w := &workloads[i]
+		w.key = unique(w.namespace, w.object.Metadata.Name, w.object.Kind)
+
+		for sk, s := range services {
+			if namespace(s) == w.namespace && selects(s.Spec.Selector, w.template.Metadata.Labels) {
+				serviceTargets[sk] = append(serviceTargets[sk], w.key)
+			}
+		}
+	}
+
+	serviceKeys := make([]string, 0, len(services))
+	for sk := range services {
+		serviceKeys = append(serviceKeys, sk)
+	}
+	sort.Strings(serviceKeys)
+
+	for _, sk := range serviceKeys {
+		s := services[sk]
+		if len(serviceTargets[sk]) > 0 {
+			continue
+		}
+
+		// the service is the only thing known about whatever it routes to
+		k := unique(namespace(s), s.Metadata.Name, s.Kind)
+		serviceTargets[sk] = []string{k}
+
+		description := s.Spec.ExternalName
+		t := Service
+		if description != "" {
+			t = TypeForImage(strings.Split(description, ".")[0])
+		}
+		addType(d, t)
+
+		d.Components[k] = model.Component{
+			Name:        Name(s.Metadata.Name),
+			Description: description,
+			TypeKey:     t,
+			TeamKey:     team(d, opts, s.Metadata.Labels),
+			AreaKey:     area(namespace(s), s.Metadata.Labels),
+		}
+	}
+
+	for _, w := range workloads {
+		t := Service
+		if w.object.Kind == "Job" || w.object.Kind == "CronJob" {
+			t = Job
+		} else if len(w.template.Spec.Containers) > 0 {
+			t = TypeForImage(w.template.Spec.Containers[0].Image)
+		}
+		addType(d, t)
+
+		labels := merged(w.object.Metadata.Labels, w.template.Metadata.Labels)
+
+		deps := []string{}
+		for _, sk := range referencedServices(w, configMaps, services, namespaces) {
+			for _, target := range serviceTargets[sk] {
+				if target != w.key && !contains(deps, target) {
+					deps = append(deps, target)
+				}
+			}
+		}
+		sort.Strings(deps)
+
+		description := ""
+		if len(w.template.Spec.Containers) > 0 {
+			description = w.template.Spec.Containers[0].Image
+		}
+
+		d.Components[w.key] = model.Component{
+			Name:           Name(w.object.Metadata.Name),
+			Description:    description,
+			TypeKey:        t,
+			TeamKey:        team(d, opts, labels),
+			AreaKey:        area(w.namespace, labels),
+			DependencyKeys: deps,
+		}
+	}
+
+	for _, i := range ingresses {
+		ns := namespace(i)
+
+		backends := []string{i.Spec.DefaultBackend.name(), i.Spec.Backend.name()}
+		for _, r := range i.Spec.Rules {
+			for _, p := range r.HTTP.Paths {
+				backends = append(backends, p.Backend.name())
+			}
+		}
+
+		deps := []string{}
+		for _, b := range backends {
+			for _, target := range serviceTargets[ns+"/"+b] {
+				if !contains(deps, target) {
+					deps = append(deps, target)
+				}
+			}
+		}
+		sort.Strings(deps)
+
+		addType(d, Proxy)
+		d.Components[unique(ns, i.Metadata.Name, i.Kind)] = model.Component{
+			Name:           Name(i.Metadata.Name),
+			TypeKey:        Proxy,
+			TeamKey:        team(d, opts, i.Metadata.Labels),
+			AreaKey:        area(ns, i.Metadata.Labels),
+			DependencyKeys: deps,
+		}
+	}
+
+	if len(d.Components) == 0 {
+		return model.Diagram{}, fmt.Errorf("no workloads, services or ingresses found in '%s'", root)
+	}
+
+	return d, nil
+}
+
+// readK8sObjects reads every object from every yaml file under root, unwrapping lists
+func readK8sObjects(fs afero.Fs, root string) ([]k8sObject, error) {
+	files, err := input.NewReader(fs).FindFiles(root)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []k8sObject{}
+	for _, name := range files {
+		f, err := fs.Open(name)
+		if err != nil {
+			return nil, err
+		}
+
+		dec := yaml.NewDecoder(f)
+		for {
+			var o k8sObject
+			err := dec.Decode(&o)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				f.Close()
+				return nil, fmt.Errorf("unable to read '%s': %s", name, err)
+			}
+
+			if o.Kind == "List" || strings.HasSuffix(o.Kind, "List") {
+				objects = append(objects, o.Items...)
+			} else if o.Kind != "" {
+				objects = append(objects, o)
+			}
+		}
+		f.Close()
+	}
+
+	return objects, nil
+}
+
+var (
+	// dnsToken matches anything that could be a host name
+	dnsToken = regexp.MustCompile(`[a-z0-9]([-a-z0-9.]*[a-z0-9])?`)
+
+	// addressVariable matches environment variable names that usually hold the address of another service
+	addressVariable = regexp.MustCompile(`(?i)(HOST|URL|URI|ADDR|ADDRESS|ENDPOINT|SERVER|SERVICE|DSN|BROKERS?)`)
+)
+
+// referencedServices returns the services the workload's containers reference through their environment
+func referencedServices(w k8sWorkload, configMaps map[string]map[string]string, services map[string]k8sObject, namespaces map[string]bool) []string {
+	type variable struct{ name, value string }
+	vars := []variable{}
+
+	containers := append(append([]k8sContainer{}, w.template.Spec.InitContainers...), w.template.Spec.Containers...)
+	for _, c := range containers {
+		for _, from := range c.EnvFrom {
+			data := configMaps[w.namespace+"/"+from.ConfigMapRef.Name]
+			for k, v := range data {
+				vars = append(vars, variable{k, v})
+			}
+		}
+		for _, e := range c.Env {
+			if ref := e.ValueFrom.ConfigMapKeyRef; ref.Name != "" {
+				vars = append(vars, variable{e.Name, configMaps[w.namespace+"/"+ref.Name][ref.Key]})
+			} else {
+				vars = append(vars, variable{e.Name, e.Value})
+			}
+		}
+	}
+
+	found := []string{}
+	add := func(sk string) {
+		if _, exists := services[sk]; exists && !contains(found, sk) {
+			found = append(found, sk)
+		}
+	}
+
+	for _, v := range vars {
+		value := strings.ToLower(v.value)
+		bareAllowed := strings.Contains(value, "://") || addressVariable.MatchString(v.name)
+
+		for _, token := range dnsToken.FindAllString(value, -1) {
+			parts := strings.Split(token, ".")
+			switch {
+			case len(parts) == 1:
+				if bareAllowed {
+					add(w.namespace + "/" + parts[0])
+				}
+			case namespaces[parts[1]] && (len(parts) == 2 || parts[2] == "svc"):
+				add(parts[1] + "/" + parts[0])
+			}
+		}
+	}
+	sort.Strings(found)
+
+	return found
+}
+
+// selects returns true if every label in the selector matches the labels
+func selects(selector yaml.MapSlice, labels map[string]string) bool {
+	if len(selector) == 0 {
+		return false
+	}
+
+	for _, item := range selector {
+		k, v := fmt.Sprint(item.Key), fmt.Sprint(item.Value)
+		if labels[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func namespace(o k8sObject) string {
+	if o.Metadata.Namespace == "" {
+		return "default"
+	}
+
+	return o.Metadata.Namespace
+}
+
+// team returns the key of the team named in the labels, adding it to the diagram
+func team(d model.Diagram, opts Options, labels map[string]string) string {
+	k := opts.teamKey()
+	for _, l := range teamLabels {
+		if v := labels[l]; v != "" {
+			k = Key(v)
+			break
+		}
+	}
+
+	addTeam(d, k)
+	return k
+}
+
+// area returns the key of the area for the namespace, or the application within it, adding them to the diagram
+// applications get a number added to their key when it is already the key of a namespace or of another area
+func area(d model.Diagram, opts Options, namespaceKeys map[string]bool, ns string, labels map[string]string) string {
+	k := Key(ns)
+	addArea(d, k, ns, opts.AreaKey)
+
+	if partOf := labels[partOfLabel]; partOf != "" {
+		parent, app := k, model.Area{Name: Name(partOf), ParentKey: k}
+
+		k = Key(ns + "-" + partOf)
+		for i, base := 2, k; ; i++ {
+			existing, exists := d.Areas[k]
+			if exists && existing.Name == app.Name && existing.ParentKey == app.ParentKey {
+				break
+			}
+			if !exists && !namespaceKeys[k] && k != opts.AreaKey {
+				break
+			}
+			k = fmt.Sprintf("%s-%d", base, i)
+		}
+		addArea(d, k, app.Name, parent)
+	}
+
+	return k
+}
+
+// merged returns the labels of the object with those of its pod template
+func merged(labels ...map[string]string) map[string]string {
+	m := map[string]string{}
+	for _, l := range labels {
+		for k, v := range l {
+			m[k] = v
+		}
+	}
+
+	return m
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
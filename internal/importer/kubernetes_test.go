@@ -0,0 +1,400 @@
+package importer_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/abramsimon/gomponere/internal/importer"
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"github.com/spf13/afero"
+)
+
+var _ = Describe("Kubernetes", func() {
+	var (
+		err  error
+		fs   afero.Fs
+		root string
+		opts importer.Options
+		d    model.Diagram
+	)
+
+	BeforeEach(func() {
+		fs = afero.NewMemMapFs()
+		root = "/the/manifests"
+		opts = importer.Options{}
+	})
+
+	write := func(name string, content string) {
+		if err := afero.WriteFile(fs, filepath.Join(root, name), []byte(content), os.ModePerm); err != nil {
+			Fail(err.Error())
+		}
+	}
+
+	JustBeforeEach(func() {
+		d, err = importer.Kubernetes(fs, root, opts)
+	})
+
+	Context("with an empty filesystem", func() {
+		It("does error", func() {
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("with no workloads", func() {
+		BeforeEach(func() {
+			write("cm.yaml", "kind: ConfigMap\nmetadata:\n  name: config\n")
+		})
+
+		It("does error", func() {
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("with invalid yaml", func() {
+		BeforeEach(func() {
+			write("bad.yaml", "kind: [")
+		})
+
+		It("does error", func() {
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("with manifests", func() {
+		BeforeEach(func() {
+			write("shop/web.yaml", k8sWeb)
+			write("shop/data.yaml", k8sData)
+			write("shop/ingress.yaml", k8sIngress)
+			write("ops/ops.yaml", k8sOps)
+		})
+
+		It("does not error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("creates a component per workload", func() {
+			Expect(d.Components).To(HaveKey("web"))
+			Expect(d.Components).To(HaveKey("postgres"))
+			Expect(d.Components).To(HaveKey("report"))
+			Expect(d.Components["web"]).To(MatchFields(IgnoreExtras, Fields{
+				"Name":        Equal("Web"),
+				"Description": Equal("mycompany/web:1.0"),
+				"TypeKey":     Equal(importer.Service),
+			}))
+		})
+		It("types components", func() {
+			Expect(d.Components["postgres"].TypeKey).To(Equal(importer.Database))
+			Expect(d.Components["report"].TypeKey).To(Equal(importer.Job))
+			Expect(d.Components["shop-ingress"].TypeKey).To(Equal(importer.Proxy))
+		})
+		It("creates components for services without workloads", func() {
+			Expect(d.Components["payments"]).To(MatchFields(IgnoreExtras, Fields{
+				"Description": Equal("payments.example.com"),
+			}))
+		})
+		It("maps namespaces and applications to areas", func() {
+			Expect(d.Areas).To(HaveKeyWithValue("shop", model.Area{Name: "shop"}))
+			Expect(d.Areas).To(HaveKeyWithValue("shop-storefront", model.Area{Name: "Storefront", ParentKey: "shop"}))
+			Expect(d.Components["web"].AreaKey).To(Equal("shop-storefront"))
+			Expect(d.Components["postgres"].AreaKey).To(Equal("shop"))
+		})
+		It("maps labels to teams", func() {
+			Expect(d.Components["web"].TeamKey).To(Equal("storefront-team"))
+			Expect(d.Components["postgres"].TeamKey).To(Equal(importer.Unassigned))
+			Expect(d.Teams).To(HaveKey("storefront-team"))
+			Expect(d.Teams).To(HaveKey(importer.Unassigned))
+		})
+		It("infers dependencies from environment variables", func() {
+			Expect(d.Components["web"].DependencyKeys).To(Equal([]string{"payments", "postgres", "shop-redis"}))
+		})
+		It("infers dependencies across namespaces", func() {
+			Expect(d.Components["report"].DependencyKeys).To(Equal([]string{"postgres"}))
+		})
+		It("points ingresses at workloads", func() {
+			Expect(d.Components["shop-ingress"].DependencyKeys).To(Equal([]string{"web"}))
+		})
+		It("prefixes names used in more than one namespace", func() {
+			Expect(d.Components).To(HaveKey("shop-redis"))
+			Expect(d.Components).To(HaveKey("ops-redis"))
+			Expect(d.Components).ToNot(HaveKey("redis"))
+		})
+
+		Context("with resources of different kinds sharing a name", func() {
+			BeforeEach(func() {
+				write("same-name.yaml", k8sSameName)
+			})
+
+			It("gives each its own component", func() {
+				Expect(d.Components).To(HaveKey("checkout"))
+				Expect(d.Components).To(HaveKey("checkout-ingress"))
+				Expect(d.Components["checkout"].TypeKey).ToNot(Equal(importer.Proxy))
+				Expect(d.Components).To(HaveKey("audit"))
+				Expect(d.Components).To(HaveKey("audit-service"))
+			})
+			It("does not make a component depend on itself", func() {
+				Expect(d.Components["checkout-ingress"].DependencyKeys).To(Equal([]string{"checkout"}))
+				Expect(d.Components["checkout"].DependencyKeys).To(BeEmpty())
+			})
+		})
+
+		Context("with an application named like a namespace", func() {
+			BeforeEach(func() {
+				write("collision.yaml", k8sAreaCollision)
+			})
+
+			It("gives the application an area of its own", func() {
+				Expect(d.Areas).To(HaveKeyWithValue("shop-storefront", model.Area{Name: "shop-storefront"}))
+				Expect(d.Areas).To(HaveKeyWithValue("shop-storefront-2", model.Area{Name: "Storefront", ParentKey: "shop"}))
+				Expect(d.Components["web"].AreaKey).To(Equal("shop-storefront-2"))
+				Expect(d.Components["catalog"].AreaKey).To(Equal("shop-storefront"))
+			})
+		})
+
+		Context("with a parent area", func() {
+			BeforeEach(func() {
+				opts = importer.Options{AreaKey: "cluster", TeamKey: "platform"}
+			})
+
+			It("nests the namespaces", func() {
+				Expect(d.Areas).To(HaveKey("cluster"))
+				Expect(d.Areas["shop"].ParentKey).To(Equal("cluster"))
+			})
+			It("uses the placeholder team", func() {
+				Expect(d.Components["postgres"].TeamKey).To(Equal("platform"))
+			})
+		})
+	})
+})
+
+const k8sWeb string = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+  labels:
+    team: storefront-team
+    app.kubernetes.io/part-of: storefront
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+        - name: web
+          image: mycompany/web:1.0
+          env:
+            - name: DATABASE_URL
+              value: postgres://user@db:5432/shop
+            - name: LOG_LEVEL
+              value: web
+            - name: CACHE_HOST
+              valueFrom:
+                configMapKeyRef:
+                  name: web-config
+                  key: cache
+          envFrom:
+            - configMapRef:
+                name: web-config
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: shop
+spec:
+  selector:
+    app: web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-config
+  namespace: shop
+data:
+  cache: redis
+  PAYMENTS: https://payments.shop.svc.cluster.local/api
+`
+
+const k8sData string = `
+apiVersion: v1
+kind: List
+items:
+  - apiVersion: apps/v1
+    kind: StatefulSet
+    metadata:
+      name: postgres
+      namespace: shop
+    spec:
+      template:
+        metadata:
+          labels:
+            app: postgres
+        spec:
+          containers:
+            - image: postgres:12
+  - apiVersion: v1
+    kind: Service
+    metadata:
+      name: db
+      namespace: shop
+    spec:
+      selector:
+        app: postgres
+  - apiVersion: apps/v1
+    kind: Deployment
+    metadata:
+      name: redis
+      namespace: shop
+    spec:
+      template:
+        metadata:
+          labels:
+            app: redis
+        spec:
+          containers:
+            - image: redis
+  - apiVersion: v1
+    kind: Service
+    metadata:
+      name: redis
+      namespace: shop
+    spec:
+      selector:
+        app: redis
+  - apiVersion: v1
+    kind: Service
+    metadata:
+      name: payments
+      namespace: shop
+    spec:
+      type: ExternalName
+      externalName: payments.example.com
+`
+
+const k8sIngress string = `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: shop-ingress
+  namespace: shop
+spec:
+  rules:
+    - http:
+        paths:
+          - path: /
+            backend:
+              service:
+                name: web
+`
+
+const k8sOps string = `
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: report
+  namespace: ops
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - image: mycompany/report
+              env:
+                - name: DB
+                  value: db.shop:5432
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: redis
+  namespace: ops
+spec:
+  template:
+    metadata:
+      labels:
+        app: ops-redis
+    spec:
+      containers:
+        - image: redis
+`
+
+const k8sSameName string = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: checkout
+  namespace: orders
+spec:
+  template:
+    metadata:
+      labels:
+        app: checkout
+    spec:
+      containers:
+        - name: checkout
+          image: acme/checkout:1.0
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: checkout
+  namespace: orders
+spec:
+  selector:
+    app: checkout
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: checkout
+  namespace: orders
+spec:
+  defaultBackend:
+    service:
+      name: checkout
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: audit
+  namespace: orders
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: audit
+              image: acme/audit:1.0
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: audit
+  namespace: orders
+spec:
+  type: ExternalName
+  externalName: audit.example.com
+`
+
+const k8sAreaCollision string = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: catalog
+  namespace: shop-storefront
+spec:
+  template:
+    spec:
+      containers:
+        - name: catalog
+          image: acme/catalog:1.0
+`
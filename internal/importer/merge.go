@@ -0,0 +1,125 @@
+package importer
+
+import (
+	"github.com/abramsimon/gomponere/internal/model"
+)
+
+// Merge returns the existing diagram with the imported one merged into it
+//
+// Entities that only exist in the import are added. For components in both, fields that are already set in the
+// existing diagram are kept, so anything written by hand survives, and only empty fields are filled in from the import.
+// The Unassigned placeholder never fills a team or area left empty by hand, and is dropped when nothing uses it.
+// Dependencies, tags and links found by the import are added to the ones already there, as are properties that are not
+// set yet.
+func Merge(existing model.Diagram, imported model.Diagram) model.Diagram {
+	m := model.Diagram{
+		Areas:      add(existing.Areas, imported.Areas),
+		Components: add(existing.Components, imported.Components),
+		Levels:     add(existing.Levels, imported.Levels),
+		Teams:      add(existing.Teams, imported.Teams),
+		Types:      add(existing.Types, imported.Types),
+		Styles:     existing.Styles,
+	}
+
+	for k, c := range existing.Components {
+		i, exists := imported.Components[k]
+		if !exists {
+			continue
+		}
+
+		fill(&c.Name, i.Name)
+		fill(&c.Description, i.Description)
+		fill(&c.Git, i.Git)
+		fill(&c.ReleaseDate, i.ReleaseDate)
+		fill(&c.LevelKey, i.LevelKey)
+		fill(&c.TypeKey, i.TypeKey)
+		fillKey(&c.TeamKey, i.TeamKey)
+		fillKey(&c.AreaKey, i.AreaKey)
+		fill(&c.Lifecycle.Status, i.Lifecycle.Status)
+
+		deps := append([]string{}, c.DependencyKeys...)
+		for _, dk := range i.DependencyKeys {
+			if !contains(deps, dk) {
+				deps = append(deps, dk)
+			}
+		}
+		c.DependencyKeys = deps
+		c.Tags, c.Links, c.Properties = mergeMetadata(c.Tags, c.Links, c.Properties, i.Tags, i.Links, i.Properties)
+
+		m.Components[k] = c
+	}
+
+	// the placeholders may only have been used by components that already had a team or area
+	used := map[string]bool{}
+	for _, c := range m.Components {
+		used["team/"+c.TeamKey] = true
+		used["area/"+c.AreaKey] = true
+	}
+	for _, a := range m.Areas {
+		used["area/"+a.ParentKey] = true
+	}
+	if _, declared := existing.Teams[Unassigned]; !declared && !used["team/"+Unassigned] {
+		delete(m.Teams, Unassigned)
+	}
+	if _, declared := existing.Areas[Unassigned]; !declared && !used["area/"+Unassigned] {
+		delete(m.Areas, Unassigned)
+	}
+
+	return m
+}
+
+// mergeMetadata adds the imported tags and links that are not there yet and the properties that are not set, without
+// changing the existing slices or map
+func mergeMetadata(tags []string, links []model.Link, properties map[string]string, importedTags []string, importedLinks []model.Link, importedProperties map[string]string) ([]string, []model.Link, map[string]string) {
+	mergedTags := append([]string(nil), tags...)
+	for _, t := range importedTags {
+		if !model.HasTag(mergedTags, t) {
+			mergedTags = append(mergedTags, t)
+		}
+	}
+
+	mergedLinks := append([]model.Link(nil), links...)
+	urls := map[string]bool{}
+	for _, l := range links {
+		urls[l.URL] = true
+	}
+	for _, l := range importedLinks {
+		if !urls[l.URL] {
+			mergedLinks = append(mergedLinks, l)
+			urls[l.URL] = true
+		}
+	}
+
+	if len(importedProperties) == 0 {
+		return mergedTags, mergedLinks, properties
+	}
+
+	return mergedTags, mergedLinks, add(properties, importedProperties)
+}
+
+// fillKey sets the key to the imported one when it is empty, unless the import only has the Unassigned placeholder
+func fillKey(field *string, imported string) {
+	if imported != Unassigned {
+		fill(field, imported)
+	}
+}
+
+// add returns the existing entities along with the imported ones that do not exist yet
+func add[V any](existing map[string]V, imported map[string]V) map[string]V {
+	m := make(map[string]V, len(existing)+len(imported))
+	for k, v := range imported {
+		m[k] = v
+	}
+	for k, v := range existing {
+		m[k] = v
+	}
+
+	return m
+}
+
+// fill sets the field to the imported value when it is empty
+func fill(field *string, imported string) {
+	if *field == "" {
+		*field = imported
+	}
+}
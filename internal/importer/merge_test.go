@@ -0,0 +1,90 @@
+package importer_test
+
+import (
+	"github.com/abramsimon/gomponere/internal/importer"
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Merge", func() {
+	var (
+		existing model.Diagram
+		imported model.Diagram
+		m        model.Diagram
+	)
+
+	BeforeEach(func() {
+		existing = model.Diagram{
+			Areas: map[string]model.Area{"shop": {Name: "Shop"}},
+			Components: map[string]model.Component{
+				"orders-db": {Name: "Orders Database", TeamKey: "orders", DependencyKeys: []string{"backup"}, Tags: []string{"pci"}, Properties: map[string]string{"tier": "1"}},
+				"backup":    {Name: "Backup"},
+			},
+			Teams: map[string]model.Team{"orders": {Name: "Order Squad"}},
+		}
+		imported = model.Diagram{
+			Areas: map[string]model.Area{"db": {Name: "Db"}},
+			Components: map[string]model.Component{
+				"orders-db":   {Name: "Orders Db", Description: "aws_db_instance", TypeKey: "database", TeamKey: "unassigned", AreaKey: "db", DependencyKeys: []string{"orders-keys"}, Tags: []string{"pci", "terraform"}, Links: []model.Link{{Name: "Console", URL: "https://console.example.com"}}, Properties: map[string]string{"tier": "2", "engine": "postgres"}},
+				"orders-keys": {Name: "Orders Keys", TeamKey: "unassigned"},
+				"backup":      {Name: "Backup", TeamKey: "unassigned", AreaKey: "unassigned"},
+			},
+			Teams: map[string]model.Team{"orders": {Name: "Orders"}, "unassigned": {Name: "Unassigned"}},
+			Types: map[string]model.Type{"database": {Name: "Database"}},
+		}
+	})
+
+	JustBeforeEach(func() {
+		m = importer.Merge(existing, imported)
+	})
+
+	It("adds new entities", func() {
+		Expect(m.Components).To(HaveKey("orders-keys"))
+		Expect(m.Areas).To(HaveKey("db"))
+		Expect(m.Teams).To(HaveKey("unassigned"))
+		Expect(m.Types).To(HaveKey("database"))
+	})
+	It("keeps existing entities", func() {
+		Expect(m.Components).To(HaveKey("backup"))
+		Expect(m.Areas).To(HaveKey("shop"))
+		Expect(m.Teams["orders"].Name).To(Equal("Order Squad"))
+	})
+	It("keeps fields that are already set", func() {
+		Expect(m.Components["orders-db"].Name).To(Equal("Orders Database"))
+		Expect(m.Components["orders-db"].TeamKey).To(Equal("orders"))
+	})
+	It("fills in empty fields", func() {
+		Expect(m.Components["orders-db"].Description).To(Equal("aws_db_instance"))
+		Expect(m.Components["orders-db"].TypeKey).To(Equal("database"))
+		Expect(m.Components["orders-db"].AreaKey).To(Equal("db"))
+	})
+	It("does not fill in the placeholders", func() {
+		Expect(m.Components["backup"].TeamKey).To(BeEmpty())
+		Expect(m.Components["backup"].AreaKey).To(BeEmpty())
+	})
+	It("adds imported dependencies", func() {
+		Expect(m.Components["orders-db"].DependencyKeys).To(Equal([]string{"backup", "orders-keys"}))
+	})
+	It("merges tags, links and properties", func() {
+		Expect(m.Components["orders-db"].Tags).To(Equal([]string{"pci", "terraform"}))
+		Expect(m.Components["orders-db"].Links).To(HaveLen(1))
+		Expect(m.Components["orders-db"].Properties).To(Equal(map[string]string{"tier": "1", "engine": "postgres"}))
+	})
+	It("does not change the existing diagram", func() {
+		Expect(existing.Components["orders-db"].Description).To(BeEmpty())
+		Expect(existing.Components["orders-db"].DependencyKeys).To(Equal([]string{"backup"}))
+		Expect(existing.Components["orders-db"].Properties).To(HaveLen(1))
+	})
+
+	Context("when only existing components were imported", func() {
+		BeforeEach(func() {
+			delete(imported.Components, "orders-keys")
+		})
+
+		It("drops the placeholder team", func() {
+			Expect(m.Teams).ToNot(HaveKey("unassigned"))
+			Expect(m.Teams).To(HaveKey("orders"))
+		})
+	})
+})
@@ -0,0 +1,506 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/abramsimon/gomponere/internal/model"
+)
+
+// resourceTypes maps terraform resource types to the type of component they are
+// resources of any other type, like roles, subnets and security groups, are not imported
+var resourceTypes = map[string]string{
+	// aws
+	"aws_db_instance":                   Database,
+	"aws_rds_cluster":                   Database,
+	"aws_dynamodb_table":                Database,
+	"aws_docdb_cluster":                 Database,
+	"aws_neptune_cluster":               Database,
+	"aws_redshift_cluster":              Database,
+	"aws_elasticache_cluster":           Cache,
+	"aws_elasticache_replication_group": Cache,
+	"aws_memorydb_cluster":              Cache,
+	"aws_sqs_queue":                     Queue,
+	"aws_sns_topic":                     Queue,
+	"aws_kinesis_stream":                Queue,
+	"aws_msk_cluster":                   Queue,
+	"aws_mq_broker":                     Queue,
+	"aws_opensearch_domain":             Search,
+	"aws_elasticsearch_domain":          Search,
+	"aws_s3_bucket":                     Storage,
+	"aws_efs_file_system":               Storage,
+	"aws_lb":                            Proxy,
+	"aws_alb":                           Proxy,
+	"aws_elb":                           Proxy,
+	"aws_api_gateway_rest_api":          Proxy,
+	"aws_apigatewayv2_api":              Proxy,
+	"aws_cloudfront_distribution":       Proxy,
+	"aws_lambda_function":               Service,
+	"aws_ecs_service":                   Service,
+	"aws_instance":                      Service,
+	"aws_apprunner_service":             Service,
+	"aws_elastic_beanstalk_environment": Service,
+	"aws_batch_job_definition":          Job,
+	"aws_glue_job":                      Job,
+
+	// google
+	"google_sql_database_instance":           Database,
+	"google_spanner_instance":                Database,
+	"google_bigtable_instance":               Database,
+	"google_firestore_database":              Database,
+	"google_redis_instance":                  Cache,
+	"google_pubsub_topic":                    Queue,
+	"google_storage_bucket":                  Storage,
+	"google_compute_url_map":                 Proxy,
+	"google_cloud_run_service":               Service,
+	"google_cloud_run_v2_service":            Service,
+	"google_cloudfunctions_function":         Service,
+	"google_cloudfunctions2_function":        Service,
+	"google_compute_instance":                Service,
+	"google_app_engine_standard_app_version": Service,
+	"google_cloud_run_v2_job":                Job,
+	"google_cloud_scheduler_job":             Job,
+
+	// azure
+	"azurerm_postgresql_server":          Database,
+	"azurerm_postgresql_flexible_server": Database,
+	"azurerm_mysql_server":               Database,
+	"azurerm_mysql_flexible_server":      Database,
+	"azurerm_mssql_server":               Database,
+	"azurerm_cosmosdb_account":           Database,
+	"azurerm_redis_cache":                Cache,
+	"azurerm_servicebus_queue":           Queue,
+	"azurerm_servicebus_topic":           Queue,
+	"azurerm_eventhub":                   Queue,
+	"azurerm_search_service":             Search,
+	"azurerm_storage_account":            Storage,
+	"azurerm_application_gateway":        Proxy,
+	"azurerm_api_management":             Proxy,
+	"azurerm_linux_web_app":              Service,
+	"azurerm_windows_web_app":            Service,
+	"azurerm_linux_function_app":         Service,
+	"azurerm_container_app":              Service,
+	"azurerm_linux_virtual_machine":      Service,
+}
+
+// attributes holding the name a resource is known by in the cloud, in order of preference
+var tfNameAttributes = []string{
+	"identifier",
+	"cluster_identifier",
+	"replication_group_id",
+	"cluster_id",
+	"bucket",
+	"function_name",
+	"name",
+}
+
+// resource names that say nothing about the resource, so the name of the module is used instead
+var tfGenericNames = map[string]bool{"this": true, "main": true, "default": true, "primary": true}
+
+// tags and labels that name the team owning a resource, in order of preference
+var tfTeamTags = []string{"team", "owner"}
+
+// tags and labels that name the region a resource is in
+var tfRegionTags = []string{"region"}
+
+// tfShow is the output of terraform show -json, for either a state or a plan
+type tfShow struct {
+	Values        *tfValues `json:"values"`
+	PlannedValues *tfValues `json:"planned_values"`
+	PriorState    *struct {
+		Values *tfValues `json:"values"`
+	} `json:"prior_state"`
+	Configuration struct {
+		RootModule tfConfigModule `json:"root_module"`
+	} `json:"configuration"`
+}
+
+type tfValues struct {
+	RootModule tfModule `json:"root_module"`
+}
+
+type tfModule struct {
+	Address      string       `json:"address"`
+	Resources    []tfResource `json:"resources"`
+	ChildModules []tfModule   `json:"child_modules"`
+}
+
+type tfResource struct {
+	Address   string                 `json:"address"`
+	Mode      string                 `json:"mode"`
+	Type      string                 `json:"type"`
+	Name      string                 `json:"name"`
+	Index     interface{}            `json:"index"`
+	Values    map[string]interface{} `json:"values"`
+	DependsOn []string               `json:"depends_on"`
+}
+
+type tfConfigModule struct {
+	Resources   []tfConfigResource      `json:"resources"`
+	ModuleCalls map[string]tfModuleCall `json:"module_calls"`
+	Outputs     map[string]struct {
+		Expression interface{} `json:"expression"`
+	} `json:"outputs"`
+}
+
+type tfConfigResource struct {
+	Address     string      `json:"address"`
+	Mode        string      `json:"mode"`
+	Expressions interface{} `json:"expressions"`
+	DependsOn   []string    `json:"depends_on"`
+}
+
+type tfModuleCall struct {
+	Expressions map[string]interface{} `json:"expressions"`
+	Module      tfConfigModule         `json:"module"`
+}
+
+// tfConfigScope is a module in the configuration along with the call that created it
+type tfConfigScope struct {
+	module tfConfigModule
+	parent string
+	call   tfModuleCall
+}
+
+// tfInstance is a resource that is imported as a component
+type tfInstance struct {
+	resource tfResource
+	module   string
+	config   string
+	key      string
+}
+
+// Terraform creates components from the output of terraform show -json, for either a state or a plan
+//
+// Resources become components typed by their resource type, databases, queues, buckets and so on. Resources of types
+// that are not components, like roles and security groups, are not imported, but dependencies are followed through
+// them, so an ecs service whose task definition points at a database depends on the database.
+// Resources in modules are put in an area per module, nested like the modules are. Resources in the root module are put
+// in an area for the region tag or label they have, or their region, when there is one. Teams come from the team or
+// owner tags.
+// Dependencies are taken from the depends_on recorded in the state, or from the references in the configuration of
+// a plan.
+func Terraform(data []byte, opts Options) (model.Diagram, error) {
+	var show tfShow
+	if err := json.Unmarshal(data, &show); err != nil {
+		return model.Diagram{}, err
+	}
+
+	values := show.Values
+	if values == nil {
+		values = show.PlannedValues
+	}
+	if values == nil && show.PriorState != nil {
+		values = show.PriorState.Values
+	}
+	if values == nil {
+		return model.Diagram{}, fmt.Errorf("no values or planned values found, expected the output of terraform show -json")
+	}
+
+	// the dependency graph between resources, by their address in the configuration
+	graph := map[string][]string{}
+
+	instances := []tfInstance{}
+	var walk func(m tfModule)
+	walk = func(m tfModule) {
+		module := tfConfigAddress(m.Address)
+		for _, r := range m.Resources {
+			if r.Mode != "managed" {
+				continue
+			}
+
+			config := tfJoin(module, r.Type+"."+r.Name)
+			graph[config] = append(graph[config], r.DependsOn...)
+
+			if _, exists := resourceTypes[r.Type]; exists {
+				instances = append(instances, tfInstance{resource: r, module: module, config: config})
+			}
+		}
+		for _, c := range m.ChildModules {
+			walk(c)
+		}
+	}
+	walk(values.RootModule)
+
+	for config, deps := range tfConfigDependencies(show.Configuration.RootModule) {
+		graph[config] = append(graph[config], deps...)
+	}
+
+	if len(instances) == 0 {
+		return model.Diagram{}, fmt.Errorf("no resources of a known type found")
+	}
+
+	// give every instance a key, falling back to its address when the name it is known by is taken
+	keyCount := map[string]int{}
+	for i := range instances {
+		instances[i].key = Key(tfName(instances[i]))
+		keyCount[instances[i].key]++
+	}
+	keysByConfig := map[string][]string{}
+	for i := range instances {
+		in := &instances[i]
+		if keyCount[in.key] > 1 || in.key == "" {
+			in.key = Key(in.resource.Address)
+		}
+		keysByConfig[in.config] = append(keysByConfig[in.config], in.key)
+	}
+
+	d := newDiagram()
+	if opts.AreaKey != "" {
+		addArea(d, opts.AreaKey, opts.AreaName, "")
+	}
+
+	for _, in := range instances {
+		t := resourceTypes[in.resource.Type]
+		addType(d, t)
+
+		deps := []string{}
+		for _, config := range tfReachable(graph, in.config, keysByConfig) {
+			for _, k := range keysByConfig[config] {
+				if k != in.key && !contains(deps, k) {
+					deps = append(deps, k)
+				}
+			}
+		}
+		sort.Strings(deps)
+
+		d.Components[in.key] = model.Component{
+			Name:           Name(tfName(in)),
+			Description:    in.resource.Type,
+			TypeKey:        t,
+			TeamKey:        tfTeam(d, opts, in.resource),
+			AreaKey:        tfArea(d, opts, in),
+			DependencyKeys: deps,
+		}
+	}
+
+	return d, nil
+}
+
+var tfIndex = regexp.MustCompile(`\[[^\]]*\]`)
+
+// tfConfigAddress removes the instance keys from an address, so "module.db[0]" becomes "module.db"
+func tfConfigAddress(address string) string {
+	return tfIndex.ReplaceAllString(address, "")
+}
+
+func tfJoin(module string, address string) string {
+	if module == "" {
+		return address
+	}
+
+	return module + "." + address
+}
+
+// tfName returns the name the resource is known by, its name in the cloud when the state or plan has it
+func tfName(in tfInstance) string {
+	for _, a := range tfNameAttributes {
+		if v, ok := in.resource.Values[a].(string); ok && v != "" {
+			return v
+		}
+	}
+
+	name := in.resource.Name
+	if tfGenericNames[name] && in.module != "" {
+		name = in.module[strings.LastIndex(in.module, ".")+1:]
+	}
+	if in.resource.Index != nil {
+		name = fmt.Sprintf("%s-%v", name, in.resource.Index)
+	}
+
+	return name
+}
+
+// tfTags returns the tags, or labels, of the resource
+func tfTags(r tfResource) map[string]string {
+	tags := map[string]string{}
+	for _, attr := range []string{"labels", "tags_all", "tags"} {
+		if m, ok := r.Values[attr].(map[string]interface{}); ok {
+			for k, v := range m {
+				if s, ok := v.(string); ok {
+					tags[strings.ToLower(k)] = s
+				}
+			}
+		}
+	}
+
+	return tags
+}
+
+// tfTeam returns the key of the team named in the resource's tags, adding it to the diagram
+func tfTeam(d model.Diagram, opts Options, r tfResource) string {
+	k := opts.teamKey()
+	tags := tfTags(r)
+	for _, t := range tfTeamTags {
+		if v := tags[t]; v != "" {
+			k = Key(v)
+			break
+		}
+	}
+
+	addTeam(d, k)
+	return k
+}
+
+// tfArea returns the key of the area for the module the resource is in, or for its region, adding it to the diagram
+func tfArea(d model.Diagram, opts Options, in tfInstance) string {
+	if in.module != "" {
+		parent, k := opts.AreaKey, ""
+		for _, name := range strings.Split(strings.TrimPrefix(in.module, "module."), ".module.") {
+			k = Key(strings.TrimPrefix(k+"-"+name, "-"))
+			addArea(d, k, Name(name), parent)
+			parent = k
+		}
+
+		return k
+	}
+
+	region := ""
+	tags := tfTags(in.resource)
+	for _, t := range tfRegionTags {
+		if v := tags[t]; v != "" {
+			region = v
+			break
+		}
+	}
+	if v, ok := in.resource.Values["region"].(string); ok && region == "" {
+		region = v
+	}
+
+	if region == "" {
+		addArea(d, opts.areaKey(), opts.AreaName, "")
+		return opts.areaKey()
+	}
+
+	k := Key(region)
+	addArea(d, k, region, opts.AreaKey)
+	return k
+}
+
+// tfReachable returns the imported resources the resource depends on, following dependencies through
+// resources that are not imported
+func tfReachable(graph map[string][]string, from string, imported map[string][]string) []string {
+	found := []string{}
+	seen := map[string]bool{from: true}
+
+	var visit func(config string)
+	visit = func(config string) {
+		for _, dep := range graph[config] {
+			dep = tfConfigAddress(dep)
+			if seen[dep] {
+				continue
+			}
+			seen[dep] = true
+
+			if _, exists := imported[dep]; exists {
+				found = append(found, dep)
+			} else {
+				visit(dep)
+			}
+		}
+	}
+	visit(from)
+
+	return found
+}
+
+// tfConfigDependencies returns the resources each resource in the configuration references, by their address
+// references to variables and module outputs are followed to the resources behind them
+func tfConfigDependencies(root tfConfigModule) map[string][]string {
+	scopes := map[string]tfConfigScope{}
+	var index func(path string, s tfConfigScope)
+	index = func(path string, s tfConfigScope) {
+		scopes[path] = s
+		for name, call := range s.module.ModuleCalls {
+			index(tfJoin(path, "module."+name), tfConfigScope{module: call.Module, parent: path, call: call})
+		}
+	}
+	index("", tfConfigScope{module: root})
+
+	var resolve func(path string, ref string, seen map[string]bool) []string
+	resolve = func(path string, ref string, seen map[string]bool) []string {
+		if seen[path+" "+ref] {
+			return nil
+		}
+		seen[path+" "+ref] = true
+
+		parts := strings.Split(tfConfigAddress(ref), ".")
+		if len(parts) < 2 {
+			return nil
+		}
+
+		found := []string{}
+		switch parts[0] {
+		case "var":
+			s := scopes[path]
+			if path == "" {
+				return nil
+			}
+			for _, r := range tfReferences(s.call.Expressions[parts[1]]) {
+				found = append(found, resolve(s.parent, r, seen)...)
+			}
+		case "module":
+			child := tfJoin(path, "module."+parts[1])
+			for name, o := range scopes[child].module.Outputs {
+				if len(parts) > 2 && parts[2] != name {
+					continue
+				}
+				for _, r := range tfReferences(o.Expression) {
+					found = append(found, resolve(child, r, seen)...)
+				}
+			}
+		case "data", "local", "each", "count", "path", "self", "terraform":
+		default:
+			found = append(found, tfJoin(path, parts[0]+"."+parts[1]))
+		}
+
+		return found
+	}
+
+	deps := map[string][]string{}
+	for path, s := range scopes {
+		for _, r := range s.module.Resources {
+			if r.Mode != "managed" {
+				continue
+			}
+
+			config := tfJoin(path, r.Address)
+			refs := append(tfReferences(r.Expressions), r.DependsOn...)
+			for _, ref := range refs {
+				deps[config] = append(deps[config], resolve(path, ref, map[string]bool{})...)
+			}
+		}
+	}
+
+	return deps
+}
+
+// tfReferences returns every reference found anywhere in an expression
+func tfReferences(expression interface{}) []string {
+	refs := []string{}
+	switch e := expression.(type) {
+	case map[string]interface{}:
+		for k, v := range e {
+			if k == "references" {
+				if list, ok := v.([]interface{}); ok {
+					for _, r := range list {
+						if s, ok := r.(string); ok {
+							refs = append(refs, s)
+						}
+					}
+					continue
+				}
+			}
+			refs = append(refs, tfReferences(v)...)
+		}
+	case []interface{}:
+		for _, v := range e {
+			refs = append(refs, tfReferences(v)...)
+		}
+	}
+
+	return refs
+}
@@ -0,0 +1,322 @@
+package importer_test
+
+import (
+	"github.com/abramsimon/gomponere/internal/importer"
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("Terraform", func() {
+	var (
+		err  error
+		data []byte
+		opts importer.Options
+		d    model.Diagram
+	)
+
+	BeforeEach(func() {
+		opts = importer.Options{}
+	})
+
+	JustBeforeEach(func() {
+		d, err = importer.Terraform(data, opts)
+	})
+
+	Context("with invalid json", func() {
+		BeforeEach(func() {
+			data = []byte("{")
+		})
+
+		It("does error", func() {
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("without values", func() {
+		BeforeEach(func() {
+			data = []byte(`{"format_version": "1.0"}`)
+		})
+
+		It("does error", func() {
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("without resources of a known type", func() {
+		BeforeEach(func() {
+			data = []byte(`{"values": {"root_module": {"resources": [{"address": "aws_iam_role.api", "mode": "managed", "type": "aws_iam_role", "name": "api"}]}}}`)
+		})
+
+		It("does error", func() {
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("with a state", func() {
+		BeforeEach(func() {
+			data = []byte(terraformState)
+		})
+
+		It("does not error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("creates a component per resource of a known type", func() {
+			Expect(d.Components).To(HaveLen(8))
+			Expect(d.Components).ToNot(HaveKey("api-role"))
+			Expect(d.Components["orders-api"]).To(MatchFields(IgnoreExtras, Fields{
+				"Name":        Equal("Orders Api"),
+				"Description": Equal("aws_lambda_function"),
+				"TypeKey":     Equal(importer.Service),
+			}))
+		})
+		It("types components by resource type", func() {
+			Expect(d.Components["orders-db"].TypeKey).To(Equal(importer.Database))
+			Expect(d.Components["orders-cache"].TypeKey).To(Equal(importer.Cache))
+			Expect(d.Components["orders-jobs"].TypeKey).To(Equal(importer.Queue))
+			Expect(d.Components["logs-0"].TypeKey).To(Equal(importer.Storage))
+			Expect(d.Types).To(HaveKey(importer.Storage))
+		})
+		It("names resources without a cloud name after their module and index", func() {
+			Expect(d.Components).To(HaveKey("logs-0"))
+			Expect(d.Components).To(HaveKey("logs-1"))
+			Expect(d.Components).To(HaveKey("search"))
+		})
+		It("maps modules to areas", func() {
+			Expect(d.Areas).To(HaveKeyWithValue("db", model.Area{Name: "Db"}))
+			Expect(d.Areas).To(HaveKeyWithValue("db-cache", model.Area{Name: "Cache", ParentKey: "db"}))
+			Expect(d.Components["orders-db"].AreaKey).To(Equal("db"))
+			Expect(d.Components["orders-cache"].AreaKey).To(Equal("db-cache"))
+		})
+		It("maps region tags to areas", func() {
+			Expect(d.Areas).To(HaveKeyWithValue("eu-west-1", model.Area{Name: "eu-west-1"}))
+			Expect(d.Components["orders-api"].AreaKey).To(Equal("eu-west-1"))
+			Expect(d.Components["logs-0"].AreaKey).To(Equal(importer.Unassigned))
+		})
+		It("maps tags to teams", func() {
+			Expect(d.Components["orders-api"].TeamKey).To(Equal("orders"))
+			Expect(d.Components["orders-db"].TeamKey).To(Equal(importer.Unassigned))
+			Expect(d.Teams).To(HaveKey("orders"))
+		})
+		It("takes dependencies from the state", func() {
+			Expect(d.Components["orders-api"].DependencyKeys).To(Equal([]string{"orders-db", "orders-jobs"}))
+			Expect(d.Components["orders-db"].DependencyKeys).To(BeEmpty())
+		})
+		It("follows dependencies through resources that are not imported", func() {
+			Expect(d.Components["web"].DependencyKeys).To(Equal([]string{"orders-cache", "orders-db"}))
+		})
+	})
+
+	Context("with a plan", func() {
+		BeforeEach(func() {
+			data = []byte(terraformPlan)
+			opts = importer.Options{AreaKey: "cloud", TeamKey: "platform"}
+		})
+
+		It("does not error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("creates components from the planned values", func() {
+			Expect(d.Components).To(HaveLen(2))
+			Expect(d.Components).To(HaveKey("worker"))
+			Expect(d.Components).To(HaveKey("jobs"))
+		})
+		It("nests module areas in the placeholder area", func() {
+			Expect(d.Areas).To(HaveKeyWithValue("jobs", model.Area{Name: "Jobs", ParentKey: "cloud"}))
+			Expect(d.Components["worker"].AreaKey).To(Equal("cloud"))
+		})
+		It("uses the placeholder team", func() {
+			Expect(d.Components["worker"].TeamKey).To(Equal("platform"))
+		})
+		It("takes dependencies from references through module outputs", func() {
+			Expect(d.Components["worker"].DependencyKeys).To(Equal([]string{"jobs"}))
+		})
+	})
+})
+
+const terraformState string = `{
+  "format_version": "1.0",
+  "values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "aws_lambda_function.api",
+          "mode": "managed",
+          "type": "aws_lambda_function",
+          "name": "api",
+          "values": {"function_name": "orders-api", "tags": {"Team": "orders", "Region": "eu-west-1"}},
+          "depends_on": ["aws_iam_role.api", "aws_sqs_queue.jobs", "module.db.aws_db_instance.this"]
+        },
+        {
+          "address": "aws_iam_role.api",
+          "mode": "managed",
+          "type": "aws_iam_role",
+          "name": "api",
+          "values": {"name": "api-role"}
+        },
+        {
+          "address": "aws_sqs_queue.jobs",
+          "mode": "managed",
+          "type": "aws_sqs_queue",
+          "name": "jobs",
+          "values": {"name": "orders-jobs", "tags": null}
+        },
+        {
+          "address": "aws_s3_bucket.logs[0]",
+          "mode": "managed",
+          "type": "aws_s3_bucket",
+          "name": "logs",
+          "index": 0,
+          "values": {}
+        },
+        {
+          "address": "aws_s3_bucket.logs[1]",
+          "mode": "managed",
+          "type": "aws_s3_bucket",
+          "name": "logs",
+          "index": 1,
+          "values": {}
+        },
+        {
+          "address": "aws_ecs_service.web",
+          "mode": "managed",
+          "type": "aws_ecs_service",
+          "name": "web",
+          "values": {"name": "web"},
+          "depends_on": ["aws_ecs_task_definition.web"]
+        },
+        {
+          "address": "aws_ecs_task_definition.web",
+          "mode": "managed",
+          "type": "aws_ecs_task_definition",
+          "name": "web",
+          "values": {},
+          "depends_on": ["module.db.aws_db_instance.this", "module.db.module.cache.aws_elasticache_cluster.this"]
+        },
+        {
+          "address": "data.aws_region.current",
+          "mode": "data",
+          "type": "aws_region",
+          "name": "current",
+          "values": {"name": "eu-west-1"}
+        }
+      ],
+      "child_modules": [
+        {
+          "address": "module.db",
+          "resources": [
+            {
+              "address": "module.db.aws_db_instance.this",
+              "mode": "managed",
+              "type": "aws_db_instance",
+              "name": "this",
+              "values": {"identifier": "orders-db", "name": "orders"},
+              "depends_on": ["module.db.aws_db_subnet_group.this"]
+            },
+            {
+              "address": "module.db.aws_db_subnet_group.this",
+              "mode": "managed",
+              "type": "aws_db_subnet_group",
+              "name": "this",
+              "values": {}
+            }
+          ],
+          "child_modules": [
+            {
+              "address": "module.db.module.cache",
+              "resources": [
+                {
+                  "address": "module.db.module.cache.aws_elasticache_cluster.this",
+                  "mode": "managed",
+                  "type": "aws_elasticache_cluster",
+                  "name": "this",
+                  "values": {"cluster_id": "orders-cache"}
+                }
+              ]
+            }
+          ]
+        },
+        {
+          "address": "module.search[0]",
+          "resources": [
+            {
+              "address": "module.search[0].aws_opensearch_domain.this",
+              "mode": "managed",
+              "type": "aws_opensearch_domain",
+              "name": "this",
+              "values": {}
+            }
+          ]
+        }
+      ]
+    }
+  }
+}`
+
+const terraformPlan string = `{
+  "format_version": "1.2",
+  "planned_values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "aws_lambda_function.worker",
+          "mode": "managed",
+          "type": "aws_lambda_function",
+          "name": "worker",
+          "values": {"function_name": "worker"}
+        }
+      ],
+      "child_modules": [
+        {
+          "address": "module.jobs",
+          "resources": [
+            {
+              "address": "module.jobs.aws_sqs_queue.this",
+              "mode": "managed",
+              "type": "aws_sqs_queue",
+              "name": "this",
+              "values": {"tags": {}}
+            }
+          ]
+        }
+      ]
+    }
+  },
+  "configuration": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "aws_lambda_function.worker",
+          "mode": "managed",
+          "type": "aws_lambda_function",
+          "name": "worker",
+          "expressions": {
+            "function_name": {"constant_value": "worker"},
+            "environment": [{"variables": {"references": ["module.jobs.url", "module.jobs"]}}]
+          }
+        }
+      ],
+      "module_calls": {
+        "jobs": {
+          "source": "./modules/queue",
+          "expressions": {"name": {"references": ["var.queue_name"]}},
+          "module": {
+            "resources": [
+              {
+                "address": "aws_sqs_queue.this",
+                "mode": "managed",
+                "type": "aws_sqs_queue",
+                "name": "this",
+                "expressions": {"name": {"references": ["var.name"]}}
+              }
+            ],
+            "outputs": {
+              "url": {"expression": {"references": ["aws_sqs_queue.this.url", "aws_sqs_queue.this"]}}
+            }
+          }
+        }
+      }
+    }
+  }
+}`
@@ -0,0 +1,132 @@
+package importer
+
+import (
+	"strings"
+
+	"github.com/abramsimon/gomponere/internal/model"
+)
+
+// the keys of the types imported components are given
+const (
+	Service  = "service"
+	Database = "database"
+	Cache    = "cache"
+	Queue    = "queue"
+	Search   = "search"
+	Proxy    = "proxy"
+	Storage  = "storage"
+	Job      = "job"
+	Module   = "module"
+)
+
+// Types are the types imported components can be given
+var Types = map[string]model.Type{
+	Service:  {Name: "Service", Description: "An application or api"},
+	Database: {Name: "Database", Description: "A SQL or NOSQL database", Shape: "cylinder"},
+	Cache:    {Name: "Cache", Description: "An in-memory cache", Shape: "box3d"},
+	Queue:    {Name: "Queue", Description: "A message queue or event stream", Shape: "cds"},
+	Search:   {Name: "Search", Description: "A search index"},
+	Proxy:    {Name: "Proxy", Description: "A reverse proxy, load balancer or gateway", Shape: "hexagon"},
+	Storage:  {Name: "Storage", Description: "Object or file storage", Shape: "folder"},
+	Job:      {Name: "Job", Description: "A scheduled or batch job", Shape: "component"},
+	Module:   {Name: "Module", Description: "A code module or library", Shape: "tab"},
+}
+
+// imageTypes maps well known image names to the type of component they run
+var imageTypes = map[string]string{
+	"postgres":      Database,
+	"postgresql":    Database,
+	"postgis":       Database,
+	"mysql":         Database,
+	"mariadb":       Database,
+	"mongo":         Database,
+	"mongodb":       Database,
+	"cassandra":     Database,
+	"cockroach":     Database,
+	"cockroachdb":   Database,
+	"mssql":         Database,
+	"mssql-server":  Database,
+	"oracle":        Database,
+	"couchdb":       Database,
+	"neo4j":         Database,
+	"influxdb":      Database,
+	"redis":         Cache,
+	"memcached":     Cache,
+	"valkey":        Cache,
+	"rabbitmq":      Queue,
+	"kafka":         Queue,
+	"cp-kafka":      Queue,
+	"nats":          Queue,
+	"activemq":      Queue,
+	"pulsar":        Queue,
+	"elasticsearch": Search,
+	"opensearch":    Search,
+	"solr":          Search,
+	"nginx":         Proxy,
+	"traefik":       Proxy,
+	"haproxy":       Proxy,
+	"envoy":         Proxy,
+	"caddy":         Proxy,
+	"minio":         Storage,
+}
+
+// toolWords are parts of image names for tools that work on a well known image rather than run it, like
+// "mongo-express" or "kafka-ui"
+var toolWords = map[string]bool{
+	"exporter": true,
+	"express":  true,
+	"ui":       true,
+	"insight":  true,
+	"admin":    true,
+}
+
+// TypeForImage returns the type of component an image runs, or Service when the image is not well known
+// registry, namespace, tag and digest are ignored, so "docker.io/library/postgres:12" is a database
+func TypeForImage(image string) string {
+	name := strings.ToLower(image)
+
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, ":"); i >= 0 {
+		name = name[:i]
+	}
+
+	if t, exists := imageTypes[name]; exists {
+		return t
+	}
+
+	// images like "redis-stack" are still recognizable by the name they start with, unless they are a tool for it
+	match, longest := Service, 0
+	for prefix, t := range imageTypes {
+		rest, found := strings.CutPrefix(name, prefix)
+		if !found || len(prefix) <= longest || !isBoundary(rest) {
+			continue
+		}
+		match, longest = t, len(prefix)
+	}
+	if longest > 0 && isTool(name[longest:]) {
+		return Service
+	}
+
+	return match
+}
+
+// isBoundary tells whether what follows a well known name starts a new part of the image name
+func isBoundary(rest string) bool {
+	return rest == "" || rest[0] == '-' || rest[0] == '_'
+}
+
+// isTool tells whether any part of what follows a well known name is a tool word
+func isTool(rest string) bool {
+	for _, word := range strings.FieldsFunc(rest, func(r rune) bool { return r == '-' || r == '_' }) {
+		if toolWords[word] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,51 @@
+package importer_test
+
+import (
+	"github.com/abramsimon/gomponere/internal/importer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TypeForImage", func() {
+	It("recognizes well known images", func() {
+		Expect(importer.TypeForImage("postgres")).To(Equal(importer.Database))
+		Expect(importer.TypeForImage("redis:6-alpine")).To(Equal(importer.Cache))
+		Expect(importer.TypeForImage("docker.io/library/rabbitmq:3-management")).To(Equal(importer.Queue))
+		Expect(importer.TypeForImage("nginx@sha256:abc")).To(Equal(importer.Proxy))
+	})
+	It("recognizes images by prefix", func() {
+		Expect(importer.TypeForImage("bitnami/postgresql:11")).To(Equal(importer.Database))
+		Expect(importer.TypeForImage("redis-stack")).To(Equal(importer.Cache))
+	})
+	It("only recognizes prefixes that end a part of the name", func() {
+		Expect(importer.TypeForImage("redisinsight")).To(Equal(importer.Service))
+		Expect(importer.TypeForImage("natsboard")).To(Equal(importer.Service))
+	})
+	It("does not mistake tools for what they work on", func() {
+		Expect(importer.TypeForImage("mongo-express")).To(Equal(importer.Service))
+		Expect(importer.TypeForImage("prometheuscommunity/postgres-exporter")).To(Equal(importer.Service))
+		Expect(importer.TypeForImage("redislabs/redisinsight:latest")).To(Equal(importer.Service))
+		Expect(importer.TypeForImage("provectuslabs/kafka-ui")).To(Equal(importer.Service))
+	})
+	It("falls back to service", func() {
+		Expect(importer.TypeForImage("mycompany/web:1.2.3")).To(Equal(importer.Service))
+	})
+})
+
+var _ = Describe("Key", func() {
+	It("makes a dash separated lower case key", func() {
+		Expect(importer.Key("My Web_App")).To(Equal("my-web-app"))
+		Expect(importer.Key("--api--")).To(Equal("api"))
+	})
+})
+
+var _ = Describe("Name", func() {
+	It("makes a readable name", func() {
+		Expect(importer.Name("my-web_app")).To(Equal("My Web App"))
+		Expect(importer.Name("api")).To(Equal("Api"))
+	})
+	It("capitalizes names starting with a multibyte character", func() {
+		Expect(importer.Name("éclair-api")).To(Equal("Éclair Api"))
+		Expect(importer.Name("ǆango")).To(Equal("ǅango"))
+	})
+})
@@ -0,0 +1,16 @@
+package input
+
+import (
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/spf13/afero"
+)
+
+// Load reads all of the input files under root and unmarshals them into a diagram
+func Load(fs afero.Fs, root string) (model.Diagram, error) {
+	data, err := NewReader(fs).ReadAll(root)
+	if err != nil {
+		return model.Diagram{}, err
+	}
+
+	return Unmarshal(data)
+}
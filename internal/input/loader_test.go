@@ -0,0 +1,55 @@
+package input_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/abramsimon/gomponere/internal/input"
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+var _ = Describe("Load", func() {
+	var (
+		err     error
+		fs      afero.Fs
+		root    string
+		diagram model.Diagram
+	)
+
+	BeforeEach(func() {
+		fs = afero.NewMemMapFs()
+		root = "/the/file/path"
+	})
+
+	JustBeforeEach(func() {
+		diagram, err = input.Load(fs, root)
+	})
+
+	Context("with an empty filesystem", func() {
+		It("does error", func() {
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Context("with input files", func() {
+		BeforeEach(func() {
+			if err = afero.WriteFile(fs, filepath.Join(root, "areas.yaml"), []byte(areas), os.ModePerm); err != nil {
+				Fail(err.Error())
+			}
+			if err = afero.WriteFile(fs, filepath.Join(root, "nested", "components.yaml"), []byte(components), os.ModePerm); err != nil {
+				Fail(err.Error())
+			}
+		})
+
+		It("does not error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("loads the diagram", func() {
+			Expect(diagram.Areas).To(HaveLen(2))
+			Expect(diagram.Components).To(HaveLen(2))
+		})
+	})
+})
@@ -4,7 +4,7 @@ import (
 	"bytes"
 	"fmt"
 
-	"../model"
+	"github.com/abramsimon/gomponere/internal/model"
 	"gopkg.in/yaml.v2"
 )
 
@@ -1,9 +1,8 @@
 package input_test
 
 import (
-	"../input"
-	"../model"
-
+	"github.com/abramsimon/gomponere/internal/input"
+	"github.com/abramsimon/gomponere/internal/model"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
@@ -72,6 +71,24 @@ var _ = Describe("Unmarshal", func() {
 			}))
 		})
 	})
+	Context("with a lifecycle", func() {
+		BeforeEach(func() {
+			data = []byte(lifecycle)
+		})
+
+		It("does not error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("can unmarshal", func() {
+			Expect(diagram.Components["component-1"].Lifecycle).To(MatchFields(IgnoreExtras, Fields{
+				"Status": Equal("deprecated"),
+				"Dates": Equal(map[string]string{
+					"active":         "2018-01-01",
+					"decommissioned": "2020-06-30",
+				}),
+			}))
+		})
+	})
 	Context("with levels", func() {
 		BeforeEach(func() {
 			data = []byte(levels)
@@ -200,6 +217,16 @@ components:
       - dep-2
       - dep-3
 `
+const lifecycle string = `
+components:
+  component-1:
+    name: The First Component
+    lifecycle:
+      status: deprecated
+      dates:
+        active: 2018-01-01
+        decommissioned: 2020-06-30
+`
 const levels string = `
 levels:
   level-1:
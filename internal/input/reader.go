@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/afero"
 )
@@ -63,6 +64,14 @@ func (r ReaderImpl) FindFiles(root string) ([]string, error) {
 			return err
 		}
 
+		// hidden files and directories, like the config file or .git, are not part of the model
+		if path != root && strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		// no need for directories
 		if info.IsDir() {
 			return nil
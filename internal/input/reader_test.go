@@ -5,8 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
-	"../input"
-
+	"github.com/abramsimon/gomponere/internal/input"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/spf13/afero"
@@ -113,6 +112,20 @@ var _ = Describe("Reader", func() {
 				})
 			})
 
+			Context("with hidden files and directories", func() {
+				BeforeEach(func() {
+					for _, name := range []string{".gomponere.yaml", ".git/config.yaml", "yes.yaml"} {
+						if err = afero.WriteFile(fs, filepath.Join(root, name), []byte("only yes.yaml should be found, hidden files and directories are skipped"), os.ModePerm); err != nil {
+							Fail(err.Error())
+						}
+					}
+				})
+
+				It("skips them", func() {
+					Expect(files).To(Equal([]string{filepath.Join(root, "yes.yaml")}))
+				})
+			})
+
 			Context("with multiple matched files", func() {
 				BeforeEach(func() {
 					for i := 0; i < 5; i++ {
@@ -0,0 +1,242 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/abramsimon/gomponere/internal/expr"
+	"github.com/abramsimon/gomponere/internal/model"
+)
+
+// Config holds the rules declared in the lint section of the config file
+type Config struct {
+	Rules []RuleConfig `yaml:"rules,omitempty"`
+}
+
+// RuleConfig declares a rule as expressions over one kind of entity
+//
+// Every entity of the kind that where is true for, or every one when where is empty, must make assert true, and
+// those that do not are reported with the message.
+type RuleConfig struct {
+	Name     string `yaml:"name"`
+	For      string `yaml:"for"`
+	Where    string `yaml:"where,omitempty"`
+	Assert   string `yaml:"assert"`
+	Message  string `yaml:"message"`
+	Severity string `yaml:"severity,omitempty"`
+}
+
+// kinds maps each kind of entity rules can be for to its fields and a function listing the entities with the values
+// of those fields
+var kinds = map[string]struct {
+	fields   expr.Fields
+	entities func(d model.Diagram) []entity
+}{
+	"component": {expr.ComponentFields, componentEntities},
+	"dependency": {
+		expr.Prefixed(expr.ComponentFields, "from.", "to."),
+		dependencyEntities,
+	},
+	"area": {
+		expr.Fields{"key": expr.String, "name": expr.String, "parent": expr.String, "components": expr.Number, "tags": expr.List, "properties.*": expr.String},
+		areaEntities,
+	},
+	"team": {
+		expr.Fields{"key": expr.String, "name": expr.String, "email": expr.String, "lead": expr.String, "components": expr.Number, "tags": expr.List, "properties.*": expr.String},
+		teamEntities,
+	},
+	"type": {
+		expr.Fields{"key": expr.String, "name": expr.String, "description": expr.String, "shape": expr.String, "components": expr.Number},
+		typeEntities,
+	},
+}
+
+// entity is something in the diagram a rule checks, with the values of its fields
+type entity struct {
+	key    string
+	fields expr.Env
+}
+
+// Compile compiles the declared rules, which may not share a name with each other or a built in rule
+func (c Config) Compile() ([]Rule, error) {
+	names := map[string]bool{}
+	for _, r := range Rules {
+		names[r.Name] = true
+	}
+
+	rules := []Rule{}
+	for i, rc := range c.Rules {
+		if rc.Name == "" {
+			return nil, fmt.Errorf("rule %d has no name", i+1)
+		}
+		if names[rc.Name] {
+			return nil, fmt.Errorf("rule '%s' is declared more than once", rc.Name)
+		}
+		names[rc.Name] = true
+
+		r, err := rc.Compile()
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+
+	return rules, nil
+}
+
+// Compile turns the declared rule into a rule, warning about the entities that break it unless a severity is given
+func (rc RuleConfig) Compile() (Rule, error) {
+	kind, exists := kinds[rc.For]
+	if !exists {
+		return Rule{}, fmt.Errorf("rule '%s' is for unknown kind '%s', expected component, dependency, area, team or type", rc.Name, rc.For)
+	}
+
+	severity := Warning
+	if rc.Severity != "" {
+		s, err := ParseSeverity(rc.Severity)
+		if err != nil {
+			return Rule{}, fmt.Errorf("rule '%s': %s", rc.Name, err)
+		}
+		severity = s
+	}
+
+	// an empty where is always true
+	where := expr.Expr{}
+	if rc.Where != "" {
+		x, err := expr.Compile(rc.Where, kind.fields)
+		if err != nil {
+			return Rule{}, fmt.Errorf("rule '%s': where: %s", rc.Name, err)
+		}
+		where = x
+	}
+
+	if rc.Assert == "" {
+		return Rule{}, fmt.Errorf("rule '%s' has nothing to assert", rc.Name)
+	}
+	assert, err := expr.Compile(rc.Assert, kind.fields)
+	if err != nil {
+		return Rule{}, fmt.Errorf("rule '%s': assert: %s", rc.Name, err)
+	}
+
+	message := rc.Message
+	if message == "" {
+		message = "does not meet: " + rc.Assert
+	}
+
+	return Rule{
+		Name:        rc.Name,
+		Description: message,
+		Severity:    severity,
+		Check: func(d model.Diagram) []Problem {
+			problems := []Problem{}
+			for _, e := range kind.entities(d) {
+				if where.Eval(d, e.fields) && !assert.Eval(d, e.fields) {
+					problems = append(problems, Problem{rc.For, e.key, message})
+				}
+			}
+
+			return problems
+		},
+	}, nil
+}
+
+func componentEntities(d model.Diagram) []entity {
+	entities := []entity{}
+	for _, k := range d.ComponentKeys() {
+		entities = append(entities, entity{k, expr.ComponentEnv(d, k)})
+	}
+
+	return entities
+}
+
+// dependencyEntities lists every dependency between components in the diagram, keyed like "web -> api"
+func dependencyEntities(d model.Diagram) []entity {
+	entities := []entity{}
+	for _, k := range d.ComponentKeys() {
+		from := expr.ComponentEnv(d, k)
+		for _, dk := range d.Dependencies(k) {
+			fields := expr.Env{}
+			for name, v := range from {
+				fields["from."+name] = v
+			}
+			for name, v := range expr.ComponentEnv(d, dk) {
+				fields["to."+name] = v
+			}
+			entities = append(entities, entity{k + " -> " + dk, fields})
+		}
+	}
+
+	return entities
+}
+
+func areaEntities(d model.Diagram) []entity {
+	entities := []entity{}
+	for _, k := range sortedKeys(d.Areas) {
+		a := d.Areas[k]
+		e := expr.Env{
+			"key":        k,
+			"name":       a.Name,
+			"parent":     a.ParentKey,
+			"components": count(d, func(c model.Component) bool { return d.InArea(c.AreaKey, k) }),
+		}
+		e.SetMetadata(a.Tags, a.Properties)
+		entities = append(entities, entity{k, e})
+	}
+
+	return entities
+}
+
+func teamEntities(d model.Diagram) []entity {
+	entities := []entity{}
+	for _, k := range sortedKeys(d.Teams) {
+		t := d.Teams[k]
+		e := expr.Env{
+			"key":        k,
+			"name":       t.Name,
+			"email":      t.TeamContact.Email,
+			"lead":       t.LeadContact.Email,
+			"components": count(d, func(c model.Component) bool { return c.TeamKey == k }),
+		}
+		e.SetMetadata(t.Tags, t.Properties)
+		entities = append(entities, entity{k, e})
+	}
+
+	return entities
+}
+
+func typeEntities(d model.Diagram) []entity {
+	entities := []entity{}
+	for _, k := range sortedKeys(d.Types) {
+		t := d.Types[k]
+		entities = append(entities, entity{k, expr.Env{
+			"key":         k,
+			"name":        t.Name,
+			"description": t.Description,
+			"shape":       t.Shape,
+			"components":  count(d, func(c model.Component) bool { return c.TypeKey == k }),
+		}})
+	}
+
+	return entities
+}
+
+func count(d model.Diagram, match func(c model.Component) bool) int {
+	n := 0
+	for _, c := range d.Components {
+		if match(c) {
+			n++
+		}
+	}
+
+	return n
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,140 @@
+package lint_test
+
+import (
+	"github.com/abramsimon/gomponere/internal/lint"
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Config", func() {
+	var (
+		d           model.Diagram
+		config      lint.Config
+		diagnostics []lint.Diagnostic
+		err         error
+	)
+
+	BeforeEach(func() {
+		d = model.Diagram{
+			Areas: map[string]model.Area{
+				"prod-eu": {Name: "Production EU"},
+				"shop":    {Name: "Shop", ParentKey: "prod-eu"},
+				"staging": {Name: "Staging"},
+			},
+			Teams: map[string]model.Team{
+				"shop": {Name: "Shop"},
+			},
+			Types: map[string]model.Type{
+				"database":   {Name: "Database", Shape: "cylinder"},
+				"app-mobile": {Name: "Mobile App"},
+			},
+			Components: map[string]model.Component{
+				"app":     {Name: "App", TypeKey: "app-mobile", AreaKey: "staging", DependencyKeys: []string{"db", "api"}},
+				"api":     {Name: "Api", AreaKey: "shop", TeamKey: "shop", Git: "https://github.com/acme/api", DependencyKeys: []string{"db"}},
+				"db":      {Name: "DB", TypeKey: "database", LevelKey: "component", AreaKey: "prod-eu", Git: "https://github.com/acme/db"},
+				"reports": {Name: "Reports", TypeKey: "database", LevelKey: "data", AreaKey: "shop"},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		var rules []lint.Rule
+		rules, err = config.Compile()
+		if err == nil {
+			diagnostics = lint.Run(d, rules)
+		}
+	})
+
+	Context("with the example rules", func() {
+		BeforeEach(func() {
+			config = lint.Config{Rules: []lint.RuleConfig{
+				{
+					Name:     "database-level",
+					For:      "component",
+					Where:    `type == "database"`,
+					Assert:   `level == "component"`,
+					Message:  "databases must be in the component level",
+					Severity: "error",
+				},
+				{
+					Name:    "prod-git",
+					For:     "component",
+					Where:   `area matches "prod-*" || area within "prod-eu"`,
+					Assert:  `git != ""`,
+					Message: "components in production must have a git url",
+				},
+				{
+					Name:     "mobile-database",
+					For:      "dependency",
+					Assert:   `!(from.type == "app-mobile" && to.type == "database")`,
+					Message:  "mobile apps must not use databases directly",
+					Severity: "error",
+				},
+			}}
+		})
+
+		It("reports what breaks them", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diagnostics).To(Equal([]lint.Diagnostic{
+				{Rule: "database-level", Severity: lint.Error, Kind: "component", Key: "reports", Message: "databases must be in the component level"},
+				{Rule: "mobile-database", Severity: lint.Error, Kind: "dependency", Key: "app -> db", Message: "mobile apps must not use databases directly"},
+				{Rule: "prod-git", Severity: lint.Warning, Kind: "component", Key: "reports", Message: "components in production must have a git url"},
+			}))
+		})
+	})
+
+	Context("with rules for areas, teams and types", func() {
+		BeforeEach(func() {
+			config = lint.Config{Rules: []lint.RuleConfig{
+				{Name: "small-areas", For: "area", Where: `parent == ""`, Assert: "components <= 2"},
+				{Name: "team-email", For: "team", Assert: `email != "" || components == 0`, Message: "needs an email"},
+				{Name: "shapes", For: "type", Assert: `shape != "" && !(name matches "*App")`},
+				{Name: "fan-out", For: "component", Assert: "dependencies < 2 && dependents >= 0 && status == \"active\""},
+			}}
+		})
+
+		It("reports what breaks them", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diagnostics).To(ConsistOf(
+				lint.Diagnostic{Rule: "small-areas", Severity: lint.Warning, Kind: "area", Key: "prod-eu", Message: "does not meet: components <= 2"},
+				lint.Diagnostic{Rule: "team-email", Severity: lint.Warning, Kind: "team", Key: "shop", Message: "needs an email"},
+				lint.Diagnostic{Rule: "shapes", Severity: lint.Warning, Kind: "type", Key: "app-mobile", Message: `does not meet: shape != "" && !(name matches "*App")`},
+				lint.Diagnostic{Rule: "fan-out", Severity: lint.Warning, Kind: "component", Key: "app", Message: `does not meet: dependencies < 2 && dependents >= 0 && status == "active"`},
+			))
+		})
+	})
+
+	Describe("invalid rules", func() {
+		cases := []struct {
+			name    string
+			rule    lint.RuleConfig
+			message string
+		}{
+			{"no name", lint.RuleConfig{For: "component", Assert: "true"}, "rule 1 has no name"},
+			{"built in name", lint.RuleConfig{Name: "unused-team", For: "team", Assert: "true"}, "rule 'unused-team' is declared more than once"},
+			{"unknown kind", lint.RuleConfig{Name: "r", For: "service", Assert: "true"}, "rule 'r' is for unknown kind 'service', expected component, dependency, area, team or type"},
+			{"unknown severity", lint.RuleConfig{Name: "r", For: "team", Assert: "true", Severity: "fatal"}, "rule 'r': unknown severity 'fatal', expected error, warning, info or off"},
+			{"no assertion", lint.RuleConfig{Name: "r", For: "team"}, "rule 'r' has nothing to assert"},
+			{"unknown field", lint.RuleConfig{Name: "r", For: "team", Where: `kind == "x"`, Assert: "true"}, "rule 'r': where: unknown field 'kind' at column 1"},
+			{"field of another kind", lint.RuleConfig{Name: "r", For: "component", Assert: `from.type == "x"`}, "rule 'r': assert: unknown field 'from.type' at column 1"},
+			{"mismatched types", lint.RuleConfig{Name: "r", For: "component", Assert: `dependencies == "2"`}, "rule 'r': assert: '==' at column 14 compares a number with a string"},
+			{"not a boolean", lint.RuleConfig{Name: "r", For: "component", Assert: "name"}, "rule 'r': assert: expression is a string, not true or false"},
+			{"logic on strings", lint.RuleConfig{Name: "r", For: "component", Assert: `name && true`}, "rule 'r': assert: '&&' at column 6 needs true or false on both sides"},
+			{"ordering strings", lint.RuleConfig{Name: "r", For: "component", Assert: `name < "b"`}, "rule 'r': assert: '<' at column 6 needs numbers on both sides"},
+			{"bad pattern", lint.RuleConfig{Name: "r", For: "component", Assert: `name matches "[a"`}, "rule 'r': assert: invalid pattern '[a' at column 6"},
+			{"unclosed parenthesis", lint.RuleConfig{Name: "r", For: "component", Assert: `(true`}, "rule 'r': assert: expected ')' at column 6"},
+			{"unterminated string", lint.RuleConfig{Name: "r", For: "component", Assert: `name == "web`}, "rule 'r': assert: unterminated string at column 9"},
+			{"unknown symbol", lint.RuleConfig{Name: "r", For: "component", Assert: `name = "web"`}, "rule 'r': assert: unexpected '=' at column 6"},
+			{"trailing tokens", lint.RuleConfig{Name: "r", For: "component", Assert: `true true`}, "rule 'r': assert: unexpected 'true' at column 6"},
+		}
+
+		for _, c := range cases {
+			c := c
+			It("fails with "+c.name, func() {
+				_, err := lint.Config{Rules: []lint.RuleConfig{c.rule}}.Compile()
+				Expect(err).To(MatchError(c.message))
+			})
+		}
+	})
+})
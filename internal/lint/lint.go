@@ -0,0 +1,145 @@
+package lint
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/abramsimon/gomponere/internal/model"
+)
+
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+	Info    Severity = "info"
+
+	// Off turns a rule off
+	Off Severity = "off"
+)
+
+// ParseSeverity returns the severity with the given name
+func ParseSeverity(s string) (Severity, error) {
+	switch Severity(s) {
+	case Error, Warning, Info, Off:
+		return Severity(s), nil
+	}
+
+	return "", fmt.Errorf("unknown severity '%s', expected error, warning, info or off", s)
+}
+
+// Problem is something a rule found wrong with a single entity in the diagram
+type Problem struct {
+	Kind    string
+	Key     string
+	Message string
+}
+
+// Rule checks the diagram for one kind of problem
+type Rule struct {
+	Name        string
+	Description string
+	Severity    Severity
+	Check       func(d model.Diagram) []Problem
+}
+
+// Diagnostic is a problem reported by a rule, with the severity it was reported at
+type Diagnostic struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Kind     string   `json:"kind"`
+	Key      string   `json:"key"`
+	Message  string   `json:"message"`
+}
+
+// WithSeverities returns a copy of the rules with the severities of the named rules changed
+func WithSeverities(rules []Rule, severities map[string]Severity) ([]Rule, error) {
+	changed := make([]Rule, len(rules))
+	copy(changed, rules)
+
+	for name, s := range severities {
+		found := false
+		for i := range changed {
+			if changed[i].Name == name {
+				changed[i].Severity = s
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown rule '%s'", name)
+		}
+	}
+
+	return changed, nil
+}
+
+// Run checks the diagram with every rule that is not turned off and returns what they found, sorted by severity and
+// then entity
+func Run(d model.Diagram, rules []Rule) []Diagnostic {
+	diagnostics := []Diagnostic{}
+	for _, r := range rules {
+		if r.Severity == Off {
+			continue
+		}
+
+		for _, p := range r.Check(d) {
+			diagnostics = append(diagnostics, Diagnostic{
+				Rule:     r.Name,
+				Severity: r.Severity,
+				Kind:     p.Kind,
+				Key:      p.Key,
+				Message:  p.Message,
+			})
+		}
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i], diagnostics[j]
+		if a.Severity != b.Severity {
+			return rank(a.Severity) < rank(b.Severity)
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		return a.Rule < b.Rule
+	})
+
+	return diagnostics
+}
+
+// HasErrors returns true if any of the diagnostics is an error
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == Error {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WriteText writes one line per diagnostic
+func WriteText(w io.Writer, diagnostics []Diagnostic) error {
+	for _, d := range diagnostics {
+		if _, err := fmt.Fprintf(w, "%s: %s '%s': %s [%s]\n", d.Severity, d.Kind, d.Key, d.Message, d.Rule); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func rank(s Severity) int {
+	switch s {
+	case Error:
+		return 0
+	case Warning:
+		return 1
+	default:
+		return 2
+	}
+}
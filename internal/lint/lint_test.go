@@ -0,0 +1,104 @@
+package lint_test
+
+import (
+	"bytes"
+
+	"github.com/abramsimon/gomponere/internal/lint"
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lint", func() {
+	var (
+		d     model.Diagram
+		rules []lint.Rule
+	)
+
+	BeforeEach(func() {
+		d = model.Diagram{
+			Components: map[string]model.Component{
+				"b": {Name: "B"},
+				"a": {Name: "A"},
+			},
+		}
+		rules = []lint.Rule{
+			{
+				Name:     "every-component",
+				Severity: lint.Warning,
+				Check: func(d model.Diagram) []lint.Problem {
+					problems := []lint.Problem{}
+					for _, k := range d.ComponentKeys() {
+						problems = append(problems, lint.Problem{Kind: "component", Key: k, Message: "is a component"})
+					}
+					return problems
+				},
+			},
+			{
+				Name:     "always-error",
+				Severity: lint.Error,
+				Check: func(d model.Diagram) []lint.Problem {
+					return []lint.Problem{{Kind: "diagram", Message: "is wrong"}}
+				},
+			},
+		}
+	})
+
+	Describe("Run", func() {
+		var (
+			diagnostics []lint.Diagnostic
+		)
+
+		JustBeforeEach(func() {
+			diagnostics = lint.Run(d, rules)
+		})
+
+		It("reports every problem", func() {
+			Expect(diagnostics).To(HaveLen(3))
+		})
+		It("sorts by severity and then entity", func() {
+			Expect(diagnostics[0]).To(Equal(lint.Diagnostic{Rule: "always-error", Severity: lint.Error, Kind: "diagram", Message: "is wrong"}))
+			Expect(diagnostics[1].Key).To(Equal("a"))
+			Expect(diagnostics[2].Key).To(Equal("b"))
+		})
+		It("has errors", func() {
+			Expect(lint.HasErrors(diagnostics)).To(BeTrue())
+		})
+
+		Context("with only warnings", func() {
+			BeforeEach(func() {
+				rules = rules[:1]
+			})
+
+			It("does not have errors", func() {
+				Expect(lint.HasErrors(diagnostics)).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("WithSeverities", func() {
+		It("changes the severity of the named rules", func() {
+			changed, err := lint.WithSeverities(rules, map[string]lint.Severity{"always-error": lint.Info})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed[1].Severity).To(Equal(lint.Info))
+			Expect(rules[1].Severity).To(Equal(lint.Error))
+		})
+		It("turns rules off", func() {
+			changed, err := lint.WithSeverities(rules, map[string]lint.Severity{"every-component": lint.Off})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lint.Run(d, changed)).To(Equal([]lint.Diagnostic{{Rule: "always-error", Severity: lint.Error, Kind: "diagram", Message: "is wrong"}}))
+		})
+		It("fails for unknown rules", func() {
+			_, err := lint.WithSeverities(rules, map[string]lint.Severity{"missing": lint.Off})
+			Expect(err).To(MatchError("unknown rule 'missing'"))
+		})
+	})
+
+	Describe("WriteText", func() {
+		It("writes a line per diagnostic", func() {
+			buf := &bytes.Buffer{}
+			Expect(lint.WriteText(buf, lint.Run(d, rules[:1]))).To(Succeed())
+			Expect(buf.String()).To(Equal("warning: component 'a': is a component [every-component]\nwarning: component 'b': is a component [every-component]\n"))
+		})
+	})
+})
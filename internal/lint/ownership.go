@@ -0,0 +1,112 @@
+package lint
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/abramsimon/gomponere/internal/model"
+)
+
+func checkUnownedComponent(d model.Diagram) []Problem {
+	problems := []Problem{}
+	for _, k := range d.ComponentKeys() {
+		if !d.HasOwner(k) {
+			problems = append(problems, Problem{"component", k, noTeam(d.Components[k])})
+		}
+	}
+
+	return problems
+}
+
+// noTeam says why a component without an owner has none
+func noTeam(c model.Component) string {
+	if c.TeamKey == "" || c.TeamKey == model.Unassigned {
+		return "has no team"
+	}
+
+	return fmt.Sprintf("has unknown team '%s'", c.TeamKey)
+}
+
+func checkTeamContact(d model.Diagram) []Problem {
+	problems := []Problem{}
+	for _, k := range sortedKeys(d.Teams) {
+		if d.Teams[k].TeamContact.Email == "" {
+			problems = append(problems, Problem{"team", k, "has no contact email"})
+		}
+	}
+
+	return problems
+}
+
+func checkContactEmail(d model.Diagram) []Problem {
+	problems := []Problem{}
+	for _, k := range sortedKeys(d.Teams) {
+		t := d.Teams[k]
+		contacts := []struct {
+			name  string
+			email string
+		}{
+			{"team contact", t.TeamContact.Email},
+			{"lead contact", t.LeadContact.Email},
+		}
+
+		for _, c := range contacts {
+			if c.email != "" && !isEmail(c.email) {
+				problems = append(problems, Problem{"team", k, fmt.Sprintf("%s email '%s' is not a valid email address", c.name, c.email)})
+			}
+		}
+	}
+
+	return problems
+}
+
+func checkUnusedTeam(d model.Diagram) []Problem {
+	owners := map[string]bool{}
+	for _, c := range d.Components {
+		owners[c.TeamKey] = true
+	}
+
+	problems := []Problem{}
+	for _, k := range sortedKeys(d.Teams) {
+		if !owners[k] {
+			problems = append(problems, Problem{"team", k, "owns no components"})
+		}
+	}
+
+	return problems
+}
+
+// RequireOwnership returns a rule that fails when a component in any of the areas, or the areas within them, has no team
+// declared in the diagram
+func RequireOwnership(areaKeys ...string) Rule {
+	return Rule{
+		Name:        "required-ownership",
+		Description: "every component in " + strings.Join(areaKeys, ", ") + " must have a team",
+		Severity:    Error,
+		Check: func(d model.Diagram) []Problem {
+			problems := []Problem{}
+			for _, k := range d.ComponentKeys() {
+				c := d.Components[k]
+				if d.HasOwner(k) {
+					continue
+				}
+
+				for _, a := range areaKeys {
+					if d.InArea(c.AreaKey, a) {
+						problems = append(problems, Problem{"component", k, fmt.Sprintf("%s, but every component in area '%s' must have one", noTeam(c), a)})
+						break
+					}
+				}
+			}
+
+			return problems
+		},
+	}
+}
+
+// isEmail returns true for a bare email address, without a display name
+func isEmail(s string) bool {
+	a, err := mail.ParseAddress(s)
+	return err == nil && a.Address == s && strings.Contains(s[strings.LastIndex(s, "@"):], ".")
+}
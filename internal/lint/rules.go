@@ -0,0 +1,104 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/abramsimon/gomponere/internal/model"
+)
+
+// Rules are the built-in rules, run by default
+var Rules = []Rule{
+	{
+		Name:        "lifecycle-status",
+		Description: "lifecycle statuses and dates must be valid",
+		Severity:    Error,
+		Check:       checkLifecycleStatus,
+	},
+	{
+		Name:        "retiring-dependency",
+		Description: "active components should not depend on deprecated or decommissioned components",
+		Severity:    Warning,
+		Check:       checkRetiringDependency,
+	},
+	{
+		Name:        "unowned-component",
+		Description: "components should have a team",
+		Severity:    Warning,
+		Check:       checkUnownedComponent,
+	},
+	{
+		Name:        "team-contact",
+		Description: "teams should have a contact email",
+		Severity:    Warning,
+		Check:       checkTeamContact,
+	},
+	{
+		Name:        "contact-email",
+		Description: "team and lead contact emails must be valid email addresses",
+		Severity:    Error,
+		Check:       checkContactEmail,
+	},
+	{
+		Name:        "unused-team",
+		Description: "teams should own at least one component",
+		Severity:    Info,
+		Check:       checkUnusedTeam,
+	},
+}
+
+func checkLifecycleStatus(d model.Diagram) []Problem {
+	problems := []Problem{}
+	for _, k := range d.ComponentKeys() {
+		l := d.Components[k].Lifecycle
+
+		if l.Status != "" && !isLifecycleStatus(l.Status) {
+			problems = append(problems, Problem{"component", k, fmt.Sprintf("unknown lifecycle status '%s'", l.Status)})
+		}
+
+		statuses := make([]string, 0, len(l.Dates))
+		for s := range l.Dates {
+			statuses = append(statuses, s)
+		}
+		sort.Strings(statuses)
+
+		for _, s := range statuses {
+			if !isLifecycleStatus(s) {
+				problems = append(problems, Problem{"component", k, fmt.Sprintf("date for unknown lifecycle status '%s'", s)})
+			} else if _, err := time.Parse(model.LifecycleDateFormat, l.Dates[s]); err != nil {
+				problems = append(problems, Problem{"component", k, fmt.Sprintf("%s date '%s' is not formatted as YYYY-MM-DD", s, l.Dates[s])})
+			}
+		}
+	}
+
+	return problems
+}
+
+func checkRetiringDependency(d model.Diagram) []Problem {
+	problems := []Problem{}
+	for _, k := range d.ComponentKeys() {
+		if d.Components[k].Lifecycle.Current() != model.Active {
+			continue
+		}
+
+		for _, dk := range d.Dependencies(k) {
+			dep := d.Components[dk].Lifecycle
+			if dep.IsRetiring() {
+				problems = append(problems, Problem{"component", k, fmt.Sprintf("depends on '%s' which is %s", dk, dep.Current())})
+			}
+		}
+	}
+
+	return problems
+}
+
+func isLifecycleStatus(s string) bool {
+	for _, v := range model.LifecycleStatuses {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
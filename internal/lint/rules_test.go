@@ -0,0 +1,193 @@
+package lint_test
+
+import (
+	"github.com/abramsimon/gomponere/internal/lint"
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("Rules", func() {
+	var (
+		d           model.Diagram
+		diagnostics []lint.Diagnostic
+	)
+
+	JustBeforeEach(func() {
+		diagnostics = lint.Run(d, lint.Rules)
+	})
+
+	Context("with a valid diagram", func() {
+		BeforeEach(func() {
+			d = model.Diagram{
+				Teams: map[string]model.Team{
+					"shop": {Name: "Shop", TeamContact: model.TeamContact{Email: "shop@example.com"}, LeadContact: model.TeamContact{Name: "Lead", Email: "lead@example.com"}},
+				},
+				Components: map[string]model.Component{
+					"web": {Name: "Web", TeamKey: "shop", DependencyKeys: []string{"api"}},
+					"api": {Name: "Api", TeamKey: "shop", Lifecycle: model.Lifecycle{Status: model.Active, Dates: map[string]string{model.Active: "2019-01-02"}}},
+				},
+			}
+		})
+
+		It("finds nothing", func() {
+			Expect(diagnostics).To(BeEmpty())
+		})
+	})
+
+	Describe("lifecycle-status", func() {
+		BeforeEach(func() {
+			d = model.Diagram{
+				Teams: map[string]model.Team{"shop": {Name: "Shop", TeamContact: model.TeamContact{Email: "shop@example.com"}}},
+				Components: map[string]model.Component{
+					"web": {Name: "Web", TeamKey: "shop", Lifecycle: model.Lifecycle{
+						Status: "retired",
+						Dates: map[string]string{
+							model.Planned:    "soon",
+							"done":           "2019-01-02",
+							model.Deprecated: "2019-01-02",
+						},
+					}},
+				},
+			}
+		})
+
+		It("reports unknown statuses and bad dates", func() {
+			Expect(diagnostics).To(HaveLen(3))
+			Expect(diagnostics).To(ConsistOf(
+				MatchFields(IgnoreExtras, Fields{"Rule": Equal("lifecycle-status"), "Severity": Equal(lint.Error), "Message": Equal("unknown lifecycle status 'retired'")}),
+				MatchFields(IgnoreExtras, Fields{"Message": Equal("date for unknown lifecycle status 'done'")}),
+				MatchFields(IgnoreExtras, Fields{"Message": Equal("planned date 'soon' is not formatted as YYYY-MM-DD")}),
+			))
+		})
+	})
+
+	Describe("retiring-dependency", func() {
+		BeforeEach(func() {
+			d = model.Diagram{
+				Components: map[string]model.Component{
+					"web":     {Name: "Web", DependencyKeys: []string{"old", "gone", "missing"}},
+					"planned": {Name: "Planned", DependencyKeys: []string{"old"}, Lifecycle: model.Lifecycle{Status: model.Planned}},
+					"old":     {Name: "Old", DependencyKeys: []string{"gone"}, Lifecycle: model.Lifecycle{Status: model.Deprecated}},
+					"gone":    {Name: "Gone", Lifecycle: model.Lifecycle{Status: model.Decommissioned}},
+				},
+			}
+		})
+
+		It("warns about active components only", func() {
+			Expect(byRule(diagnostics, "retiring-dependency")).To(Equal([]lint.Diagnostic{
+				{Rule: "retiring-dependency", Severity: lint.Warning, Kind: "component", Key: "web", Message: "depends on 'gone' which is decommissioned"},
+				{Rule: "retiring-dependency", Severity: lint.Warning, Kind: "component", Key: "web", Message: "depends on 'old' which is deprecated"},
+			}))
+		})
+	})
+
+	Describe("unowned-component", func() {
+		BeforeEach(func() {
+			d = model.Diagram{
+				Teams: map[string]model.Team{"shop": {Name: "Shop", TeamContact: model.TeamContact{Email: "shop@example.com"}}},
+				Components: map[string]model.Component{
+					"web":    {Name: "Web", TeamKey: "shop"},
+					"db":     {Name: "DB"},
+					"queue":  {Name: "Queue", TeamKey: "unassigned"},
+					"search": {Name: "Search", TeamKey: "shpo"},
+				},
+			}
+		})
+
+		It("warns about components without a team", func() {
+			Expect(byRule(diagnostics, "unowned-component")).To(Equal([]lint.Diagnostic{
+				{Rule: "unowned-component", Severity: lint.Warning, Kind: "component", Key: "db", Message: "has no team"},
+				{Rule: "unowned-component", Severity: lint.Warning, Kind: "component", Key: "queue", Message: "has no team"},
+				{Rule: "unowned-component", Severity: lint.Warning, Kind: "component", Key: "search", Message: "has unknown team 'shpo'"},
+			}))
+		})
+	})
+
+	Describe("team-contact and contact-email", func() {
+		BeforeEach(func() {
+			d = model.Diagram{
+				Teams: map[string]model.Team{
+					"quiet":  {Name: "Quiet"},
+					"broken": {Name: "Broken", TeamContact: model.TeamContact{Email: "broken@"}, LeadContact: model.TeamContact{Email: "Lead <lead@example.com>"}},
+					"fine":   {Name: "Fine", TeamContact: model.TeamContact{Email: "fine@example.com"}},
+				},
+				Components: map[string]model.Component{
+					"a": {Name: "A", TeamKey: "quiet"},
+					"b": {Name: "B", TeamKey: "broken"},
+					"c": {Name: "C", TeamKey: "fine"},
+				},
+			}
+		})
+
+		It("reports missing and invalid emails", func() {
+			Expect(diagnostics).To(Equal([]lint.Diagnostic{
+				{Rule: "contact-email", Severity: lint.Error, Kind: "team", Key: "broken", Message: "team contact email 'broken@' is not a valid email address"},
+				{Rule: "contact-email", Severity: lint.Error, Kind: "team", Key: "broken", Message: "lead contact email 'Lead <lead@example.com>' is not a valid email address"},
+				{Rule: "team-contact", Severity: lint.Warning, Kind: "team", Key: "quiet", Message: "has no contact email"},
+			}))
+		})
+	})
+
+	Describe("unused-team", func() {
+		BeforeEach(func() {
+			d = model.Diagram{
+				Teams: map[string]model.Team{
+					"shop": {Name: "Shop", TeamContact: model.TeamContact{Email: "shop@example.com"}},
+					"idle": {Name: "Idle", TeamContact: model.TeamContact{Email: "idle@example.com"}},
+				},
+				Components: map[string]model.Component{
+					"web": {Name: "Web", TeamKey: "shop"},
+				},
+			}
+		})
+
+		It("notes teams that own nothing", func() {
+			Expect(diagnostics).To(Equal([]lint.Diagnostic{
+				{Rule: "unused-team", Severity: lint.Info, Kind: "team", Key: "idle", Message: "owns no components"},
+			}))
+		})
+	})
+
+	Describe("RequireOwnership", func() {
+		BeforeEach(func() {
+			d = model.Diagram{
+				Areas: map[string]model.Area{
+					"prod":  {Name: "Production"},
+					"eu":    {Name: "EU", ParentKey: "prod"},
+					"stage": {Name: "Staging"},
+				},
+				Teams: map[string]model.Team{"shop": {Name: "Shop"}},
+				Components: map[string]model.Component{
+					"web":  {Name: "Web", AreaKey: "eu"},
+					"api":  {Name: "Api", AreaKey: "prod", TeamKey: "shop"},
+					"db":   {Name: "Db", AreaKey: "prod", TeamKey: "unassigned"},
+					"test": {Name: "Test", AreaKey: "stage"},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			diagnostics = lint.Run(d, []lint.Rule{lint.RequireOwnership("prod")})
+		})
+
+		It("fails for unowned components in the area and the areas within it", func() {
+			Expect(diagnostics).To(Equal([]lint.Diagnostic{
+				{Rule: "required-ownership", Severity: lint.Error, Kind: "component", Key: "db", Message: "has no team, but every component in area 'prod' must have one"},
+				{Rule: "required-ownership", Severity: lint.Error, Kind: "component", Key: "web", Message: "has no team, but every component in area 'prod' must have one"},
+			}))
+		})
+	})
+})
+
+func byRule(diagnostics []lint.Diagnostic, rule string) []lint.Diagnostic {
+	found := []lint.Diagnostic{}
+	for _, d := range diagnostics {
+		if d.Rule == rule {
+			found = append(found, d)
+		}
+	}
+
+	return found
+}
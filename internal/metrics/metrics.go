@@ -0,0 +1,264 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/abramsimon/gomponere/internal/model"
+)
+
+// the metrics that can be chosen for a heat map
+const (
+	Afferent    = "afferent"
+	Efferent    = "efferent"
+	Instability = "instability"
+	Depth       = "depth"
+)
+
+// Names are the names of the metrics that can be chosen for a heat map
+var Names = []string{Afferent, Efferent, Instability, Depth}
+
+// Component holds the metrics of a single component
+type Component struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	Area string `json:"area"`
+	Team string `json:"team"`
+
+	// Afferent is the number of components that depend on this one (Ca)
+	Afferent int `json:"afferent"`
+
+	// Efferent is the number of components this one depends on (Ce)
+	Efferent int `json:"efferent"`
+
+	// Instability is Ce / (Ca + Ce), from 0 for components that depend on nothing to 1 for components nothing depends on
+	// isolated components are 0
+	Instability float64 `json:"instability"`
+
+	// Depth is the length of the longest chain of dependencies below the component, 0 when it has none
+	Depth int `json:"depth"`
+
+	// LongestPath is true when the component is on the longest chain of dependencies in the diagram
+	LongestPath bool `json:"longest-path"`
+}
+
+// Group holds the metrics of the components in an area or owned by a team
+// coupling only counts dependencies crossing the boundary of the group
+type Group struct {
+	Key         string  `json:"key"`
+	Name        string  `json:"name"`
+	Components  int     `json:"components"`
+	Afferent    int     `json:"afferent"`
+	Efferent    int     `json:"efferent"`
+	Instability float64 `json:"instability"`
+	Depth       int     `json:"depth"`
+	LongestPath int     `json:"longest-path"`
+}
+
+// Report holds the metrics of every component, area and team
+type Report struct {
+	Components  []Component `json:"components"`
+	Areas       []Group     `json:"areas"`
+	Teams       []Group     `json:"teams"`
+	LongestPath []string    `json:"longest-path"`
+}
+
+// Compute calculates the metrics for the diagram
+// dependencies on components that are not in the diagram are ignored, and so are the dependencies that close a cycle
+func Compute(d model.Diagram) Report {
+	keys := d.ComponentKeys()
+
+	depths := depths(d, keys)
+	path := longestPath(d, keys, depths)
+
+	r := Report{Components: []Component{}, LongestPath: path}
+	for _, k := range keys {
+		c := d.Components[k]
+		ca, ce := len(d.Dependents(k)), len(d.Dependencies(k))
+
+		r.Components = append(r.Components, Component{
+			Key:         k,
+			Name:        c.Name,
+			Area:        c.AreaKey,
+			Team:        c.TeamKey,
+			Afferent:    ca,
+			Efferent:    ce,
+			Instability: instability(ca, ce),
+			Depth:       depths[k],
+			LongestPath: contains(path, k),
+		})
+	}
+
+	areas := map[string]string{}
+	for k, a := range d.Areas {
+		areas[k] = a.Name
+	}
+	r.Areas = groups(d, r.Components, areas, func(c Component, key string) bool {
+		return d.InArea(c.Area, key)
+	})
+
+	teams := map[string]string{}
+	for k, t := range d.Teams {
+		teams[k] = t.Name
+	}
+	r.Teams = groups(d, r.Components, teams, func(c Component, key string) bool {
+		return c.Team == key
+	})
+
+	return r
+}
+
+// Value returns the value of the named metric for the component
+func (c Component) Value(metric string) (float64, error) {
+	switch metric {
+	case Afferent:
+		return float64(c.Afferent), nil
+	case Efferent:
+		return float64(c.Efferent), nil
+	case Instability:
+		return c.Instability, nil
+	case Depth:
+		return float64(c.Depth), nil
+	}
+
+	return 0, fmt.Errorf("unknown metric '%s'", metric)
+}
+
+func instability(ca int, ce int) float64 {
+	if ca+ce == 0 {
+		return 0
+	}
+
+	return float64(ce) / float64(ca+ce)
+}
+
+// depths returns the length of the longest chain of dependencies below each component
+func depths(d model.Diagram, keys []string) map[string]int {
+	depths := map[string]int{}
+	visiting := map[string]bool{}
+
+	var visit func(k string) int
+	visit = func(k string) int {
+		if depth, done := depths[k]; done {
+			return depth
+		}
+		if visiting[k] {
+			// a cycle, which is not followed any further
+			return -1
+		}
+		visiting[k] = true
+
+		depth := 0
+		for _, dk := range d.Dependencies(k) {
+			if child := visit(dk); child+1 > depth {
+				depth = child + 1
+			}
+		}
+
+		visiting[k] = false
+		depths[k] = depth
+		return depth
+	}
+
+	for _, k := range keys {
+		visit(k)
+	}
+
+	return depths
+}
+
+// longestPath returns the keys of the components on the longest chain of dependencies, from the top down
+// when there are several, the first in key order is used
+func longestPath(d model.Diagram, keys []string, depths map[string]int) []string {
+	path := []string{}
+	if len(keys) == 0 {
+		return path
+	}
+
+	start := keys[0]
+	for _, k := range keys {
+		if depths[k] > depths[start] {
+			start = k
+		}
+	}
+
+	for k := start; ; {
+		path = append(path, k)
+		if depths[k] == 0 {
+			break
+		}
+
+		for _, dk := range d.Dependencies(k) {
+			if depths[dk] == depths[k]-1 && !contains(path, dk) {
+				k = dk
+				break
+			}
+		}
+		if k == path[len(path)-1] {
+			break
+		}
+	}
+
+	return path
+}
+
+// groups aggregates the metrics of the components in each of the named groups
+func groups(d model.Diagram, components []Component, names map[string]string, in func(c Component, key string) bool) []Group {
+	keys := make([]string, 0, len(names))
+	for k := range names {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	groups := []Group{}
+	for _, k := range keys {
+		g := Group{Key: k, Name: names[k]}
+
+		members := map[string]bool{}
+		for _, c := range components {
+			if in(c, k) {
+				members[c.Key] = true
+			}
+		}
+
+		for _, c := range components {
+			if !members[c.Key] {
+				continue
+			}
+
+			g.Components++
+			if c.Depth > g.Depth {
+				g.Depth = c.Depth
+			}
+			if c.LongestPath {
+				g.LongestPath++
+			}
+
+			for _, dk := range d.Dependencies(c.Key) {
+				if !members[dk] {
+					g.Efferent++
+				}
+			}
+			for _, dk := range d.Dependents(c.Key) {
+				if !members[dk] {
+					g.Afferent++
+				}
+			}
+		}
+		g.Instability = instability(g.Afferent, g.Efferent)
+
+		groups = append(groups, g)
+	}
+
+	return groups
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,182 @@
+package metrics_test
+
+import (
+	"bytes"
+
+	"github.com/abramsimon/gomponere/internal/diagram"
+	"github.com/abramsimon/gomponere/internal/metrics"
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("Metrics", func() {
+	var d model.Diagram
+
+	BeforeEach(func() {
+		d = model.Diagram{
+			Areas: map[string]model.Area{
+				"shop":    {Name: "Shop"},
+				"front":   {Name: "Front", ParentKey: "shop"},
+				"backend": {Name: "Backend", ParentKey: "shop"},
+			},
+			Teams: map[string]model.Team{
+				"web":    {Name: "Web"},
+				"orders": {Name: "Orders"},
+			},
+			Components: map[string]model.Component{
+				"web":    {Name: "Web", AreaKey: "front", TeamKey: "web", DependencyKeys: []string{"api", "auth"}},
+				"mobile": {Name: "Mobile", AreaKey: "front", TeamKey: "web", DependencyKeys: []string{"api"}},
+				"api":    {Name: "Api", AreaKey: "backend", TeamKey: "orders", DependencyKeys: []string{"db", "missing"}},
+				"auth":   {Name: "Auth", AreaKey: "backend", TeamKey: "orders"},
+				"db":     {Name: "Db", AreaKey: "backend", TeamKey: "orders"},
+				"lonely": {Name: "Lonely", AreaKey: "backend"},
+			},
+		}
+	})
+
+	Describe("Compute", func() {
+		var r metrics.Report
+
+		JustBeforeEach(func() {
+			r = metrics.Compute(d)
+		})
+
+		component := func(key string) metrics.Component {
+			for _, c := range r.Components {
+				if c.Key == key {
+					return c
+				}
+			}
+			return metrics.Component{}
+		}
+		group := func(groups []metrics.Group, key string) metrics.Group {
+			for _, g := range groups {
+				if g.Key == key {
+					return g
+				}
+			}
+			return metrics.Group{}
+		}
+
+		It("computes coupling", func() {
+			Expect(component("api")).To(MatchFields(IgnoreExtras, Fields{
+				"Afferent":    Equal(2),
+				"Efferent":    Equal(1),
+				"Instability": BeNumerically("~", 1.0/3),
+			}))
+			Expect(component("db").Instability).To(Equal(0.0))
+			Expect(component("web").Instability).To(Equal(1.0))
+			Expect(component("lonely").Instability).To(Equal(0.0))
+		})
+		It("computes depth", func() {
+			Expect(component("web").Depth).To(Equal(2))
+			Expect(component("api").Depth).To(Equal(1))
+			Expect(component("db").Depth).To(Equal(0))
+		})
+		It("finds the longest path", func() {
+			Expect(r.LongestPath).To(Equal([]string{"mobile", "api", "db"}))
+			Expect(component("mobile").LongestPath).To(BeTrue())
+			Expect(component("web").LongestPath).To(BeFalse())
+		})
+		It("aggregates per area, counting dependencies across its boundary", func() {
+			Expect(group(r.Areas, "front")).To(Equal(metrics.Group{
+				Key: "front", Name: "Front", Components: 2, Afferent: 0, Efferent: 3, Instability: 1, Depth: 2, LongestPath: 1,
+			}))
+			Expect(group(r.Areas, "backend")).To(MatchFields(IgnoreExtras, Fields{
+				"Components": Equal(4),
+				"Afferent":   Equal(3),
+				"Efferent":   Equal(0),
+			}))
+			Expect(group(r.Areas, "shop").Components).To(Equal(6))
+		})
+		It("aggregates per team", func() {
+			Expect(group(r.Teams, "orders")).To(MatchFields(IgnoreExtras, Fields{
+				"Components":  Equal(3),
+				"Afferent":    Equal(3),
+				"LongestPath": Equal(2),
+			}))
+		})
+
+		Context("with a cycle", func() {
+			BeforeEach(func() {
+				db := d.Components["db"]
+				db.DependencyKeys = []string{"api"}
+				d.Components["db"] = db
+			})
+
+			It("does not follow it", func() {
+				Expect(component("web").Depth).To(Equal(2))
+				Expect(r.LongestPath).To(HaveLen(3))
+			})
+		})
+	})
+
+	Describe("WriteTable", func() {
+		It("writes aligned rows", func() {
+			var b bytes.Buffer
+			err := metrics.WriteTable(&b, metrics.Compute(d), metrics.ByTeam)
+			Expect(err).To(BeNil())
+			Expect(b.String()).To(Equal(
+				"key     name    components  afferent  efferent  instability  depth  longest-path\n" +
+					"orders  Orders  3           3         0         0.00         1      2\n" +
+					"web     Web     2           0         3         1.00         2      1\n"))
+		})
+		It("errors on unknown groupings", func() {
+			err := metrics.WriteTable(&bytes.Buffer{}, metrics.Compute(d), "level")
+			Expect(err).ToNot(BeNil())
+		})
+	})
+
+	Describe("WriteCSV", func() {
+		It("writes a header and a row per component", func() {
+			var b bytes.Buffer
+			err := metrics.WriteCSV(&b, metrics.Compute(d), metrics.ByComponent)
+			Expect(err).To(BeNil())
+			Expect(b.String()).To(HavePrefix("key,name,area,team,afferent,efferent,instability,depth,longest-path\napi,Api,backend,orders,2,1,0.33,1,true\n"))
+		})
+	})
+
+	Describe("MakeHeatMap", func() {
+		It("colors nodes by the metric", func() {
+			dot, err := metrics.MakeHeatMap(d, metrics.Depth)
+			Expect(err).To(BeNil())
+			Expect(dot).To(ContainSubstring(`fillcolor="/ylorrd9/9",fontcolor="white",label="Web\ndepth 2"`))
+			Expect(dot).To(ContainSubstring(`fillcolor="/ylorrd9/1",fontcolor="black",label="Db\ndepth 0"`))
+		})
+		It("shows the values of the colors in the legend instead of the teams", func() {
+			dot, err := metrics.MakeHeatMap(d, metrics.Depth)
+			Expect(err).To(BeNil())
+			Expect(dot).To(ContainSubstring(`label="depth"`))
+			Expect(dot).To(ContainSubstring(`[fillcolor="/ylorrd9/1",fontcolor="black",label="0",shape="box",style="filled"]`))
+			Expect(dot).To(ContainSubstring(`[fillcolor="/ylorrd9/5",fontcolor="black",label="1",shape="box",style="filled"]`))
+			Expect(dot).To(ContainSubstring(`[fillcolor="/ylorrd9/9",fontcolor="white",label="2",shape="box",style="filled"]`))
+			Expect(dot).ToNot(ContainSubstring(`label="Orders"`))
+		})
+		It("errors on unknown metrics", func() {
+			_, err := metrics.MakeHeatMap(d, "complexity")
+			Expect(err).ToNot(BeNil())
+		})
+
+		Context("with the legend turned off", func() {
+			It("leaves it out", func() {
+				dot, err := metrics.MakeHeatMapWithOptions(d, metrics.Depth, diagram.Options{Legend: diagram.LegendOff})
+				Expect(err).To(BeNil())
+				Expect(dot).ToNot(ContainSubstring(`label="Legend"`))
+				Expect(dot).To(ContainSubstring(`label="Web\ndepth 2"`))
+			})
+		})
+
+		Context("with only the legend", func() {
+			It("leaves the diagram out", func() {
+				dot, err := metrics.MakeHeatMapWithOptions(d, metrics.Depth, diagram.Options{Legend: diagram.LegendOnly})
+				Expect(err).To(BeNil())
+				Expect(dot).To(ContainSubstring(`label="Legend"`))
+				Expect(dot).To(ContainSubstring(`label="depth"`))
+				Expect(dot).ToNot(ContainSubstring(`label="Shop"`))
+				Expect(dot).ToNot(ContainSubstring(`depth 2`))
+			})
+		})
+	})
+})
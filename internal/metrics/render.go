@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/abramsimon/gomponere/internal/diagram"
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/emicklei/dot"
+)
+
+// heatScheme is the graphviz color scheme used for heat maps, from pale yellow to dark red
+const heatScheme = "ylorrd9"
+
+// MakeHeatMap renders the diagram with each component filled with a color for its value of the metric instead of its
+// team's color, from pale yellow for the lowest value to dark red for the highest, and labeled with the value
+// components on the longest path are drawn with a thick border, and the legend shows the values of the colors used
+// instead of the teams
+func MakeHeatMap(d model.Diagram, metric string) (string, error) {
+	return MakeHeatMapWithOptions(d, metric, diagram.Options{})
+}
+
+// MakeHeatMapWithOptions renders the heat map like MakeHeatMap with the theme and legend of the options
+func MakeHeatMapWithOptions(d model.Diagram, metric string, opts diagram.Options) (string, error) {
+	r := Compute(d)
+
+	values := map[string]float64{}
+	max := 0.0
+	for _, c := range r.Components {
+		v, err := c.Value(metric)
+		if err != nil {
+			return "", err
+		}
+		values[c.Key] = v
+		max = math.Max(max, v)
+	}
+
+	return diagram.MakeDotWithOptions(d, diagram.Options{
+		Theme:   opts.Theme,
+		Legend:  opts.Legend,
+		AreaURL: opts.AreaURL,
+		Scale:   scale(metric, values, max),
+		NodeStyle: func(key string, c model.Component, n dot.Node) {
+			v := values[key]
+			s := shade(v, max)
+
+			n.Attr("fillcolor", fillColor(s)).
+				Attr("fontcolor", fontColor(s)).
+				Attr("label", fmt.Sprintf("%s\n%s %s", n.Value("label"), metric, format(v)))
+
+			if contains(r.LongestPath, key) {
+				n.Attr("penwidth", "3")
+			}
+		},
+	})
+}
+
+// scale returns a step for each shade used in the heat map, labeled with the values that got it
+func scale(metric string, values map[string]float64, max float64) *diagram.Scale {
+	lowest, highest := map[int]float64{}, map[int]float64{}
+	for _, v := range values {
+		s := shade(v, max)
+		if l, exists := lowest[s]; !exists || v < l {
+			lowest[s] = v
+		}
+		highest[s] = math.Max(highest[s], v)
+	}
+
+	steps := []diagram.ScaleStep{}
+	for s := 1; s <= 9; s++ {
+		l, exists := lowest[s]
+		if !exists {
+			continue
+		}
+
+		label := format(l)
+		if h := highest[s]; h != l {
+			label += " to " + format(h)
+		}
+		steps = append(steps, diagram.ScaleStep{Label: label, FillColor: fillColor(s), FontColor: fontColor(s)})
+	}
+
+	return &diagram.Scale{Title: metric, Steps: steps}
+}
+
+// shade returns 1 to 9 in the color scheme for the value, with everything at 1 when all values are 0
+func shade(v float64, max float64) int {
+	if max == 0 {
+		return 1
+	}
+
+	return 1 + int(math.Round(8*v/max))
+}
+
+func fillColor(shade int) string {
+	return fmt.Sprintf("/%s/%d", heatScheme, shade)
+}
+
+// fontColor keeps labels readable on the darker shades
+func fontColor(shade int) string {
+	if shade > 6 {
+		return "white"
+	}
+
+	return "black"
+}
+
+// format writes whole numbers without decimals
+func format(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprint(int(v))
+	}
+
+	return ratio(v)
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+// what the rows of a table can be
+const (
+	ByComponent = "component"
+	ByArea      = "area"
+	ByTeam      = "team"
+)
+
+// WriteTable writes the metrics of the components, areas or teams as an aligned table
+func WriteTable(w io.Writer, r Report, by string) error {
+	rows, err := rows(r, by)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		for i, cell := range row {
+			if i > 0 {
+				fmt.Fprint(tw, "\t")
+			}
+			fmt.Fprint(tw, cell)
+		}
+		fmt.Fprintln(tw)
+	}
+
+	return tw.Flush()
+}
+
+// WriteCSV writes the metrics of the components, areas or teams as csv with a header row
+func WriteCSV(w io.Writer, r Report, by string) error {
+	rows, err := rows(r, by)
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+
+	return cw.Error()
+}
+
+// rows returns the header and a row per component, area or team
+func rows(r Report, by string) ([][]string, error) {
+	switch by {
+	case ByComponent:
+		rows := [][]string{{"key", "name", "area", "team", "afferent", "efferent", "instability", "depth", "longest-path"}}
+		for _, c := range r.Components {
+			rows = append(rows, []string{
+				c.Key, c.Name, c.Area, c.Team,
+				strconv.Itoa(c.Afferent), strconv.Itoa(c.Efferent), ratio(c.Instability),
+				strconv.Itoa(c.Depth), strconv.FormatBool(c.LongestPath),
+			})
+		}
+		return rows, nil
+	case ByArea:
+		return groupRows(r.Areas), nil
+	case ByTeam:
+		return groupRows(r.Teams), nil
+	}
+
+	return nil, fmt.Errorf("unknown grouping '%s', expected component, area or team", by)
+}
+
+func groupRows(groups []Group) [][]string {
+	rows := [][]string{{"key", "name", "components", "afferent", "efferent", "instability", "depth", "longest-path"}}
+	for _, g := range groups {
+		rows = append(rows, []string{
+			g.Key, g.Name, strconv.Itoa(g.Components),
+			strconv.Itoa(g.Afferent), strconv.Itoa(g.Efferent), ratio(g.Instability),
+			strconv.Itoa(g.Depth), strconv.Itoa(g.LongestPath),
+		})
+	}
+
+	return rows
+}
+
+func ratio(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
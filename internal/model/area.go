@@ -1,6 +1,9 @@
 package model
 
 type Area struct {
-	Name      string `yaml:"name"`
-	ParentKey string `yaml:"parent"`
+	Name       string            `yaml:"name" json:"name"`
+	ParentKey  string            `yaml:"parent,omitempty" json:"parent"`
+	Tags       []string          `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Links      []Link            `yaml:"links,omitempty" json:"links,omitempty"`
+	Properties map[string]string `yaml:"properties,omitempty" json:"properties,omitempty"`
 }
@@ -1,13 +1,17 @@
 package model
 
 type Component struct {
-	Name           string   `yaml:"name"`
-	Description    string   `yaml:"description"`
-	Git            string   `yaml:"git"`
-	ReleaseDate    string   `yaml:"release-date"`
-	LevelKey       string   `yaml:"level"`
-	TypeKey        string   `yaml:"type"`
-	TeamKey        string   `yaml:"team"`
-	AreaKey        string   `yaml:"area"`
-	DependencyKeys []string `yaml:"dependencies"`
+	Name           string            `yaml:"name" json:"name"`
+	Description    string            `yaml:"description,omitempty" json:"description"`
+	Git            string            `yaml:"git,omitempty" json:"git"`
+	ReleaseDate    string            `yaml:"release-date,omitempty" json:"release-date"`
+	LevelKey       string            `yaml:"level,omitempty" json:"level"`
+	TypeKey        string            `yaml:"type,omitempty" json:"type"`
+	TeamKey        string            `yaml:"team,omitempty" json:"team"`
+	AreaKey        string            `yaml:"area,omitempty" json:"area"`
+	DependencyKeys []string          `yaml:"dependencies,omitempty" json:"dependencies"`
+	Lifecycle      Lifecycle         `yaml:"lifecycle,omitempty" json:"lifecycle"`
+	Tags           []string          `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Links          []Link            `yaml:"links,omitempty" json:"links,omitempty"`
+	Properties     map[string]string `yaml:"properties,omitempty" json:"properties,omitempty"`
 }
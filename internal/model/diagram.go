@@ -1,9 +1,116 @@
 package model
 
+import "sort"
+
 type Diagram struct {
-	Areas      map[string]Area
-	Components map[string]Component
-	Levels     map[string]Level
-	Teams      map[string]Team
-	Types      map[string]Type
+	Areas      map[string]Area      `yaml:"areas,omitempty" json:"areas"`
+	Components map[string]Component `yaml:"components,omitempty" json:"components"`
+	Levels     map[string]Level     `yaml:"levels,omitempty" json:"levels"`
+	Teams      map[string]Team      `yaml:"teams,omitempty" json:"teams"`
+	Types      map[string]Type      `yaml:"types,omitempty" json:"types"`
+	Styles     []Style              `yaml:"styles,omitempty" json:"styles,omitempty"`
+}
+
+// ComponentKeys returns the keys of all components in a stable order
+func (d Diagram) ComponentKeys() []string {
+	keys := make([]string, 0, len(d.Components))
+	for k := range d.Components {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Dependencies returns the keys of the components the given component depends on
+// only dependencies that exist in the diagram are returned
+func (d Diagram) Dependencies(key string) []string {
+	keys := []string{}
+	for _, k := range d.Components[key].DependencyKeys {
+		if _, exists := d.Components[k]; exists {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Dependents returns the keys of the components that depend on the given component
+func (d Diagram) Dependents(key string) []string {
+	keys := []string{}
+	for k, c := range d.Components {
+		for _, dk := range c.DependencyKeys {
+			if dk == key {
+				keys = append(keys, k)
+				break
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// AreaAncestors returns the keys of the given area and all of its parents, starting with the area itself
+func (d Diagram) AreaAncestors(key string) []string {
+	keys := []string{}
+	seen := map[string]bool{}
+	for k := key; k != "" && !seen[k]; k = d.Areas[k].ParentKey {
+		if _, exists := d.Areas[k]; !exists {
+			break
+		}
+		seen[k] = true
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// InArea returns true if the given area is the area with areaKey or one of its descendants
+func (d Diagram) InArea(key string, areaKey string) bool {
+	for _, k := range d.AreaAncestors(key) {
+		if k == areaKey {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Subset returns a copy of the diagram containing only the components that match keep
+// dependencies on removed components are dropped, as are areas that no longer contain any components
+func (d Diagram) Subset(keep func(key string, component Component) bool) Diagram {
+	sub := Diagram{
+		Areas:      map[string]Area{},
+		Components: map[string]Component{},
+		Levels:     d.Levels,
+		Teams:      d.Teams,
+		Types:      d.Types,
+		Styles:     d.Styles,
+	}
+
+	for k, c := range d.Components {
+		if keep(k, c) {
+			sub.Components[k] = c
+		}
+	}
+
+	for k, c := range sub.Components {
+		deps := []string{}
+		for _, dk := range c.DependencyKeys {
+			if _, exists := sub.Components[dk]; exists {
+				deps = append(deps, dk)
+			}
+		}
+		c.DependencyKeys = deps
+		sub.Components[k] = c
+
+		// keep the area the component lives in along with all of its parents
+		for _, ak := range d.AreaAncestors(c.AreaKey) {
+			sub.Areas[ak] = d.Areas[ak]
+		}
+	}
+
+	return sub
 }
@@ -0,0 +1,133 @@
+package model_test
+
+import (
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diagram", func() {
+	var (
+		d model.Diagram
+	)
+
+	BeforeEach(func() {
+		d = model.Diagram{
+			Areas: map[string]model.Area{
+				"root":   {Name: "Root"},
+				"child":  {Name: "Child", ParentKey: "root"},
+				"other":  {Name: "Other"},
+				"orphan": {Name: "Orphan", ParentKey: "missing"},
+			},
+			Components: map[string]model.Component{
+				"web": {Name: "Web", AreaKey: "child", DependencyKeys: []string{"api", "missing"}},
+				"api": {Name: "Api", AreaKey: "child", DependencyKeys: []string{"db"}},
+				"db":  {Name: "Database", AreaKey: "root"},
+				"ops": {Name: "Ops", AreaKey: "other", DependencyKeys: []string{"db"}},
+			},
+		}
+	})
+
+	Describe("ComponentKeys", func() {
+		It("returns sorted keys", func() {
+			Expect(d.ComponentKeys()).To(Equal([]string{"api", "db", "ops", "web"}))
+		})
+	})
+
+	Describe("Dependencies", func() {
+		It("returns known dependencies", func() {
+			Expect(d.Dependencies("web")).To(Equal([]string{"api"}))
+		})
+		It("returns nothing for unknown components", func() {
+			Expect(d.Dependencies("nope")).To(BeEmpty())
+		})
+	})
+
+	Describe("Dependents", func() {
+		It("returns the components depending on the component", func() {
+			Expect(d.Dependents("db")).To(Equal([]string{"api", "ops"}))
+		})
+		It("returns nothing when nothing depends on the component", func() {
+			Expect(d.Dependents("web")).To(BeEmpty())
+		})
+	})
+
+	Describe("AreaAncestors", func() {
+		It("walks up to the root", func() {
+			Expect(d.AreaAncestors("child")).To(Equal([]string{"child", "root"}))
+		})
+		It("stops at missing parents", func() {
+			Expect(d.AreaAncestors("orphan")).To(Equal([]string{"orphan"}))
+		})
+		It("stops on cycles", func() {
+			d.Areas["root"] = model.Area{Name: "Root", ParentKey: "child"}
+			Expect(d.AreaAncestors("child")).To(Equal([]string{"child", "root"}))
+		})
+	})
+
+	Describe("InArea", func() {
+		It("includes the area itself", func() {
+			Expect(d.InArea("root", "root")).To(BeTrue())
+		})
+		It("includes descendants", func() {
+			Expect(d.InArea("child", "root")).To(BeTrue())
+		})
+		It("excludes unrelated areas", func() {
+			Expect(d.InArea("other", "root")).To(BeFalse())
+		})
+	})
+
+	Describe("Subset", func() {
+		var (
+			sub model.Diagram
+		)
+
+		JustBeforeEach(func() {
+			sub = d.Subset(func(k string, c model.Component) bool {
+				return k == "web" || k == "api"
+			})
+		})
+
+		It("keeps matching components", func() {
+			Expect(sub.Components).To(HaveLen(2))
+			Expect(sub.Components).To(HaveKey("web"))
+			Expect(sub.Components).To(HaveKey("api"))
+		})
+		It("drops dependencies on removed components", func() {
+			Expect(sub.Components["web"].DependencyKeys).To(Equal([]string{"api"}))
+			Expect(sub.Components["api"].DependencyKeys).To(BeEmpty())
+		})
+		It("keeps only the areas containing components", func() {
+			Expect(sub.Areas).To(HaveLen(2))
+			Expect(sub.Areas).To(HaveKey("child"))
+			Expect(sub.Areas).To(HaveKey("root"))
+		})
+		It("does not modify the original", func() {
+			Expect(d.Components["web"].DependencyKeys).To(Equal([]string{"api", "missing"}))
+		})
+	})
+})
+
+var _ = Describe("HasOwner", func() {
+	d := model.Diagram{
+		Teams: map[string]model.Team{
+			"shop":           {Name: "Shop"},
+			model.Unassigned: {Name: "Unassigned"},
+		},
+		Components: map[string]model.Component{
+			"web":    {Name: "Web", TeamKey: "shop"},
+			"db":     {Name: "Db"},
+			"queue":  {Name: "Queue", TeamKey: model.Unassigned},
+			"search": {Name: "Search", TeamKey: "shpo"},
+		},
+	}
+
+	It("is true for components of a declared team", func() {
+		Expect(d.HasOwner("web")).To(BeTrue())
+	})
+	It("is false for components without a team, with the placeholder or with an unknown team", func() {
+		for _, k := range []string{"db", "queue", "search", "missing"} {
+			Expect(d.HasOwner(k)).To(BeFalse(), k)
+		}
+	})
+})
@@ -1,6 +1,6 @@
 package model
 
 type Level struct {
-	Name  string `yaml:"name"`
-	Order int    `yaml:"order"`
+	Name  string `yaml:"name" json:"name"`
+	Order int    `yaml:"order,omitempty" json:"order"`
 }
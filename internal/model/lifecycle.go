@@ -0,0 +1,38 @@
+package model
+
+// the lifecycle statuses a component moves through, in order
+const (
+	Planned        = "planned"
+	InDevelopment  = "in-development"
+	Active         = "active"
+	Deprecated     = "deprecated"
+	Decommissioned = "decommissioned"
+)
+
+// LifecycleStatuses lists every valid status in the order a component moves through them
+var LifecycleStatuses = []string{Planned, InDevelopment, Active, Deprecated, Decommissioned}
+
+// LifecycleDateFormat is the format of the dates in a lifecycle
+const LifecycleDateFormat = "2006-01-02"
+
+type Lifecycle struct {
+	Status string `yaml:"status,omitempty" json:"status,omitempty"`
+
+	// Dates maps a status to when the component entered it, or is expected to
+	Dates map[string]string `yaml:"dates,omitempty" json:"dates,omitempty"`
+}
+
+// Current returns the status of the component, components without a status are considered active
+func (l Lifecycle) Current() string {
+	if l.Status == "" {
+		return Active
+	}
+
+	return l.Status
+}
+
+// IsRetiring returns true when the component is deprecated or decommissioned
+func (l Lifecycle) IsRetiring() bool {
+	s := l.Current()
+	return s == Deprecated || s == Decommissioned
+}
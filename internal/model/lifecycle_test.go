@@ -0,0 +1,22 @@
+package model_test
+
+import (
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lifecycle", func() {
+	It("defaults to active", func() {
+		Expect(model.Lifecycle{}.Current()).To(Equal(model.Active))
+		Expect(model.Lifecycle{}.IsRetiring()).To(BeFalse())
+	})
+	It("uses the status", func() {
+		Expect(model.Lifecycle{Status: model.Planned}.Current()).To(Equal(model.Planned))
+	})
+	It("knows when a component is retiring", func() {
+		Expect(model.Lifecycle{Status: model.Deprecated}.IsRetiring()).To(BeTrue())
+		Expect(model.Lifecycle{Status: model.Decommissioned}.IsRetiring()).To(BeTrue())
+		Expect(model.Lifecycle{Status: model.InDevelopment}.IsRetiring()).To(BeFalse())
+	})
+})
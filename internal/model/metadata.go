@@ -0,0 +1,22 @@
+package model
+
+// Link points to something about an entity, like its runbook, dashboard or on-call rota
+type Link struct {
+	Name string `yaml:"name,omitempty" json:"name"`
+	URL  string `yaml:"url" json:"url"`
+}
+
+// HasTag returns true when tag is one of the tags
+func HasTag(tags []string, tag string) bool {
+	return contains(tags, tag)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,108 @@
+package model
+
+import "sort"
+
+// the border styles a style can give
+const (
+	Solid  = "solid"
+	Dashed = "dashed"
+	Dotted = "dotted"
+	Bold   = "bold"
+)
+
+// BorderStyles lists every valid border style
+var BorderStyles = []string{Solid, Dashed, Dotted, Bold}
+
+// Style changes how the components its selector matches are drawn, and the dependencies on them
+type Style struct {
+	Select      Selector  `yaml:"select" json:"select"`
+	Fill        string    `yaml:"fill,omitempty" json:"fill,omitempty"`
+	BorderColor string    `yaml:"border-color,omitempty" json:"border-color,omitempty"`
+	BorderStyle string    `yaml:"border-style,omitempty" json:"border-style,omitempty"`
+	Font        string    `yaml:"font,omitempty" json:"font,omitempty"`
+	FontColor   string    `yaml:"font-color,omitempty" json:"font-color,omitempty"`
+	Shape       string    `yaml:"shape,omitempty" json:"shape,omitempty"`
+	Icon        string    `yaml:"icon,omitempty" json:"icon,omitempty"`
+	Edge        EdgeStyle `yaml:"edge,omitempty" json:"edge,omitempty"`
+}
+
+// EdgeStyle changes how the dependencies on a component are drawn
+type EdgeStyle struct {
+	Color string `yaml:"color,omitempty" json:"color,omitempty"`
+	Style string `yaml:"style,omitempty" json:"style,omitempty"`
+	Width string `yaml:"width,omitempty" json:"width,omitempty"`
+}
+
+// Selector picks what a style applies to, everything that matches all of its fields
+// an empty selector matches every component
+type Selector struct {
+	Tag       string `yaml:"tag,omitempty" json:"tag,omitempty"`
+	Type      string `yaml:"type,omitempty" json:"type,omitempty"`
+	Level     string `yaml:"level,omitempty" json:"level,omitempty"`
+	Team      string `yaml:"team,omitempty" json:"team,omitempty"`
+	Area      string `yaml:"area,omitempty" json:"area,omitempty"`
+	Lifecycle string `yaml:"lifecycle,omitempty" json:"lifecycle,omitempty"`
+}
+
+// precedence returns how much the selector outranks others, from the highest ranked kind of field it uses and then
+// how many fields it uses
+// the kinds rank level, area, type, team, lifecycle and then tag
+func (s Selector) precedence() int {
+	p, n := 0, 0
+	for i, v := range []string{s.Level, s.Area, s.Type, s.Team, s.Lifecycle, s.Tag} {
+		if v != "" {
+			p = i + 1
+			n++
+		}
+	}
+
+	return p*10 + n
+}
+
+// MatchesComponent returns true when the selector matches the component, areas match the components in them and in
+// the areas inside them
+func (s Selector) MatchesComponent(d Diagram, key string) bool {
+	c := d.Components[key]
+	return (s.Tag == "" || HasTag(c.Tags, s.Tag)) &&
+		(s.Type == "" || s.Type == c.TypeKey) &&
+		(s.Level == "" || s.Level == c.LevelKey) &&
+		(s.Team == "" || s.Team == c.TeamKey) &&
+		(s.Area == "" || d.InArea(c.AreaKey, s.Area)) &&
+		(s.Lifecycle == "" || s.Lifecycle == c.Lifecycle.Current())
+}
+
+// MatchesTeam returns true when the selector only uses a team or tag and matches the team, tags are matched against
+// the team's own tags
+func (s Selector) MatchesTeam(d Diagram, key string) bool {
+	if s.Type != "" || s.Level != "" || s.Area != "" || s.Lifecycle != "" || (s.Team == "" && s.Tag == "") {
+		return false
+	}
+
+	return (s.Tag == "" || HasTag(d.Teams[key].Tags, s.Tag)) && (s.Team == "" || s.Team == key)
+}
+
+// ComponentStyles returns the styles that apply to the component in the order they cascade, so later styles win
+// styles are ordered by the precedence of their selectors, and styles with the same precedence keep the order they
+// were declared in
+func (d Diagram) ComponentStyles(key string) []Style {
+	return d.styles(func(s Selector) bool { return s.MatchesComponent(d, key) })
+}
+
+// TeamStyles returns the styles that apply to the team in the order they cascade, like ComponentStyles
+func (d Diagram) TeamStyles(key string) []Style {
+	return d.styles(func(s Selector) bool { return s.MatchesTeam(d, key) })
+}
+
+func (d Diagram) styles(match func(s Selector) bool) []Style {
+	styles := []Style{}
+	for _, s := range d.Styles {
+		if match(s.Select) {
+			styles = append(styles, s)
+		}
+	}
+	sort.SliceStable(styles, func(i, j int) bool {
+		return styles[i].Select.precedence() < styles[j].Select.precedence()
+	})
+
+	return styles
+}
@@ -0,0 +1,62 @@
+package model_test
+
+import (
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Style", func() {
+	var (
+		d model.Diagram
+	)
+
+	BeforeEach(func() {
+		d = model.Diagram{
+			Areas: map[string]model.Area{
+				"prod":    {Name: "Production"},
+				"prod-eu": {Name: "Production EU", ParentKey: "prod"},
+			},
+			Teams: map[string]model.Team{
+				"shop":     {Name: "Shop", Tags: []string{"pci"}},
+				"platform": {Name: "Platform"},
+			},
+			Components: map[string]model.Component{
+				"web": {Name: "Web", TypeKey: "app", TeamKey: "shop", AreaKey: "prod-eu", Tags: []string{"pci"}},
+				"api": {Name: "Api", TeamKey: "platform", AreaKey: "prod", Lifecycle: model.Lifecycle{Status: model.Deprecated}},
+			},
+			Styles: []model.Style{
+				{Select: model.Selector{Tag: "pci"}, BorderColor: "red"},
+				{Select: model.Selector{Team: "shop"}, Fill: "blue"},
+				{Select: model.Selector{Area: "prod"}, Font: "Helvetica"},
+				{Select: model.Selector{Lifecycle: model.Deprecated}, Fill: "gray"},
+				{Select: model.Selector{Type: "app", Area: "prod-eu"}, Shape: "box"},
+				{Select: model.Selector{}, FontColor: "black"},
+			},
+		}
+	})
+
+	fills := func(styles []model.Style) []string {
+		values := []string{}
+		for _, s := range styles {
+			values = append(values, s.Fill+s.BorderColor+s.Font+s.Shape+s.FontColor)
+		}
+		return values
+	}
+
+	Describe("ComponentStyles", func() {
+		It("cascades from the least to the most specific selector", func() {
+			Expect(fills(d.ComponentStyles("web"))).To(Equal([]string{"black", "Helvetica", "box", "blue", "red"}))
+		})
+		It("matches areas, lifecycles and empty selectors", func() {
+			Expect(fills(d.ComponentStyles("api"))).To(Equal([]string{"black", "Helvetica", "gray"}))
+		})
+	})
+
+	Describe("TeamStyles", func() {
+		It("only uses styles selecting teams or tags", func() {
+			Expect(fills(d.TeamStyles("shop"))).To(Equal([]string{"blue", "red"}))
+			Expect(fills(d.TeamStyles("platform"))).To(BeEmpty())
+		})
+	})
+})
@@ -1,18 +1,37 @@
 package model
 
+// Unassigned is the key of the placeholder team and area importers give to components, which is meant to be replaced
+// by hand once the import has been reviewed
+const Unassigned = "unassigned"
+
 type Team struct {
-	Name        string      `yaml:"name"`
-	TeamContact TeamContact `yaml:"team-contact"`
-	LeadContact TeamContact `yaml:"lead-contact"`
-	Display     Display     `yaml:"display"`
+	Name        string            `yaml:"name" json:"name"`
+	TeamContact TeamContact       `yaml:"team-contact,omitempty" json:"team-contact"`
+	LeadContact TeamContact       `yaml:"lead-contact,omitempty" json:"lead-contact"`
+	Display     Display           `yaml:"display,omitempty" json:"display"`
+	Tags        []string          `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Links       []Link            `yaml:"links,omitempty" json:"links,omitempty"`
+	Properties  map[string]string `yaml:"properties,omitempty" json:"properties,omitempty"`
 }
 
 type TeamContact struct {
-	Name  string `yaml:"name"`
-	Email string `yaml:"email"`
+	Name  string `yaml:"name" json:"name"`
+	Email string `yaml:"email,omitempty" json:"email"`
 }
 
 type Display struct {
-	BackgroundColor string `yaml:"background-color"`
-	ForegroundColor string `yaml:"foreground-color"`
+	BackgroundColor string `yaml:"background-color,omitempty" json:"background-color"`
+	ForegroundColor string `yaml:"foreground-color,omitempty" json:"foreground-color"`
+}
+
+// HasOwner returns true when the component is owned by a team declared in the diagram, the Unassigned placeholder
+// is never an owner
+func (d Diagram) HasOwner(key string) bool {
+	k := d.Components[key].TeamKey
+	if k == "" || k == Unassigned {
+		return false
+	}
+
+	_, exists := d.Teams[k]
+	return exists
 }
@@ -1,7 +1,7 @@
 package model
 
 type Type struct {
-	Name        string `yaml:"name"`
-	Description string `yaml:"description"`
-	Shape       string `yaml:"shape"`
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description"`
+	Shape       string `yaml:"shape,omitempty" json:"shape"`
 }
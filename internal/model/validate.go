@@ -0,0 +1,128 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidationError lists everything that is wrong with a diagram
+type ValidationError []string
+
+func (e ValidationError) Error() string {
+	return "invalid diagram: " + strings.Join(e, "; ")
+}
+
+// Validate checks that every key the diagram refers to exists, that areas are not their own ancestors, that links
+// have a url and that styles select and draw with known values
+func (d Diagram) Validate() error {
+	problems := ValidationError{}
+
+	areaKeys := make([]string, 0, len(d.Areas))
+	for k := range d.Areas {
+		areaKeys = append(areaKeys, k)
+	}
+	sort.Strings(areaKeys)
+
+	for _, k := range areaKeys {
+		a := d.Areas[k]
+		if a.ParentKey == "" {
+			continue
+		}
+		if _, exists := d.Areas[a.ParentKey]; !exists {
+			problems = append(problems, fmt.Sprintf("area '%s' has unknown parent '%s'", k, a.ParentKey))
+			continue
+		}
+
+		// the ancestors stop before the first area seen twice, which is the area itself when it is in a cycle
+		ancestors := d.AreaAncestors(k)
+		if d.Areas[ancestors[len(ancestors)-1]].ParentKey == k {
+			problems = append(problems, fmt.Sprintf("area '%s' is within itself", k))
+		}
+	}
+
+	for _, k := range d.ComponentKeys() {
+		c := d.Components[k]
+		if _, exists := d.Areas[c.AreaKey]; c.AreaKey != "" && !exists {
+			problems = append(problems, fmt.Sprintf("component '%s' is in unknown area '%s'", k, c.AreaKey))
+		}
+		if _, exists := d.Levels[c.LevelKey]; c.LevelKey != "" && !exists {
+			problems = append(problems, fmt.Sprintf("component '%s' is at unknown level '%s'", k, c.LevelKey))
+		}
+		if _, exists := d.Teams[c.TeamKey]; c.TeamKey != "" && !exists {
+			problems = append(problems, fmt.Sprintf("component '%s' is owned by unknown team '%s'", k, c.TeamKey))
+		}
+		if _, exists := d.Types[c.TypeKey]; c.TypeKey != "" && !exists {
+			problems = append(problems, fmt.Sprintf("component '%s' has unknown type '%s'", k, c.TypeKey))
+		}
+		for _, dk := range c.DependencyKeys {
+			if _, exists := d.Components[dk]; !exists {
+				problems = append(problems, fmt.Sprintf("component '%s' depends on unknown component '%s'", k, dk))
+			}
+		}
+	}
+
+	// links are only useful with somewhere to go
+	for _, k := range d.ComponentKeys() {
+		problems = append(problems, linkProblems("component", k, d.Components[k].Links)...)
+	}
+	teamKeys := make([]string, 0, len(d.Teams))
+	for k := range d.Teams {
+		teamKeys = append(teamKeys, k)
+	}
+	sort.Strings(teamKeys)
+	for _, k := range teamKeys {
+		problems = append(problems, linkProblems("team", k, d.Teams[k].Links)...)
+	}
+	for _, k := range areaKeys {
+		problems = append(problems, linkProblems("area", k, d.Areas[k].Links)...)
+	}
+
+	for i, s := range d.Styles {
+		problems = append(problems, d.styleProblems(i+1, s)...)
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+
+	return nil
+}
+
+func linkProblems(kind string, key string, links []Link) []string {
+	problems := []string{}
+	for i, l := range links {
+		if l.URL == "" {
+			problems = append(problems, fmt.Sprintf("%s '%s' has link %d without a url", kind, key, i+1))
+		}
+	}
+
+	return problems
+}
+
+func (d Diagram) styleProblems(n int, s Style) []string {
+	problems := []string{}
+	if _, exists := d.Areas[s.Select.Area]; s.Select.Area != "" && !exists {
+		problems = append(problems, fmt.Sprintf("style %d selects unknown area '%s'", n, s.Select.Area))
+	}
+	if _, exists := d.Levels[s.Select.Level]; s.Select.Level != "" && !exists {
+		problems = append(problems, fmt.Sprintf("style %d selects unknown level '%s'", n, s.Select.Level))
+	}
+	if _, exists := d.Teams[s.Select.Team]; s.Select.Team != "" && !exists {
+		problems = append(problems, fmt.Sprintf("style %d selects unknown team '%s'", n, s.Select.Team))
+	}
+	if _, exists := d.Types[s.Select.Type]; s.Select.Type != "" && !exists {
+		problems = append(problems, fmt.Sprintf("style %d selects unknown type '%s'", n, s.Select.Type))
+	}
+	if s.Select.Lifecycle != "" && !contains(LifecycleStatuses, s.Select.Lifecycle) {
+		problems = append(problems, fmt.Sprintf("style %d selects unknown lifecycle status '%s'", n, s.Select.Lifecycle))
+	}
+	if s.BorderStyle != "" && !contains(BorderStyles, s.BorderStyle) {
+		problems = append(problems, fmt.Sprintf("style %d has unknown border style '%s'", n, s.BorderStyle))
+	}
+	if s.Edge.Style != "" && !contains(BorderStyles, s.Edge.Style) {
+		problems = append(problems, fmt.Sprintf("style %d has unknown edge style '%s'", n, s.Edge.Style))
+	}
+
+	return problems
+}
@@ -0,0 +1,99 @@
+package model_test
+
+import (
+	"github.com/abramsimon/gomponere/internal/model"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Validate", func() {
+	var (
+		d   model.Diagram
+		err error
+	)
+
+	BeforeEach(func() {
+		d = model.Diagram{
+			Areas: map[string]model.Area{
+				"root":  {Name: "Root"},
+				"child": {Name: "Child", ParentKey: "root"},
+			},
+			Levels: map[string]model.Level{"service": {Name: "Service"}},
+			Teams:  map[string]model.Team{"shop": {Name: "Shop"}},
+			Types:  map[string]model.Type{"database": {Name: "Database"}},
+			Components: map[string]model.Component{
+				"web": {Name: "Web", AreaKey: "child", LevelKey: "service", TeamKey: "shop", DependencyKeys: []string{"db"}},
+				"db":  {Name: "Database", AreaKey: "root", TypeKey: "database"},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		err = d.Validate()
+	})
+
+	Context("with a valid diagram", func() {
+		It("succeeds", func() {
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("with unknown keys", func() {
+		BeforeEach(func() {
+			d.Areas["lost"] = model.Area{Name: "Lost", ParentKey: "missing"}
+			d.Components["api"] = model.Component{Name: "Api", AreaKey: "nowhere", LevelKey: "code", TeamKey: "nobody", TypeKey: "thing", DependencyKeys: []string{"gone"}}
+		})
+
+		It("reports each of them", func() {
+			Expect(err).To(Equal(model.ValidationError{
+				"area 'lost' has unknown parent 'missing'",
+				"component 'api' is in unknown area 'nowhere'",
+				"component 'api' is at unknown level 'code'",
+				"component 'api' is owned by unknown team 'nobody'",
+				"component 'api' has unknown type 'thing'",
+				"component 'api' depends on unknown component 'gone'",
+			}))
+		})
+	})
+
+	Context("with links without a url", func() {
+		BeforeEach(func() {
+			d.Components["web"] = model.Component{Name: "Web", Links: []model.Link{{Name: "runbook", URL: "https://wiki/web"}, {Name: "dashboard"}}}
+			d.Teams["shop"] = model.Team{Name: "Shop", Links: []model.Link{{Name: "on-call"}}}
+		})
+
+		It("reports them", func() {
+			Expect(err).To(MatchError("invalid diagram: component 'web' has link 2 without a url; team 'shop' has link 1 without a url"))
+		})
+	})
+
+	Context("with styles using unknown values", func() {
+		BeforeEach(func() {
+			d.Styles = []model.Style{
+				{Select: model.Selector{Tag: "pci", Team: "shop"}, BorderStyle: model.Dashed},
+				{Select: model.Selector{Team: "nobody", Lifecycle: "retired"}, BorderStyle: "wavy", Edge: model.EdgeStyle{Style: "zigzag"}},
+			}
+		})
+
+		It("reports them", func() {
+			Expect(err).To(Equal(model.ValidationError{
+				"style 2 selects unknown team 'nobody'",
+				"style 2 selects unknown lifecycle status 'retired'",
+				"style 2 has unknown border style 'wavy'",
+				"style 2 has unknown edge style 'zigzag'",
+			}))
+		})
+	})
+
+	Context("with areas in a cycle", func() {
+		BeforeEach(func() {
+			d.Areas["a"] = model.Area{Name: "A", ParentKey: "b"}
+			d.Areas["b"] = model.Area{Name: "B", ParentKey: "a"}
+			d.Areas["c"] = model.Area{Name: "C", ParentKey: "a"}
+		})
+
+		It("reports the areas within themselves", func() {
+			Expect(err).To(MatchError("invalid diagram: area 'a' is within itself; area 'b' is within itself"))
+		})
+	})
+})
@@ -0,0 +1,11 @@
+package output
+
+import (
+	"github.com/abramsimon/gomponere/internal/model"
+	"gopkg.in/yaml.v2"
+)
+
+// Marshal writes the diagram as a single yaml document that input.Unmarshal can read back
+func Marshal(d model.Diagram) ([]byte, error) {
+	return yaml.Marshal(d)
+}
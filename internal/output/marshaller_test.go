@@ -0,0 +1,57 @@
+package output_test
+
+import (
+	"github.com/abramsimon/gomponere/internal/input"
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/abramsimon/gomponere/internal/output"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Marshal", func() {
+	var (
+		err  error
+		d    model.Diagram
+		data []byte
+	)
+
+	BeforeEach(func() {
+		d = diagram()
+	})
+
+	JustBeforeEach(func() {
+		data, err = output.Marshal(d)
+	})
+
+	It("does not error", func() {
+		Expect(err).To(BeNil())
+	})
+	It("leaves out empty fields", func() {
+		Expect(string(data)).ToNot(ContainSubstring("description"))
+		Expect(string(data)).ToNot(ContainSubstring("parent"))
+		Expect(string(data)).ToNot(ContainSubstring("dates"))
+	})
+	It("can be unmarshalled", func() {
+		back, err := input.Unmarshal(data)
+		Expect(err).To(BeNil())
+		Expect(back).To(Equal(d))
+	})
+})
+
+func diagram() model.Diagram {
+	return model.Diagram{
+		Areas: map[string]model.Area{
+			"area": {Name: "Area"},
+		},
+		Teams: map[string]model.Team{
+			"team": {Name: "Team"},
+		},
+		Types: map[string]model.Type{
+			"type": {Name: "Type", Shape: "cylinder"},
+		},
+		Components: map[string]model.Component{
+			"web": {Name: "Web", AreaKey: "area", TeamKey: "team", TypeKey: "type", DependencyKeys: []string{"api"}},
+			"api": {Name: "Api", AreaKey: "area", Lifecycle: model.Lifecycle{Status: model.Planned}},
+		},
+	}
+}
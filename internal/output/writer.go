@@ -0,0 +1,60 @@
+package output
+
+import (
+	"path/filepath"
+
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+)
+
+type Writer interface {
+	WriteAll(root string, d model.Diagram) error
+}
+
+type WriterImpl struct {
+	fs afero.Fs
+}
+
+func NewWriter(fs afero.Fs) *WriterImpl {
+	return &WriterImpl{
+		fs,
+	}
+}
+
+// WriteAll writes the diagram into root, split into files the same way the example input is
+// every top level key is written to exactly one file since the reader joins all of the files into one document
+func (w WriterImpl) WriteAll(root string, d model.Diagram) error {
+	if err := w.fs.MkdirAll(root, 0755); err != nil {
+		return err
+	}
+
+	files := []struct {
+		name    string
+		empty   bool
+		content interface{}
+	}{
+		{"areas.yaml", len(d.Areas) == 0, model.Diagram{Areas: d.Areas}},
+		{"teams.yaml", len(d.Teams) == 0, model.Diagram{Teams: d.Teams}},
+		{"meta.yaml", len(d.Types) == 0 && len(d.Levels) == 0, model.Diagram{Types: d.Types, Levels: d.Levels}},
+		{"components.yaml", len(d.Components) == 0, model.Diagram{Components: d.Components}},
+		{"styles.yaml", len(d.Styles) == 0, model.Diagram{Styles: d.Styles}},
+	}
+
+	for _, f := range files {
+		if f.empty {
+			continue
+		}
+
+		b, err := yaml.Marshal(f.content)
+		if err != nil {
+			return err
+		}
+
+		if err := afero.WriteFile(w.fs, filepath.Join(root, f.name), b, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package output_test
+
+import (
+	"github.com/abramsimon/gomponere/internal/input"
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/abramsimon/gomponere/internal/output"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+var _ = Describe("Writer", func() {
+	var (
+		err    error
+		fs     afero.Fs
+		root   string
+		d      model.Diagram
+		writer *output.WriterImpl
+	)
+
+	BeforeEach(func() {
+		fs = afero.NewMemMapFs()
+		root = "/the/file/path"
+		d = diagram()
+		writer = output.NewWriter(fs)
+	})
+
+	JustBeforeEach(func() {
+		err = writer.WriteAll(root, d)
+	})
+
+	It("does not error", func() {
+		Expect(err).To(BeNil())
+	})
+	It("writes a file per section", func() {
+		files, err := input.NewReader(fs).FindFiles(root)
+		Expect(err).To(BeNil())
+		Expect(files).To(ConsistOf(
+			root+"/areas.yaml",
+			root+"/teams.yaml",
+			root+"/meta.yaml",
+			root+"/components.yaml",
+		))
+	})
+	It("can be loaded", func() {
+		back, err := input.Load(fs, root)
+		Expect(err).To(BeNil())
+		Expect(back).To(Equal(d))
+	})
+
+	Context("with empty sections", func() {
+		BeforeEach(func() {
+			d.Teams = nil
+			d.Types = nil
+		})
+
+		It("skips them", func() {
+			exists, _ := afero.Exists(fs, root+"/teams.yaml")
+			Expect(exists).To(BeFalse())
+			exists, _ = afero.Exists(fs, root+"/meta.yaml")
+			Expect(exists).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,109 @@
+package owners
+
+import (
+	"bufio"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// Locations are where a CODEOWNERS file is looked for in a repository, in the order github uses them
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Rule gives the owners of the files matching a pattern
+type Rule struct {
+	Pattern string
+	Owners  []string
+	match   *regexp.Regexp
+}
+
+// Parse reads the rules of a CODEOWNERS file
+func Parse(r io.Reader) ([]Rule, error) {
+	rules := []Rule{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		rules = append(rules, Rule{Pattern: fields[0], Owners: fields[1:], match: compile(fields[0])})
+	}
+
+	return rules, scanner.Err()
+}
+
+// Find reads the CODEOWNERS file of the repository, returning no rules when it does not have one
+func Find(fs afero.Fs, repo string) ([]Rule, error) {
+	for _, l := range Locations {
+		f, err := fs.Open(filepath.Join(repo, l))
+		if err != nil {
+			continue
+		}
+		defer f.Close()
+
+		return Parse(f)
+	}
+
+	return nil, nil
+}
+
+// Owners returns the owners of the path in the repository, which is the directory itself when empty
+// as in github, the last rule that matches wins
+func Owners(rules []Rule, path string) []string {
+	path = strings.Trim(path, "/")
+
+	owners := []string{}
+	for _, r := range rules {
+		if r.match.MatchString(path) {
+			owners = r.Owners
+		}
+	}
+
+	return owners
+}
+
+// compile converts a CODEOWNERS pattern into a regular expression matching the paths it applies to,
+// including everything within a matching directory
+func compile(pattern string) *regexp.Regexp {
+	// a pattern with a slash anywhere but at its end is relative to the root
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" {
+		return regexp.MustCompile(".*")
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(.*/)?")
+			i += 2
+		case c == '*' && i+1 < len(pattern) && pattern[i+1] == '*':
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	prefix := "^(|.*/)"
+	if anchored {
+		prefix = "^"
+	}
+
+	return regexp.MustCompile(prefix + b.String() + "(/.*)?$")
+}
@@ -0,0 +1,109 @@
+package owners_test
+
+import (
+	"os"
+	"strings"
+
+	"github.com/abramsimon/gomponere/internal/owners"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+var _ = Describe("Codeowners", func() {
+	var rules []owners.Rule
+
+	BeforeEach(func() {
+		var err error
+		rules, err = owners.Parse(strings.NewReader(codeowners))
+		if err != nil {
+			Fail(err.Error())
+		}
+	})
+
+	Describe("Parse", func() {
+		It("skips comments and blank lines", func() {
+			Expect(rules).To(HaveLen(6))
+			Expect(rules[0].Pattern).To(Equal("*"))
+			Expect(rules[0].Owners).To(Equal([]string{"@example/platform"}))
+		})
+	})
+
+	Describe("Owners", func() {
+		It("uses the default owners for the repository itself", func() {
+			Expect(owners.Owners(rules, "")).To(Equal([]string{"@example/platform"}))
+		})
+		It("uses the last rule that matches", func() {
+			Expect(owners.Owners(rules, "services/orders")).To(Equal([]string{"@example/orders", "jane@example.com"}))
+			Expect(owners.Owners(rules, "services/orders/main.go")).To(Equal([]string{"@example/orders", "jane@example.com"}))
+		})
+		It("matches unanchored directories at any depth", func() {
+			Expect(owners.Owners(rules, "services/web/docs")).To(Equal([]string{"@example/writers"}))
+		})
+		It("matches anchored patterns only from the root", func() {
+			Expect(owners.Owners(rules, "services/payments")).To(Equal([]string{"@example/payments"}))
+			Expect(owners.Owners(rules, "old/services/payments")).To(Equal([]string{"@example/platform"}))
+		})
+		It("matches double stars across directories", func() {
+			Expect(owners.Owners(rules, "deploy/prod/web.tf")).To(Equal([]string{"@example/infra"}))
+			Expect(owners.Owners(rules, "web.tf")).To(Equal([]string{"@example/infra"}))
+		})
+		It("allows rules without owners", func() {
+			Expect(owners.Owners(rules, "vendor/lib")).To(BeEmpty())
+		})
+	})
+
+	Describe("Find", func() {
+		var (
+			fs    afero.Fs
+			found []owners.Rule
+			err   error
+		)
+
+		BeforeEach(func() {
+			fs = afero.NewMemMapFs()
+			afero.WriteFile(fs, "/repo/docs/CODEOWNERS", []byte("* @docs"), os.ModePerm)
+		})
+
+		JustBeforeEach(func() {
+			found, err = owners.Find(fs, "/repo")
+		})
+
+		It("finds the file in docs", func() {
+			Expect(err).To(BeNil())
+			Expect(found).To(HaveLen(1))
+		})
+
+		Context("with a file in .github", func() {
+			BeforeEach(func() {
+				afero.WriteFile(fs, "/repo/.github/CODEOWNERS", []byte("* @github\n*.go @gophers"), os.ModePerm)
+			})
+
+			It("prefers it", func() {
+				Expect(found).To(HaveLen(2))
+			})
+		})
+
+		Context("without a file", func() {
+			BeforeEach(func() {
+				fs = afero.NewMemMapFs()
+			})
+
+			It("finds no rules", func() {
+				Expect(err).To(BeNil())
+				Expect(found).To(BeNil())
+			})
+		})
+	})
+})
+
+const codeowners string = `
+# default owners
+*                   @example/platform
+
+services/orders/    @example/orders jane@example.com
+/services/payments  @example/payments
+docs/               @example/writers  # any docs directory
+**/*.tf             @example/infra
+vendor/
+`
@@ -0,0 +1,227 @@
+package owners
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/abramsimon/gomponere/internal/importer"
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+)
+
+// the kinds of findings
+const (
+	// Suggested is a component without a team that CODEOWNERS gives one
+	Suggested = "suggested"
+
+	// Mismatch is a component whose team is not one of the teams CODEOWNERS gives it
+	Mismatch = "mismatch"
+
+	// Unmapped is a component with owners in CODEOWNERS that are not mapped to any team
+	Unmapped = "unmapped"
+
+	// NotFound is a component whose repository has no local clone or no CODEOWNERS file
+	NotFound = "not-found"
+)
+
+// Mapping maps CODEOWNERS handles, like "@org/team" or "someone@example.com", to team keys
+type Mapping map[string]string
+
+// LoadMapping reads a mapping from a yaml file of handles to team keys
+func LoadMapping(fs afero.Fs, path string) (Mapping, error) {
+	b, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := Mapping{}
+	if err := yaml.UnmarshalStrict(b, &m); err != nil {
+		return nil, fmt.Errorf("unable to read '%s': %s", path, err)
+	}
+
+	return m, nil
+}
+
+// Team returns the key of the team for the handle
+// handles that are not mapped still match a team when they name a github team with the same key, so "@org/orders"
+// is the "orders" team
+func (m Mapping) Team(d model.Diagram, handle string) (string, bool) {
+	if k, exists := m[handle]; exists {
+		return k, true
+	}
+
+	if strings.HasPrefix(handle, "@") && strings.Contains(handle, "/") {
+		k := importer.Key(handle[strings.LastIndex(handle, "/")+1:])
+		if _, exists := d.Teams[k]; exists {
+			return k, true
+		}
+	}
+
+	return "", false
+}
+
+// Finding is what CODEOWNERS says about the team of a single component
+type Finding struct {
+	Kind     string   `json:"kind"`
+	Key      string   `json:"key"`
+	Declared string   `json:"declared"`
+	Owners   []string `json:"owners"`
+	Teams    []string `json:"teams"`
+	Message  string   `json:"message"`
+}
+
+// Check compares the team of every component that has a git repository with the owners in its CODEOWNERS file
+//
+// Repositories are looked for in the repos directory, by their path ("repos/org/name") or just their name
+// ("repos/name"), unless git is itself a directory. When git points within a repository, like a github url to a
+// directory, the owners of that directory are used.
+func Check(fs afero.Fs, d model.Diagram, repos string, mapping Mapping) []Finding {
+	findings := []Finding{}
+	for _, k := range d.ComponentKeys() {
+		c := d.Components[k]
+		if c.Git == "" {
+			continue
+		}
+
+		declared := c.TeamKey
+		if !d.HasOwner(k) {
+			declared = ""
+		}
+
+		dir, path := clone(fs, repos, c.Git)
+		if dir == "" {
+			findings = append(findings, Finding{Kind: NotFound, Key: k, Declared: declared, Message: fmt.Sprintf("no local clone of '%s' found", c.Git)})
+			continue
+		}
+
+		rules, err := Find(fs, dir)
+		if err != nil {
+			findings = append(findings, Finding{Kind: NotFound, Key: k, Declared: declared, Message: fmt.Sprintf("unable to read CODEOWNERS in '%s': %s", dir, err)})
+			continue
+		}
+		if rules == nil {
+			findings = append(findings, Finding{Kind: NotFound, Key: k, Declared: declared, Message: fmt.Sprintf("no CODEOWNERS file in '%s'", dir)})
+			continue
+		}
+
+		owners := Owners(rules, path)
+		teams, unmapped := []string{}, []string{}
+		for _, o := range owners {
+			if t, ok := mapping.Team(d, o); !ok {
+				unmapped = append(unmapped, o)
+			} else if !contains(teams, t) {
+				teams = append(teams, t)
+			}
+		}
+
+		f := Finding{Key: k, Declared: declared, Owners: owners, Teams: teams}
+		switch {
+		case len(teams) == 0 && len(unmapped) > 0:
+			f.Kind = Unmapped
+			f.Message = fmt.Sprintf("owners %s are not mapped to a team", strings.Join(unmapped, ", "))
+		case len(teams) == 0:
+			continue
+		case declared == "":
+			f.Kind = Suggested
+			f.Message = fmt.Sprintf("CODEOWNERS suggests team '%s'", teams[0])
+		case !contains(teams, declared):
+			f.Kind = Mismatch
+			f.Message = fmt.Sprintf("team '%s' is not an owner in CODEOWNERS, which has '%s'", declared, strings.Join(teams, "', '"))
+		default:
+			continue
+		}
+		findings = append(findings, f)
+	}
+
+	return findings
+}
+
+// Apply returns a copy of the diagram with the suggested teams filled in
+func Apply(d model.Diagram, findings []Finding) model.Diagram {
+	components := make(map[string]model.Component, len(d.Components))
+	for k, c := range d.Components {
+		components[k] = c
+	}
+
+	for _, f := range findings {
+		if f.Kind != Suggested {
+			continue
+		}
+
+		c := components[f.Key]
+		c.TeamKey = f.Teams[0]
+		components[f.Key] = c
+	}
+
+	d.Components = components
+	return d
+}
+
+// WriteText writes one line per finding
+func WriteText(w io.Writer, findings []Finding) error {
+	for _, f := range findings {
+		if _, err := fmt.Fprintf(w, "%s: component '%s': %s\n", f.Kind, f.Key, f.Message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var (
+	// scpURL matches git urls like "git@github.com:org/name.git"
+	scpURL = regexp.MustCompile(`^[^/@]+@[^/:]+:(.*)$`)
+
+	// treeURL matches the part of a url pointing within a repository, like "/tree/main/services/web"
+	treeURL = regexp.MustCompile(`/(?:tree|blob|-/tree|src)/[^/]+(/.*)?$`)
+)
+
+// clone returns the directory of the local clone of a repository and the path within it that git points to
+func clone(fs afero.Fs, repos string, git string) (string, string) {
+	if isDir(fs, git) {
+		return git, ""
+	}
+
+	path := git
+	if m := scpURL.FindStringSubmatch(path); m != nil {
+		path = m[1]
+	} else if i := strings.Index(path, "://"); i >= 0 {
+		path = path[i+3:]
+		path = path[strings.Index(path+"/", "/"):]
+	}
+
+	within := ""
+	if m := treeURL.FindStringSubmatch(path); m != nil {
+		within = m[1]
+		path = path[:len(path)-len(m[0])]
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+
+	candidates := []string{filepath.Join(repos, filepath.FromSlash(path)), filepath.Join(repos, filepath.Base(path))}
+	for _, c := range candidates {
+		if isDir(fs, c) {
+			return c, within
+		}
+	}
+
+	return "", ""
+}
+
+func isDir(fs afero.Fs, path string) bool {
+	ok, err := afero.IsDir(fs, path)
+	return err == nil && ok
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,13 @@
+package owners_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestOwners(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Owners Suite")
+}
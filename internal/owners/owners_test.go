@@ -0,0 +1,139 @@
+package owners_test
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/abramsimon/gomponere/internal/importer"
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/abramsimon/gomponere/internal/owners"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"github.com/spf13/afero"
+)
+
+var _ = Describe("Owners", func() {
+	var (
+		fs       afero.Fs
+		d        model.Diagram
+		mapping  owners.Mapping
+		findings []owners.Finding
+	)
+
+	BeforeEach(func() {
+		fs = afero.NewMemMapFs()
+		afero.WriteFile(fs, "/repos/example/shop/.github/CODEOWNERS", []byte("* @example/orders\n/web/ @jane\n/legacy/ @someone\n"), os.ModePerm)
+		afero.WriteFile(fs, "/repos/billing/CODEOWNERS", []byte("* @example/billing-team\n"), os.ModePerm)
+		afero.WriteFile(fs, "/repos/empty/README.md", []byte("nothing"), os.ModePerm)
+
+		d = model.Diagram{
+			Components: map[string]model.Component{
+				"api":     {Name: "Api", Git: "git@github.com:example/shop.git"},
+				"web":     {Name: "Web", Git: "https://github.com/example/shop/tree/main/web", TeamKey: "orders"},
+				"legacy":  {Name: "Legacy", Git: "https://github.com/example/shop/tree/main/legacy/", TeamKey: "orders"},
+				"billing": {Name: "Billing", Git: "https://gitlab.example.com/finance/billing.git", TeamKey: "orders"},
+				"empty":   {Name: "Empty", Git: "/repos/empty"},
+				"gone":    {Name: "Gone", Git: "https://github.com/example/gone"},
+				"manual":  {Name: "Manual", TeamKey: importer.Unassigned},
+				"typo":    {Name: "Typo", Git: "https://github.com/example/shop", TeamKey: "ordres"},
+				"correct": {Name: "Correct", Git: "https://github.com/example/shop", TeamKey: "orders"},
+			},
+			Teams: map[string]model.Team{
+				"orders":  {Name: "Orders"},
+				"billing": {Name: "Billing"},
+			},
+		}
+		mapping = owners.Mapping{"@jane": "web-team", "@example/billing-team": "billing"}
+	})
+
+	JustBeforeEach(func() {
+		findings = owners.Check(fs, d, "/repos", mapping)
+	})
+
+	find := func(key string) owners.Finding {
+		for _, f := range findings {
+			if f.Key == key {
+				return f
+			}
+		}
+		return owners.Finding{}
+	}
+
+	It("suggests teams for components without one", func() {
+		Expect(find("api")).To(MatchFields(IgnoreExtras, Fields{
+			"Kind":   Equal(owners.Suggested),
+			"Owners": Equal([]string{"@example/orders"}),
+			"Teams":  Equal([]string{"orders"}),
+		}))
+	})
+	It("suggests teams for components whose team is not declared", func() {
+		Expect(find("typo")).To(MatchFields(IgnoreExtras, Fields{
+			"Kind":     Equal(owners.Suggested),
+			"Declared": BeEmpty(),
+		}))
+	})
+	It("reports teams that disagree with CODEOWNERS", func() {
+		Expect(find("web")).To(MatchFields(IgnoreExtras, Fields{
+			"Kind":     Equal(owners.Mismatch),
+			"Declared": Equal("orders"),
+			"Teams":    Equal([]string{"web-team"}),
+		}))
+		Expect(find("billing").Kind).To(Equal(owners.Mismatch))
+	})
+	It("reports owners that are not mapped", func() {
+		Expect(find("legacy")).To(MatchFields(IgnoreExtras, Fields{
+			"Kind":   Equal(owners.Unmapped),
+			"Owners": Equal([]string{"@someone"}),
+		}))
+	})
+	It("reports repositories that cannot be found", func() {
+		Expect(find("empty").Kind).To(Equal(owners.NotFound))
+		Expect(find("gone").Kind).To(Equal(owners.NotFound))
+	})
+	It("skips components that agree or have no repository", func() {
+		Expect(find("correct").Kind).To(BeEmpty())
+		Expect(find("manual").Kind).To(BeEmpty())
+		Expect(findings).To(HaveLen(7))
+	})
+
+	Describe("Apply", func() {
+		var applied model.Diagram
+
+		JustBeforeEach(func() {
+			applied = owners.Apply(d, findings)
+		})
+
+		It("fills in suggested teams", func() {
+			Expect(applied.Components["api"].TeamKey).To(Equal("orders"))
+		})
+		It("leaves declared teams alone", func() {
+			Expect(applied.Components["web"].TeamKey).To(Equal("orders"))
+		})
+		It("does not change the diagram", func() {
+			Expect(d.Components["api"].TeamKey).To(BeEmpty())
+		})
+	})
+
+	Describe("WriteText", func() {
+		It("writes a line per finding", func() {
+			var b bytes.Buffer
+			owners.WriteText(&b, findings[:1])
+			Expect(b.String()).To(Equal("suggested: component 'api': CODEOWNERS suggests team 'orders'\n"))
+		})
+	})
+
+	Describe("LoadMapping", func() {
+		It("reads handles to teams", func() {
+			afero.WriteFile(fs, "/owners.yaml", []byte("\"@example/ops\": platform\n"), os.ModePerm)
+			m, err := owners.LoadMapping(fs, "/owners.yaml")
+			Expect(err).To(BeNil())
+			Expect(m).To(Equal(owners.Mapping{"@example/ops": "platform"}))
+		})
+		It("errors on invalid yaml", func() {
+			afero.WriteFile(fs, "/owners.yaml", []byte("- nope"), os.ModePerm)
+			_, err := owners.LoadMapping(fs, "/owners.yaml")
+			Expect(err).ToNot(BeNil())
+		})
+	})
+})
@@ -0,0 +1,251 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/abramsimon/gomponere"
+	"github.com/abramsimon/gomponere/internal/lint"
+	"github.com/abramsimon/gomponere/internal/model"
+)
+
+// Prefix starts the name of every plugin executable, so "gomponere-wiki" is the plugin "wiki"
+const Prefix = "gomponere-"
+
+// the commands a plugin is run with, as its first argument
+const (
+	// Render asks for the diagram in the plugin's format, written to stdout
+	Render = "render"
+
+	// Lint asks for a json list of problems with the diagram, written to stdout
+	Lint = "lint"
+)
+
+// ContentType is the media type of what plugins render, as they do not say
+const ContentType = "application/octet-stream"
+
+// Plugin is an executable that is sent the diagram as json on stdin
+type Plugin struct {
+	Name string
+	Path string
+}
+
+// Find returns the plugins in the directories of path, a list like $PATH, sorted by name
+// as with the shell, the first executable with a name wins
+func Find(path string) []Plugin {
+	found := map[string]Plugin{}
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, e := range entries {
+			name := strings.TrimPrefix(e.Name(), Prefix)
+			if name == e.Name() || name == "" {
+				continue
+			}
+			name = trimExecutableExt(name)
+			if _, exists := found[name]; exists {
+				continue
+			}
+
+			p := filepath.Join(dir, e.Name())
+			if info, err := os.Stat(p); err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+				continue
+			}
+			found[name] = Plugin{Name: name, Path: p}
+		}
+	}
+
+	plugins := make([]Plugin, 0, len(found))
+	for _, p := range found {
+		plugins = append(plugins, p)
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name < plugins[j].Name
+	})
+
+	return plugins
+}
+
+// trimExecutableExt removes the extension windows runs a file by, one of those in PATHEXT like ".exe", from name
+// elsewhere extensions are part of the name, so "gomponere-c4.v2" is the plugin "c4.v2"
+func trimExecutableExt(name string) string {
+	ext := filepath.Ext(name)
+	if runtime.GOOS != "windows" || ext == "" {
+		return name
+	}
+
+	exts := os.Getenv("PATHEXT")
+	if exts == "" {
+		exts = ".com;.exe;.bat;.cmd"
+	}
+	for _, e := range filepath.SplitList(exts) {
+		if strings.EqualFold(e, ext) {
+			return strings.TrimSuffix(name, ext)
+		}
+	}
+
+	return name
+}
+
+// Lookup returns the plugin with the name from the directories of path
+func Lookup(path string, name string) (Plugin, error) {
+	for _, p := range Find(path) {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+
+	return Plugin{}, fmt.Errorf("no plugin '%s%s' found", Prefix, name)
+}
+
+// Register makes every plugin available as a format, unless a renderer already has its name
+func Register(plugins []Plugin) {
+	formats := map[string]bool{}
+	for _, f := range gomponere.Formats() {
+		formats[f] = true
+	}
+
+	for _, p := range plugins {
+		if !formats[p.Name] {
+			gomponere.Register(p.Name, p)
+		}
+	}
+}
+
+// Render runs the plugin with the render command and the options as name=value arguments, in order
+func (p Plugin) Render(d model.Diagram, opts gomponere.FormatOptions) ([]byte, string, error) {
+	names := make([]string, 0, len(opts))
+	for name := range opts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := []string{}
+	for _, name := range names {
+		args = append(args, name+"="+opts[name])
+	}
+
+	out, err := p.run(Render, d, args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return out, ContentType, nil
+}
+
+// problem is a problem as plugins write it, without a severity it is a warning
+type problem struct {
+	Kind     string `json:"kind"`
+	Key      string `json:"key"`
+	Message  string `json:"message"`
+	Severity string `json:"severity,omitempty"`
+}
+
+// Problem is a problem a plugin found, with the severity it was reported at
+type Problem struct {
+	lint.Problem
+	Severity lint.Severity
+}
+
+// Lint runs the plugin with the lint command and reads the problems it found
+func (p Plugin) Lint(d model.Diagram) ([]Problem, error) {
+	out, err := p.run(Lint, d)
+	if err != nil {
+		return nil, err
+	}
+
+	found := []problem{}
+	if err := json.Unmarshal(out, &found); err != nil {
+		return nil, fmt.Errorf("unable to read the problems from plugin '%s': %s", p.Name, err)
+	}
+
+	problems := make([]Problem, 0, len(found))
+	for _, f := range found {
+		severity := lint.Warning
+		if f.Severity != "" {
+			if severity, err = lint.ParseSeverity(f.Severity); err != nil {
+				return nil, fmt.Errorf("plugin '%s': %s", p.Name, err)
+			}
+		}
+
+		problems = append(problems, Problem{lint.Problem{Kind: f.Kind, Key: f.Key, Message: f.Message}, severity})
+	}
+
+	return problems, nil
+}
+
+// Rules runs the plugin's lint command on the diagram, returning a rule named after the plugin for each severity the
+// problems were reported at, so changing the severity of the plugin's rule changes all of them
+func (p Plugin) Rules(d model.Diagram) ([]lint.Rule, error) {
+	problems, err := p.Lint(d)
+	if err != nil {
+		return nil, err
+	}
+
+	bySeverity := map[lint.Severity][]lint.Problem{}
+	for _, f := range problems {
+		bySeverity[f.Severity] = append(bySeverity[f.Severity], f.Problem)
+	}
+
+	// warnings always have a rule, so the plugin's rule exists even when it found nothing
+	rules := []lint.Rule{}
+	for _, s := range []lint.Severity{lint.Error, lint.Warning, lint.Info} {
+		if len(bySeverity[s]) == 0 && s != lint.Warning {
+			continue
+		}
+
+		found := bySeverity[s]
+		rules = append(rules, lint.Rule{
+			Name:        p.Name,
+			Description: "problems found by " + p.Path,
+			Severity:    s,
+			Check: func(model.Diagram) []lint.Problem {
+				return found
+			},
+		})
+	}
+
+	return rules, nil
+}
+
+// run validates the diagram and sends it to the plugin as json, returning what the plugin writes to stdout
+func (p Plugin) run(command string, d model.Diagram, args ...string) ([]byte, error) {
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	in, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(p.Path, append([]string{command}, args...)...)
+	cmd.Stdin = bytes.NewReader(in)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("plugin '%s': %s", p.Name, msg)
+		}
+		return nil, fmt.Errorf("plugin '%s': %s", p.Name, err)
+	}
+
+	return out, nil
+}
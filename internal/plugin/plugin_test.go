@@ -0,0 +1,178 @@
+package plugin_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/abramsimon/gomponere"
+	"github.com/abramsimon/gomponere/internal/lint"
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/abramsimon/gomponere/internal/plugin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Plugin", func() {
+	var (
+		err   error
+		first string
+		other string
+		d     model.Diagram
+	)
+
+	write := func(dir string, name string, script string, mode os.FileMode) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), mode); err != nil {
+			Fail(err.Error())
+		}
+	}
+
+	BeforeEach(func() {
+		if _, err := exec.LookPath("sh"); err != nil {
+			Skip("sh is not installed")
+		}
+
+		if first, err = ioutil.TempDir("", "plugin"); err != nil {
+			Fail(err.Error())
+		}
+		if other, err = ioutil.TempDir("", "plugin"); err != nil {
+			Fail(err.Error())
+		}
+
+		// echo prints its arguments and then the diagram it was sent
+		write(first, "gomponere-echo", `echo "$@"; cat`, 0755)
+		write(first, "gomponere-problems", `cat > /dev/null; echo '[{"kind": "component", "key": "web", "message": "has no docs"}, {"kind": "component", "key": "web", "message": "has no owner", "severity": "error"}]'`, 0755)
+		write(first, "gomponere-loud", `cat > /dev/null; echo '[{"kind": "component", "key": "web", "message": "is loud", "severity": "fatal"}]'`, 0755)
+		write(first, "gomponere-broken", `cat > /dev/null; echo "it broke" >&2; exit 3`, 0755)
+		write(first, "gomponere-data", `cat > /dev/null`, 0644)
+		write(first, "other-tool", `cat`, 0755)
+		write(other, "gomponere-echo", `echo shadowed`, 0755)
+		write(other, "gomponere-wiki.py", `cat > /dev/null; echo wiki`, 0755)
+		write(other, "gomponere-c4.v2", `cat`, 0755)
+
+		d = model.Diagram{
+			Components: map[string]model.Component{
+				"web": {Name: "Web"},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(first)
+		os.RemoveAll(other)
+	})
+
+	path := func() string {
+		return first + string(os.PathListSeparator) + other
+	}
+
+	Describe("Find", func() {
+		It("finds executables named for gomponere, earlier directories first, keeping extensions", func() {
+			Expect(plugin.Find(path())).To(Equal([]plugin.Plugin{
+				{Name: "broken", Path: filepath.Join(first, "gomponere-broken")},
+				{Name: "c4.v2", Path: filepath.Join(other, "gomponere-c4.v2")},
+				{Name: "echo", Path: filepath.Join(first, "gomponere-echo")},
+				{Name: "loud", Path: filepath.Join(first, "gomponere-loud")},
+				{Name: "problems", Path: filepath.Join(first, "gomponere-problems")},
+				{Name: "wiki.py", Path: filepath.Join(other, "gomponere-wiki.py")},
+			}))
+		})
+		It("looks plugins up by name", func() {
+			_, err := plugin.Lookup(path(), "data")
+			Expect(err).To(MatchError("no plugin 'gomponere-data' found"))
+		})
+	})
+
+	Describe("Register", func() {
+		It("adds plugins as formats without replacing the built in ones", func() {
+			plugin.Register([]plugin.Plugin{{Name: "dot", Path: "gomponere-dot"}, {Name: "registered", Path: "gomponere-registered"}})
+			Expect(gomponere.Formats()).To(ContainElement("registered"))
+
+			r, err := gomponere.Lookup("dot")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r).NotTo(BeAssignableToTypeOf(plugin.Plugin{}))
+		})
+	})
+
+	Describe("Render", func() {
+		var (
+			p plugin.Plugin
+		)
+
+		BeforeEach(func() {
+			p, err = plugin.Lookup(path(), "echo")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("sends the diagram as json with the options as arguments", func() {
+			out, contentType, err := p.Render(d, gomponere.FormatOptions{"width": "10", "title": "Shop"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contentType).To(Equal(plugin.ContentType))
+			Expect(string(out)).To(HavePrefix("render title=Shop width=10\n{"))
+			Expect(string(out)).To(ContainSubstring(`"web":{"name":"Web"`))
+		})
+
+		Context("with an invalid diagram", func() {
+			BeforeEach(func() {
+				d.Components["web"] = model.Component{Name: "Web", DependencyKeys: []string{"missing"}}
+			})
+
+			It("does not run the plugin", func() {
+				_, _, err := p.Render(d, nil)
+				Expect(err).To(MatchError("invalid diagram: component 'web' depends on unknown component 'missing'"))
+			})
+		})
+
+		Context("when the plugin fails", func() {
+			BeforeEach(func() {
+				p, err = plugin.Lookup(path(), "broken")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("reports what it wrote to stderr", func() {
+				_, _, err := p.Render(d, nil)
+				Expect(err).To(MatchError("plugin 'broken': it broke"))
+			})
+		})
+	})
+
+	Describe("Rules", func() {
+		It("reports the problems the plugin found at their severity, or as warnings", func() {
+			p, err := plugin.Lookup(path(), "problems")
+			Expect(err).NotTo(HaveOccurred())
+
+			rules, err := p.Rules(d)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lint.Run(d, rules)).To(Equal([]lint.Diagnostic{
+				{Rule: "problems", Severity: lint.Error, Kind: "component", Key: "web", Message: "has no owner"},
+				{Rule: "problems", Severity: lint.Warning, Kind: "component", Key: "web", Message: "has no docs"},
+			}))
+		})
+		It("changes the severity of every problem with the severity of the rule", func() {
+			p, err := plugin.Lookup(path(), "problems")
+			Expect(err).NotTo(HaveOccurred())
+
+			rules, err := p.Rules(d)
+			Expect(err).NotTo(HaveOccurred())
+			rules, err = lint.WithSeverities(rules, map[string]lint.Severity{"problems": lint.Info})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lint.Run(d, rules)).To(HaveLen(2))
+			Expect(lint.HasErrors(lint.Run(d, rules))).To(BeFalse())
+		})
+		It("fails for unknown severities", func() {
+			p, err := plugin.Lookup(path(), "loud")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = p.Rules(d)
+			Expect(err).To(MatchError("plugin 'loud': unknown severity 'fatal', expected error, warning, info or off"))
+		})
+		It("fails when the plugin does not write problems", func() {
+			p, err := plugin.Lookup(path(), "wiki.py")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = p.Rules(d)
+			Expect(err).To(MatchError(HavePrefix("unable to read the problems from plugin 'wiki.py'")))
+		})
+	})
+})
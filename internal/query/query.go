@@ -0,0 +1,318 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/abramsimon/gomponere/internal/expr"
+	"github.com/abramsimon/gomponere/internal/model"
+)
+
+// what results can be grouped by
+const (
+	ByTeam  = "team"
+	ByArea  = "area"
+	ByType  = "type"
+	ByLevel = "level"
+)
+
+// Query is a pipeline of stages, separated by "|", that starts with every component in the diagram
+//
+// The stages are:
+//
+//	where <expression>          keeps the components the expression is true for
+//	dependencies [min[..max]]   replaces the components with those they depend on, directly or not
+//	dependents [min[..max]]     replaces the components with those that depend on them, directly or not
+//	group by <team|area|type|level>
+//
+// The depth of dependencies and dependents is bounded by min and max, 1..* by default, where "*" is unbounded and 0
+// keeps the components walked from. A single number is that depth only. Grouping can only be the last stage.
+type Query struct {
+	stages  []stage
+	groupBy string
+}
+
+// stage takes the keys of components to the depth they were found at and returns the next set
+type stage func(d model.Diagram, in map[string]int) map[string]int
+
+// Match is a component found by a query, with how far from the components walked from it was found
+type Match struct {
+	Key   string `json:"key"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Team  string `json:"team"`
+	Area  string `json:"area"`
+	Depth int    `json:"depth"`
+}
+
+// Group is the matches with the same team, area, type or level
+type Group struct {
+	Key        string   `json:"key"`
+	Name       string   `json:"name"`
+	Components []string `json:"components"`
+}
+
+// Result is what a query found
+type Result struct {
+	Components []Match `json:"components"`
+	GroupBy    string  `json:"group-by,omitempty"`
+	Groups     []Group `json:"groups,omitempty"`
+}
+
+// Parse compiles a query, an empty one finds every component
+func Parse(s string) (Query, error) {
+	q := Query{}
+	if strings.TrimSpace(s) == "" {
+		return q, nil
+	}
+
+	parts := split(s)
+	for i, part := range parts {
+		word, rest := part, ""
+		if j := strings.IndexFunc(part, isSpace); j >= 0 {
+			word, rest = part[:j], strings.TrimSpace(part[j:])
+		}
+
+		if q.groupBy != "" {
+			return Query{}, fmt.Errorf("stage %d: group by must be the last stage", i+1)
+		}
+
+		switch word {
+		case "":
+			return Query{}, fmt.Errorf("stage %d is empty", i+1)
+		case "where":
+			x, err := expr.Compile(rest, expr.ComponentFields)
+			if err != nil {
+				return Query{}, fmt.Errorf("stage %d: %s", i+1, err)
+			}
+			q.stages = append(q.stages, where(x))
+		case "dependencies", "dependents":
+			min, max, err := depths(rest)
+			if err != nil {
+				return Query{}, fmt.Errorf("stage %d: %s", i+1, err)
+			}
+			next := model.Diagram.Dependencies
+			if word == "dependents" {
+				next = model.Diagram.Dependents
+			}
+			q.stages = append(q.stages, walk(next, min, max))
+		case "group":
+			fields := strings.Fields(rest)
+			if len(fields) != 2 || fields[0] != "by" || !isGroup(fields[1]) {
+				return Query{}, fmt.Errorf("stage %d: expected group by team, area, type or level", i+1)
+			}
+			q.groupBy = fields[1]
+		default:
+			return Query{}, fmt.Errorf("stage %d: unknown stage '%s', expected where, dependencies, dependents or group by", i+1, word)
+		}
+	}
+
+	return q, nil
+}
+
+// Run runs the query over the diagram
+func (q Query) Run(d model.Diagram) Result {
+	set := map[string]int{}
+	for k := range d.Components {
+		set[k] = 0
+	}
+
+	for _, s := range q.stages {
+		set = s(d, set)
+	}
+
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	r := Result{Components: []Match{}, GroupBy: q.groupBy}
+	for _, k := range keys {
+		c := d.Components[k]
+		r.Components = append(r.Components, Match{Key: k, Name: c.Name, Type: c.TypeKey, Team: c.TeamKey, Area: c.AreaKey, Depth: set[k]})
+	}
+
+	if q.groupBy != "" {
+		r.Groups = group(d, r.Components, q.groupBy)
+	}
+
+	return r
+}
+
+// Subset returns the part of the diagram with only the components that were found
+func (r Result) Subset(d model.Diagram) model.Diagram {
+	found := map[string]bool{}
+	for _, m := range r.Components {
+		found[m.Key] = true
+	}
+
+	return d.Subset(func(key string, _ model.Component) bool {
+		return found[key]
+	})
+}
+
+func where(x expr.Expr) stage {
+	return func(d model.Diagram, in map[string]int) map[string]int {
+		out := map[string]int{}
+		for k, depth := range in {
+			if x.Eval(d, expr.ComponentEnv(d, k)) {
+				out[k] = depth
+			}
+		}
+
+		return out
+	}
+}
+
+// walk follows the edges from every component breadth first, keeping those found between min and max steps away
+// components walked from are 0 steps away, but can be found again further away from the others, and a max below zero
+// is unbounded
+func walk(next func(d model.Diagram, key string) []string, min int, max int) stage {
+	return func(d model.Diagram, in map[string]int) map[string]int {
+		frontier := make([]string, 0, len(in))
+		for k := range in {
+			frontier = append(frontier, k)
+		}
+		sort.Strings(frontier)
+
+		found := map[string]int{}
+		for depth := 1; len(frontier) > 0 && (max < 0 || depth <= max); depth++ {
+			following := []string{}
+			for _, k := range frontier {
+				for _, nk := range next(d, k) {
+					if _, done := found[nk]; !done {
+						found[nk] = depth
+						following = append(following, nk)
+					}
+				}
+			}
+			frontier = following
+		}
+
+		out := map[string]int{}
+		for k, depth := range found {
+			if depth >= min {
+				out[k] = depth
+			}
+		}
+		if min == 0 {
+			for k := range in {
+				out[k] = 0
+			}
+		}
+
+		return out
+	}
+}
+
+// depths reads the bounds of a walk, like "2", "1..3" or "1..*"
+func depths(s string) (int, int, error) {
+	if s == "" {
+		return 1, -1, nil
+	}
+
+	parts := strings.SplitN(s, "..", 2)
+	min, err := strconv.Atoi(parts[0])
+	if err != nil || min < 0 {
+		return 0, 0, fmt.Errorf("invalid depth '%s', expected a number, min..max or min..*", s)
+	}
+	if len(parts) == 1 {
+		return min, min, nil
+	}
+	if parts[1] == "*" {
+		return min, -1, nil
+	}
+
+	max, err := strconv.Atoi(parts[1])
+	if err != nil || max < min {
+		return 0, 0, fmt.Errorf("invalid depth '%s', expected a number, min..max or min..*", s)
+	}
+
+	return min, max, nil
+}
+
+func group(d model.Diagram, matches []Match, by string) []Group {
+	groups := map[string]*Group{}
+	for _, m := range matches {
+		key := groupKey(d, m, by)
+		if _, exists := groups[key]; !exists {
+			groups[key] = &Group{Key: key, Name: groupName(d, by, key), Components: []string{}}
+		}
+		groups[key].Components = append(groups[key].Components, m.Key)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := []Group{}
+	for _, k := range keys {
+		result = append(result, *groups[k])
+	}
+
+	return result
+}
+
+func groupKey(d model.Diagram, m Match, by string) string {
+	switch by {
+	case ByTeam:
+		return m.Team
+	case ByArea:
+		return m.Area
+	case ByType:
+		return m.Type
+	case ByLevel:
+		return d.Components[m.Key].LevelKey
+	}
+
+	return ""
+}
+
+func groupName(d model.Diagram, by string, key string) string {
+	switch by {
+	case ByTeam:
+		return d.Teams[key].Name
+	case ByArea:
+		return d.Areas[key].Name
+	case ByType:
+		return d.Types[key].Name
+	case ByLevel:
+		return d.Levels[key].Name
+	}
+
+	return ""
+}
+
+func isGroup(by string) bool {
+	return by == ByTeam || by == ByArea || by == ByType || by == ByLevel
+}
+
+// split splits the query into its stages on "|", leaving "||" and anything in a string alone
+func split(s string) []string {
+	parts := []string{}
+	start, quoted := 0, false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quoted && s[i] == '\\':
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && s[i] == '|' && i+1 < len(s) && s[i+1] == '|':
+			i++
+		case !quoted && s[i] == '|':
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+
+	return append(parts, strings.TrimSpace(s[start:]))
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n'
+}
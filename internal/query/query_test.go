@@ -0,0 +1,199 @@
+package query_test
+
+import (
+	"bytes"
+
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/abramsimon/gomponere/internal/query"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Query", func() {
+	var (
+		d      model.Diagram
+		q      string
+		result query.Result
+		err    error
+	)
+
+	keys := func(r query.Result) []string {
+		keys := []string{}
+		for _, m := range r.Components {
+			keys = append(keys, m.Key)
+		}
+		return keys
+	}
+
+	BeforeEach(func() {
+		d = model.Diagram{
+			Areas: map[string]model.Area{
+				"us-east-1": {Name: "US East 1"},
+				"shop":      {Name: "Shop", ParentKey: "us-east-1"},
+				"eu-west-1": {Name: "EU West 1"},
+			},
+			Teams: map[string]model.Team{
+				"thundercats": {Name: "Thundercats"},
+				"web-team":    {Name: "Web Team"},
+			},
+			Components: map[string]model.Component{
+				"web":        {Name: "Web", TypeKey: "web-app", AreaKey: "shop", TeamKey: "web-team", DependencyKeys: []string{"api"}},
+				"web-eu":     {Name: "Web EU", TypeKey: "web-app", AreaKey: "eu-west-1", TeamKey: "web-team", DependencyKeys: []string{"reports"}},
+				"api":        {Name: "Api", AreaKey: "us-east-1", TeamKey: "thundercats", DependencyKeys: []string{"orders-db", "cache"}},
+				"cache":      {Name: "Cache", TypeKey: "cache", AreaKey: "us-east-1", TeamKey: "thundercats", DependencyKeys: []string{"archive-db"}},
+				"orders-db":  {Name: "Orders", TypeKey: "database", AreaKey: "us-east-1", TeamKey: "thundercats"},
+				"archive-db": {Name: "Archive", TypeKey: "database", AreaKey: "us-east-1", TeamKey: "web-team"},
+				"reports":    {Name: "Reports", TypeKey: "database", AreaKey: "eu-west-1", TeamKey: "thundercats"},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		var parsed query.Query
+		parsed, err = query.Parse(q)
+		if err == nil {
+			result = parsed.Run(d)
+		}
+	})
+
+	Context("finding the databases a team owns that web apps in an area reach", func() {
+		BeforeEach(func() {
+			q = `where type == "web-app" && area within "us-east-1" | dependencies | where type == "database" && team == "thundercats"`
+		})
+
+		It("finds them", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Components).To(Equal([]query.Match{
+				{Key: "orders-db", Name: "Orders", Type: "database", Team: "thundercats", Area: "us-east-1", Depth: 2},
+			}))
+		})
+	})
+
+	Context("with an empty query", func() {
+		BeforeEach(func() {
+			q = " "
+		})
+
+		It("finds every component", func() {
+			Expect(result.Components).To(HaveLen(7))
+		})
+	})
+
+	Context("with depth bounds", func() {
+		BeforeEach(func() {
+			q = `where key == "web" | dependencies 0..2`
+		})
+
+		It("keeps the components within them", func() {
+			Expect(keys(result)).To(Equal([]string{"api", "cache", "orders-db", "web"}))
+			Expect(result.Components[0].Depth).To(Equal(1))
+		})
+	})
+
+	Context("with a single depth", func() {
+		BeforeEach(func() {
+			q = `where key == "web" | dependencies 3`
+		})
+
+		It("keeps the components at that depth", func() {
+			Expect(keys(result)).To(Equal([]string{"archive-db"}))
+		})
+	})
+
+	Context("walking dependents", func() {
+		BeforeEach(func() {
+			q = `where key == "archive-db" || key == "reports" | dependents 1..*`
+		})
+
+		It("finds everything depending on the components", func() {
+			Expect(keys(result)).To(Equal([]string{"api", "cache", "web", "web-eu"}))
+		})
+	})
+
+	Context("walking from components that depend on each other", func() {
+		BeforeEach(func() {
+			q = `where type == "web-app" || key == "api" | dependencies 1`
+		})
+
+		It("finds the components walked from too", func() {
+			Expect(keys(result)).To(Equal([]string{"api", "cache", "orders-db", "reports"}))
+		})
+	})
+
+	Context("grouping", func() {
+		BeforeEach(func() {
+			q = `where type == "database" | group by team`
+		})
+
+		It("groups the components", func() {
+			Expect(result.GroupBy).To(Equal("team"))
+			Expect(result.Groups).To(Equal([]query.Group{
+				{Key: "thundercats", Name: "Thundercats", Components: []string{"orders-db", "reports"}},
+				{Key: "web-team", Name: "Web Team", Components: []string{"archive-db"}},
+			}))
+		})
+
+		It("writes a table of the groups", func() {
+			buf := &bytes.Buffer{}
+			Expect(query.WriteTable(buf, result)).To(Succeed())
+			Expect(buf.String()).To(Equal("" +
+				"TEAM         NAME         COUNT  COMPONENTS\n" +
+				"thundercats  Thundercats  2      orders-db, reports\n" +
+				"web-team     Web Team     1      archive-db\n"))
+		})
+	})
+
+	Context("with a string containing a pipe", func() {
+		BeforeEach(func() {
+			d.Components["odd"] = model.Component{Name: "a | b"}
+			q = `where name == "a | b"`
+		})
+
+		It("does not split the stage", func() {
+			Expect(keys(result)).To(Equal([]string{"odd"}))
+		})
+	})
+
+	It("writes a table of the components", func() {
+		r, err := query.Parse(`where key == "web" | dependencies 1`)
+		Expect(err).NotTo(HaveOccurred())
+
+		buf := &bytes.Buffer{}
+		Expect(query.WriteTable(buf, r.Run(d))).To(Succeed())
+		Expect(buf.String()).To(Equal("" +
+			"KEY  NAME  TYPE  TEAM         AREA       DEPTH\n" +
+			"api  Api   -     thundercats  us-east-1  1\n"))
+	})
+
+	Describe("Subset", func() {
+		BeforeEach(func() {
+			q = `where key == "web" | dependencies 0..1`
+		})
+
+		It("keeps the components found and the dependencies between them", func() {
+			sub := result.Subset(d)
+			Expect(sub.ComponentKeys()).To(Equal([]string{"api", "web"}))
+			Expect(sub.Components["api"].DependencyKeys).To(BeEmpty())
+		})
+	})
+
+	Describe("invalid queries", func() {
+		cases := map[string]string{
+			`where`:                            "stage 1: unexpected 'end of expression' at column 1",
+			`where key == "a" |`:               "stage 2 is empty",
+			`select name`:                      "stage 1: unknown stage 'select', expected where, dependencies, dependents or group by",
+			`dependencies 3..1`:                "stage 1: invalid depth '3..1', expected a number, min..max or min..*",
+			`dependents many`:                  "stage 1: invalid depth 'many', expected a number, min..max or min..*",
+			`group by owner`:                   "stage 1: expected group by team, area, type or level",
+			`group by team | where key == "a"`: "stage 2: group by must be the last stage",
+		}
+
+		for q, message := range cases {
+			q, message := q, message
+			It("rejects "+q, func() {
+				_, err := query.Parse(q)
+				Expect(err).To(MatchError(message))
+			})
+		}
+	})
+})
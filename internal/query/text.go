@@ -0,0 +1,39 @@
+package query
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// none is shown for empty values in a table
+const none = "-"
+
+// WriteTable writes the components that were found as an aligned table, or the groups when the query grouped them
+func WriteTable(w io.Writer, r Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	if r.GroupBy != "" {
+		fmt.Fprintf(tw, "%s\tNAME\tCOUNT\tCOMPONENTS\n", strings.ToUpper(r.GroupBy))
+		for _, g := range r.Groups {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", orNone(g.Key), orNone(g.Name), len(g.Components), strings.Join(g.Components, ", "))
+		}
+		return tw.Flush()
+	}
+
+	fmt.Fprintln(tw, "KEY\tNAME\tTYPE\tTEAM\tAREA\tDEPTH")
+	for _, m := range r.Components {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\n", m.Key, orNone(m.Name), orNone(m.Type), orNone(m.Team), orNone(m.Area), m.Depth)
+	}
+
+	return tw.Flush()
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return none
+	}
+
+	return s
+}
@@ -0,0 +1,476 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abramsimon/gomponere"
+	"github.com/abramsimon/gomponere/internal/model"
+)
+
+type status struct {
+	LoadedAt   time.Time `json:"loaded-at"`
+	Error      string    `json:"error,omitempty"`
+	Areas      int       `json:"areas"`
+	Components int       `json:"components"`
+	Levels     int       `json:"levels"`
+	Teams      int       `json:"teams"`
+	Types      int       `json:"types"`
+}
+
+type component struct {
+	Key string `json:"key"`
+	model.Component
+}
+
+type team struct {
+	Key string `json:"key"`
+	model.Team
+}
+
+type area struct {
+	Key string `json:"key"`
+	model.Area
+}
+
+type level struct {
+	Key string `json:"key"`
+	model.Level
+}
+
+type componentType struct {
+	Key string `json:"key"`
+	model.Type
+}
+
+type searchResult struct {
+	Kind string `json:"kind"`
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+func (s *Server) getStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	st := status{
+		LoadedAt:   s.loadedAt,
+		Areas:      len(s.diagram.Areas),
+		Components: len(s.diagram.Components),
+		Levels:     len(s.diagram.Levels),
+		Teams:      len(s.diagram.Teams),
+		Types:      len(s.diagram.Types),
+	}
+	if s.loadErr != nil {
+		st.Error = s.loadErr.Error()
+	}
+	s.mu.RUnlock()
+
+	writeJSON(w, http.StatusOK, st)
+}
+
+func (s *Server) listComponents(w http.ResponseWriter, r *http.Request) {
+	d := s.Diagram()
+	q := r.URL.Query()
+
+	teamKey, areaKey, typeKey, levelKey, tag := q.Get("team"), q.Get("area"), q.Get("type"), q.Get("level"), q.Get("tag")
+
+	keys := []string{}
+	for _, k := range d.ComponentKeys() {
+		c := d.Components[k]
+		if teamKey != "" && c.TeamKey != teamKey {
+			continue
+		}
+		if areaKey != "" && !d.InArea(c.AreaKey, areaKey) {
+			continue
+		}
+		if typeKey != "" && c.TypeKey != typeKey {
+			continue
+		}
+		if levelKey != "" && c.LevelKey != levelKey {
+			continue
+		}
+		if tag != "" && !model.HasTag(c.Tags, tag) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+
+	writeJSON(w, http.StatusOK, components(d, keys))
+}
+
+func (s *Server) getComponent(w http.ResponseWriter, r *http.Request) {
+	d := s.Diagram()
+	k := r.PathValue("key")
+
+	c, exists := d.Components[k]
+	if !exists {
+		writeError(w, http.StatusNotFound, fmt.Errorf("component '%s' not found", k))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, component{k, c})
+}
+
+func (s *Server) listDependencies(w http.ResponseWriter, r *http.Request) {
+	s.listRelated(w, r, model.Diagram.Dependencies)
+}
+
+func (s *Server) listDependents(w http.ResponseWriter, r *http.Request) {
+	s.listRelated(w, r, model.Diagram.Dependents)
+}
+
+// listRelated writes the components related to the requested component
+// when transitive=true is passed the relation is followed until no new components are found
+func (s *Server) listRelated(w http.ResponseWriter, r *http.Request, related func(model.Diagram, string) []string) {
+	d := s.Diagram()
+	k := r.PathValue("key")
+
+	if _, exists := d.Components[k]; !exists {
+		writeError(w, http.StatusNotFound, fmt.Errorf("component '%s' not found", k))
+		return
+	}
+
+	keys := related(d, k)
+
+	if r.URL.Query().Get("transitive") == "true" {
+		seen := map[string]bool{k: true}
+		queue := append([]string{}, keys...)
+		keys = []string{}
+		for len(queue) > 0 {
+			next := queue[0]
+			queue = queue[1:]
+			if seen[next] {
+				continue
+			}
+			seen[next] = true
+			keys = append(keys, next)
+			queue = append(queue, related(d, next)...)
+		}
+		sort.Strings(keys)
+	}
+
+	writeJSON(w, http.StatusOK, components(d, keys))
+}
+
+func (s *Server) listTeams(w http.ResponseWriter, r *http.Request) {
+	d := s.Diagram()
+
+	teams := []team{}
+	for _, k := range sortedKeys(d.Teams) {
+		teams = append(teams, team{k, d.Teams[k]})
+	}
+
+	writeJSON(w, http.StatusOK, teams)
+}
+
+func (s *Server) getTeam(w http.ResponseWriter, r *http.Request) {
+	d := s.Diagram()
+	k := r.PathValue("key")
+
+	t, exists := d.Teams[k]
+	if !exists {
+		writeError(w, http.StatusNotFound, fmt.Errorf("team '%s' not found", k))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, team{k, t})
+}
+
+func (s *Server) listTeamComponents(w http.ResponseWriter, r *http.Request) {
+	d := s.Diagram()
+	k := r.PathValue("key")
+
+	if _, exists := d.Teams[k]; !exists {
+		writeError(w, http.StatusNotFound, fmt.Errorf("team '%s' not found", k))
+		return
+	}
+
+	keys := []string{}
+	for _, ck := range d.ComponentKeys() {
+		if d.Components[ck].TeamKey == k {
+			keys = append(keys, ck)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, components(d, keys))
+}
+
+func (s *Server) listAreas(w http.ResponseWriter, r *http.Request) {
+	d := s.Diagram()
+
+	areas := []area{}
+	for _, k := range sortedKeys(d.Areas) {
+		areas = append(areas, area{k, d.Areas[k]})
+	}
+
+	writeJSON(w, http.StatusOK, areas)
+}
+
+func (s *Server) getArea(w http.ResponseWriter, r *http.Request) {
+	d := s.Diagram()
+	k := r.PathValue("key")
+
+	a, exists := d.Areas[k]
+	if !exists {
+		writeError(w, http.StatusNotFound, fmt.Errorf("area '%s' not found", k))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, area{k, a})
+}
+
+func (s *Server) listAreaComponents(w http.ResponseWriter, r *http.Request) {
+	d := s.Diagram()
+	k := r.PathValue("key")
+
+	if _, exists := d.Areas[k]; !exists {
+		writeError(w, http.StatusNotFound, fmt.Errorf("area '%s' not found", k))
+		return
+	}
+
+	keys := []string{}
+	for _, ck := range d.ComponentKeys() {
+		if d.InArea(d.Components[ck].AreaKey, k) {
+			keys = append(keys, ck)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, components(d, keys))
+}
+
+func (s *Server) listLevels(w http.ResponseWriter, r *http.Request) {
+	d := s.Diagram()
+
+	levels := []level{}
+	for _, k := range sortedKeys(d.Levels) {
+		levels = append(levels, level{k, d.Levels[k]})
+	}
+
+	writeJSON(w, http.StatusOK, levels)
+}
+
+func (s *Server) getLevel(w http.ResponseWriter, r *http.Request) {
+	d := s.Diagram()
+	k := r.PathValue("key")
+
+	l, exists := d.Levels[k]
+	if !exists {
+		writeError(w, http.StatusNotFound, fmt.Errorf("level '%s' not found", k))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, level{k, l})
+}
+
+func (s *Server) listTypes(w http.ResponseWriter, r *http.Request) {
+	d := s.Diagram()
+
+	types := []componentType{}
+	for _, k := range sortedKeys(d.Types) {
+		types = append(types, componentType{k, d.Types[k]})
+	}
+
+	writeJSON(w, http.StatusOK, types)
+}
+
+func (s *Server) getType(w http.ResponseWriter, r *http.Request) {
+	d := s.Diagram()
+	k := r.PathValue("key")
+
+	t, exists := d.Types[k]
+	if !exists {
+		writeError(w, http.StatusNotFound, fmt.Errorf("type '%s' not found", k))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, componentType{k, t})
+}
+
+func (s *Server) renderAll(w http.ResponseWriter, r *http.Request) {
+	s.writeDiagram(w, r, s.Diagram())
+}
+
+func (s *Server) renderArea(w http.ResponseWriter, r *http.Request) {
+	d := s.Diagram()
+	k := r.PathValue("key")
+
+	if _, exists := d.Areas[k]; !exists {
+		writeError(w, http.StatusNotFound, fmt.Errorf("area '%s' not found", k))
+		return
+	}
+
+	s.writeDiagram(w, r, d.Subset(func(_ string, c model.Component) bool {
+		return d.InArea(c.AreaKey, k)
+	}))
+}
+
+func (s *Server) renderTeam(w http.ResponseWriter, r *http.Request) {
+	d := s.Diagram()
+	k := r.PathValue("key")
+
+	if _, exists := d.Teams[k]; !exists {
+		writeError(w, http.StatusNotFound, fmt.Errorf("team '%s' not found", k))
+		return
+	}
+
+	s.writeDiagram(w, r, d.Subset(func(_ string, c model.Component) bool {
+		return c.TeamKey == k
+	}))
+}
+
+// renderComponent renders the component along with its direct dependencies and dependents
+func (s *Server) renderComponent(w http.ResponseWriter, r *http.Request) {
+	d := s.Diagram()
+	k := r.PathValue("key")
+
+	if _, exists := d.Components[k]; !exists {
+		writeError(w, http.StatusNotFound, fmt.Errorf("component '%s' not found", k))
+		return
+	}
+
+	neighbors := map[string]bool{k: true}
+	for _, nk := range append(d.Dependencies(k), d.Dependents(k)...) {
+		neighbors[nk] = true
+	}
+
+	s.writeDiagram(w, r, d.Subset(func(ck string, _ model.Component) bool {
+		return neighbors[ck]
+	}))
+}
+
+// search finds every entity whose key, name or description contains the query, ignoring case
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	d := s.Diagram()
+	q := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("q")))
+	kind := r.URL.Query().Get("kind")
+
+	if q == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("query parameter 'q' must be specified"))
+		return
+	}
+
+	matches := func(fields ...string) bool {
+		for _, f := range fields {
+			if strings.Contains(strings.ToLower(f), q) {
+				return true
+			}
+		}
+		return false
+	}
+
+	results := []searchResult{}
+	add := func(k string, key string, name string, fields ...string) {
+		if (kind == "" || kind == k) && matches(append(fields, key, name)...) {
+			results = append(results, searchResult{k, key, name})
+		}
+	}
+
+	for _, k := range d.ComponentKeys() {
+		c := d.Components[k]
+		add("component", k, c.Name, c.Description, c.Git)
+	}
+	for _, k := range sortedKeys(d.Teams) {
+		t := d.Teams[k]
+		add("team", k, t.Name, t.TeamContact.Name, t.TeamContact.Email, t.LeadContact.Name, t.LeadContact.Email)
+	}
+	for _, k := range sortedKeys(d.Areas) {
+		add("area", k, d.Areas[k].Name)
+	}
+	for _, k := range sortedKeys(d.Levels) {
+		add("level", k, d.Levels[k].Name)
+	}
+	for _, k := range sortedKeys(d.Types) {
+		t := d.Types[k]
+		add("type", k, t.Name, t.Description)
+	}
+
+	writeJSON(w, http.StatusOK, results)
+}
+
+func components(d model.Diagram, keys []string) []component {
+	cs := make([]component, 0, len(keys))
+	for _, k := range keys {
+		cs = append(cs, component{k, d.Components[k]})
+	}
+
+	return cs
+}
+
+// writeDiagram renders the diagram in the format given by ?format=, dot by default
+// the other query parameters are passed to the renderer as its options, overriding the server's defaults
+func (s *Server) writeDiagram(w http.ResponseWriter, r *http.Request, d model.Diagram) {
+	q := r.URL.Query()
+	format := q.Get("format")
+	if format == "" {
+		format = "dot"
+	}
+
+	renderer, err := gomponere.Lookup(format)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	opts := gomponere.FormatOptions{}
+	for name, value := range s.formatOptions {
+		opts[name] = value
+	}
+	for name := range q {
+		if name != "format" {
+			opts[name] = q.Get(name)
+		}
+	}
+
+	b, contentType, err := renderer.Render(d, opts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", withCharset(contentType))
+	w.WriteHeader(http.StatusOK)
+	w.Write(b)
+}
+
+// withCharset adds the utf-8 charset to text content types, like dot, json, yaml and svg, and leaves binary ones and
+// those that already have parameters alone
+func withCharset(contentType string) string {
+	if strings.Contains(contentType, ";") {
+		return contentType
+	}
+
+	switch {
+	case strings.HasPrefix(contentType, "text/"),
+		contentType == "application/json", contentType == "application/yaml",
+		strings.HasSuffix(contentType, "+json"), strings.HasSuffix(contentType, "+yaml"), strings.HasSuffix(contentType, "+xml"):
+		return contentType + "; charset=utf-8"
+	}
+
+	return contentType
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
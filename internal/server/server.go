@@ -0,0 +1,161 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/abramsimon/gomponere"
+	"github.com/abramsimon/gomponere/internal/input"
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/spf13/afero"
+)
+
+// Server serves a read-only view of the diagram loaded from the input files
+type Server struct {
+	fs   afero.Fs
+	root string
+
+	mu       sync.RWMutex
+	diagram  model.Diagram
+	checksum []byte
+	loadErr  error
+	loadedAt time.Time
+
+	formatOptions gomponere.FormatOptions
+}
+
+func NewServer(fs afero.Fs, root string) (*Server, error) {
+	s := &Server{
+		fs:   fs,
+		root: root,
+	}
+
+	// the first load must succeed, there is nothing to serve otherwise
+	if _, err := s.Reload(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// SetFormatOptions sets the options diagrams are rendered with unless a request overrides them, like the theme
+// it should be called before the server starts handling requests
+func (s *Server) SetFormatOptions(opts gomponere.FormatOptions) {
+	s.formatOptions = opts
+}
+
+// Diagram returns the most recently loaded diagram
+func (s *Server) Diagram() model.Diagram {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.diagram
+}
+
+// Reload reads the input files and replaces the diagram when they have changed
+// if the new input is invalid the previous diagram is kept and the error is returned
+func (s *Server) Reload() (bool, error) {
+	data, err := input.NewReader(s.fs).ReadAll(s.root)
+	if err != nil {
+		s.setError(err)
+		return false, err
+	}
+
+	sum := sha256.Sum256(data)
+
+	s.mu.RLock()
+	unchanged := s.checksum != nil && bytes.Equal(s.checksum, sum[:])
+	s.mu.RUnlock()
+	if unchanged {
+		// the files may have been put back the way they were after an error
+		s.setError(nil)
+		return false, nil
+	}
+
+	d, err := input.Unmarshal(data)
+	if err != nil {
+		s.setError(err)
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.diagram = d
+	s.checksum = sum[:]
+	s.loadErr = nil
+	s.loadedAt = time.Now()
+
+	return true, nil
+}
+
+// Watch polls the input files every interval and reloads the diagram when they change
+// it blocks until the context is cancelled, errors are passed to onError if it is not nil, once until they change
+func (s *Server) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	last := ""
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			_, err := s.Reload()
+			if err == nil {
+				last = ""
+				continue
+			}
+			if err.Error() != last && onError != nil {
+				onError(err)
+			}
+			last = err.Error()
+		}
+	}
+}
+
+// Handler returns the http handler for all of the api routes
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/status", s.getStatus)
+
+	mux.HandleFunc("GET /api/components", s.listComponents)
+	mux.HandleFunc("GET /api/components/{key}", s.getComponent)
+	mux.HandleFunc("GET /api/components/{key}/dependencies", s.listDependencies)
+	mux.HandleFunc("GET /api/components/{key}/dependents", s.listDependents)
+
+	mux.HandleFunc("GET /api/teams", s.listTeams)
+	mux.HandleFunc("GET /api/teams/{key}", s.getTeam)
+	mux.HandleFunc("GET /api/teams/{key}/components", s.listTeamComponents)
+
+	mux.HandleFunc("GET /api/areas", s.listAreas)
+	mux.HandleFunc("GET /api/areas/{key}", s.getArea)
+	mux.HandleFunc("GET /api/areas/{key}/components", s.listAreaComponents)
+
+	mux.HandleFunc("GET /api/levels", s.listLevels)
+	mux.HandleFunc("GET /api/levels/{key}", s.getLevel)
+
+	mux.HandleFunc("GET /api/types", s.listTypes)
+	mux.HandleFunc("GET /api/types/{key}", s.getType)
+
+	mux.HandleFunc("GET /api/diagrams", s.renderAll)
+	mux.HandleFunc("GET /api/diagrams/areas/{key}", s.renderArea)
+	mux.HandleFunc("GET /api/diagrams/teams/{key}", s.renderTeam)
+	mux.HandleFunc("GET /api/diagrams/components/{key}", s.renderComponent)
+
+	mux.HandleFunc("GET /api/search", s.search)
+
+	return mux
+}
+
+func (s *Server) setError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.loadErr = err
+}
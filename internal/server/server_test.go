@@ -0,0 +1,312 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/abramsimon/gomponere"
+	"github.com/abramsimon/gomponere/internal/server"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+func init() {
+	gomponere.Register("png", gomponere.RendererFunc(func(d gomponere.Diagram, opts gomponere.FormatOptions) ([]byte, string, error) {
+		return []byte{0x89, 'P', 'N', 'G'}, "image/png", nil
+	}))
+	gomponere.Register("svg", gomponere.RendererFunc(func(d gomponere.Diagram, opts gomponere.FormatOptions) ([]byte, string, error) {
+		return []byte("<svg/>"), "image/svg+xml", nil
+	}))
+}
+
+var _ = Describe("Server", func() {
+	var (
+		err  error
+		fs   afero.Fs
+		root string
+		s    *server.Server
+	)
+
+	BeforeEach(func() {
+		fs = afero.NewMemMapFs()
+		root = "/the/file/path"
+	})
+
+	write := func(content string) {
+		if err := afero.WriteFile(fs, filepath.Join(root, "diagram.yaml"), []byte(content), os.ModePerm); err != nil {
+			Fail(err.Error())
+		}
+	}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	decode := func(rec *httptest.ResponseRecorder) interface{} {
+		var v interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+			Fail(err.Error())
+		}
+		return v
+	}
+
+	keys := func(rec *httptest.ResponseRecorder) []string {
+		keys := []string{}
+		for _, item := range decode(rec).([]interface{}) {
+			keys = append(keys, item.(map[string]interface{})["key"].(string))
+		}
+		return keys
+	}
+
+	Describe("NewServer", func() {
+		JustBeforeEach(func() {
+			s, err = server.NewServer(fs, root)
+		})
+
+		Context("with an empty filesystem", func() {
+			It("does error", func() {
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		Context("with invalid input", func() {
+			BeforeEach(func() {
+				write("not: [valid")
+			})
+
+			It("does error", func() {
+				Expect(err).ToNot(BeNil())
+			})
+		})
+
+		Context("with valid input", func() {
+			BeforeEach(func() {
+				write(fixture)
+			})
+
+			It("does not error", func() {
+				Expect(err).To(BeNil())
+			})
+			It("loads the diagram", func() {
+				Expect(s.Diagram().Components).To(HaveLen(4))
+			})
+		})
+	})
+
+	Describe("Reload", func() {
+		var (
+			changed bool
+		)
+
+		BeforeEach(func() {
+			write(fixture)
+			if s, err = server.NewServer(fs, root); err != nil {
+				Fail(err.Error())
+			}
+		})
+
+		JustBeforeEach(func() {
+			changed, err = s.Reload()
+		})
+
+		Context("without changes", func() {
+			It("does not reload", func() {
+				Expect(err).To(BeNil())
+				Expect(changed).To(BeFalse())
+			})
+		})
+
+		Context("with changes", func() {
+			BeforeEach(func() {
+				write(fixture + `
+  extra:
+    name: Extra
+`)
+			})
+
+			It("reloads the diagram", func() {
+				Expect(err).To(BeNil())
+				Expect(changed).To(BeTrue())
+				Expect(s.Diagram().Components).To(HaveLen(5))
+			})
+		})
+
+		Context("with invalid changes", func() {
+			BeforeEach(func() {
+				write("not: [valid")
+			})
+
+			It("keeps the previous diagram", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(changed).To(BeFalse())
+				Expect(s.Diagram().Components).To(HaveLen(4))
+			})
+			It("reports the error in the status", func() {
+				Expect(decode(get("/api/status"))).To(HaveKey("error"))
+			})
+
+			Context("that are reverted", func() {
+				JustBeforeEach(func() {
+					write(fixture)
+					changed, err = s.Reload()
+				})
+
+				It("clears the error", func() {
+					Expect(err).To(BeNil())
+					Expect(changed).To(BeFalse())
+					Expect(decode(get("/api/status"))).ToNot(HaveKey("error"))
+				})
+			})
+		})
+	})
+
+	Describe("Handler", func() {
+		BeforeEach(func() {
+			write(fixture)
+			if s, err = server.NewServer(fs, root); err != nil {
+				Fail(err.Error())
+			}
+		})
+
+		It("lists components", func() {
+			rec := get("/api/components")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(keys(rec)).To(Equal([]string{"api", "db", "ops", "web"}))
+		})
+		It("filters components", func() {
+			Expect(keys(get("/api/components?team=web-team"))).To(Equal([]string{"api", "web"}))
+			Expect(keys(get("/api/components?area=company"))).To(Equal([]string{"api", "db", "web"}))
+			Expect(keys(get("/api/components?type=database"))).To(Equal([]string{"db"}))
+			Expect(keys(get("/api/components?tag=pci"))).To(Equal([]string{"db"}))
+		})
+		It("gets a component", func() {
+			rec := get("/api/components/web")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(decode(rec)).To(HaveKeyWithValue("name", "Web"))
+			Expect(decode(rec)).To(HaveKeyWithValue("team", "web-team"))
+		})
+		It("does not find unknown components", func() {
+			Expect(get("/api/components/nope").Code).To(Equal(http.StatusNotFound))
+			Expect(get("/api/components/nope/dependents").Code).To(Equal(http.StatusNotFound))
+		})
+		It("lists dependencies", func() {
+			Expect(keys(get("/api/components/web/dependencies"))).To(Equal([]string{"api"}))
+			Expect(keys(get("/api/components/web/dependencies?transitive=true"))).To(Equal([]string{"api", "db"}))
+		})
+		It("lists dependents", func() {
+			Expect(keys(get("/api/components/db/dependents"))).To(Equal([]string{"api", "ops"}))
+			Expect(keys(get("/api/components/db/dependents?transitive=true"))).To(Equal([]string{"api", "ops", "web"}))
+		})
+		It("lists teams and their components", func() {
+			Expect(keys(get("/api/teams"))).To(Equal([]string{"data-team", "web-team"}))
+			Expect(keys(get("/api/teams/data-team/components"))).To(Equal([]string{"db", "ops"}))
+			Expect(get("/api/teams/nope").Code).To(Equal(http.StatusNotFound))
+		})
+		It("lists areas and their components", func() {
+			Expect(keys(get("/api/areas"))).To(Equal([]string{"company", "other", "system"}))
+			Expect(keys(get("/api/areas/system/components"))).To(Equal([]string{"api", "web"}))
+		})
+		It("lists levels and types", func() {
+			Expect(keys(get("/api/levels"))).To(Equal([]string{"app"}))
+			Expect(keys(get("/api/types"))).To(Equal([]string{"database"}))
+			Expect(decode(get("/api/types/database"))).To(HaveKeyWithValue("shape", "cylinder"))
+		})
+		It("renders the whole diagram", func() {
+			rec := get("/api/diagrams")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Header().Get("Content-Type")).To(ContainSubstring("text/vnd.graphviz"))
+			Expect(rec.Body.String()).To(ContainSubstring("Ops"))
+		})
+		It("renders views", func() {
+			Expect(get("/api/diagrams/areas/system").Body.String()).ToNot(ContainSubstring(`label="Database"`))
+			Expect(get("/api/diagrams/teams/data-team").Body.String()).ToNot(ContainSubstring(`label="Web"`))
+			Expect(get("/api/diagrams/components/api").Body.String()).ToNot(ContainSubstring(`label="Ops"`))
+			Expect(get("/api/diagrams/areas/nope").Code).To(Equal(http.StatusNotFound))
+		})
+		It("renders other formats", func() {
+			rec := get("/api/diagrams/teams/data-team?format=json")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Header().Get("Content-Type")).To(ContainSubstring("application/json"))
+			Expect(decode(rec)).To(HaveKey("components"))
+			Expect(get("/api/diagrams?format=heatmap&metric=depth").Code).To(Equal(http.StatusOK))
+			Expect(get("/api/diagrams?format=heatmap&metric=size").Code).To(Equal(http.StatusInternalServerError))
+			Expect(get("/api/diagrams?format=nope").Code).To(Equal(http.StatusBadRequest))
+		})
+		It("only gives text formats a charset", func() {
+			Expect(get("/api/diagrams").Header().Get("Content-Type")).To(Equal("text/vnd.graphviz; charset=utf-8"))
+			Expect(get("/api/diagrams?format=yaml").Header().Get("Content-Type")).To(Equal("application/yaml; charset=utf-8"))
+			Expect(get("/api/diagrams?format=svg").Header().Get("Content-Type")).To(Equal("image/svg+xml; charset=utf-8"))
+			Expect(get("/api/diagrams?format=png").Header().Get("Content-Type")).To(Equal("image/png"))
+		})
+		It("renders with the default options unless the request overrides them", func() {
+			s.SetFormatOptions(gomponere.FormatOptions{gomponere.ThemeOption: gomponere.DarkTheme})
+			Expect(get("/api/diagrams").Body.String()).To(ContainSubstring(`bgcolor="#1e1e1e"`))
+			Expect(get("/api/diagrams?theme=print").Body.String()).To(ContainSubstring(`fontname="Times-Roman"`))
+		})
+		It("searches", func() {
+			rec := get("/api/search?q=DATA")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(keys(rec)).To(Equal([]string{"db", "data-team", "database"}))
+			Expect(keys(get("/api/search?q=data&kind=team"))).To(Equal([]string{"data-team"}))
+			Expect(get("/api/search").Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
+
+const fixture string = `
+areas:
+  company:
+    name: Company
+  system:
+    name: System
+    parent: company
+  other:
+    name: Other
+levels:
+  app:
+    name: App
+types:
+  database:
+    name: Database Type
+    shape: cylinder
+teams:
+  web-team:
+    name: Web Team
+  data-team:
+    name: Data Team
+components:
+  web:
+    name: Web
+    team: web-team
+    area: system
+    level: app
+    dependencies:
+      - api
+  api:
+    name: Api
+    team: web-team
+    area: system
+    level: app
+    dependencies:
+      - db
+  db:
+    name: Database
+    team: data-team
+    area: company
+    level: app
+    type: database
+    tags: [pci]
+  ops:
+    name: Ops
+    team: data-team
+    area: other
+    level: app
+    dependencies:
+      - db
+`
@@ -0,0 +1,95 @@
+package teammap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/abramsimon/gomponere/internal/diagram"
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/emicklei/dot"
+)
+
+// maxPenWidth is the width of the edges with the most dependencies, others are scaled down from it
+const maxPenWidth = 8.0
+
+// MakeDot renders a node per team, with an edge to each team it depends on
+// edges are labeled with the number of component dependencies behind them and are thicker the more there are,
+// and their tooltip, shown on hover in svg output, lists those dependencies
+func MakeDot(d model.Diagram) (string, error) {
+	return MakeDotWithOptions(d, diagram.Options{})
+}
+
+// MakeDotWithOptions renders the teams like MakeDot with the theme of the options
+func MakeDotWithOptions(d model.Diagram, opts diagram.Options) (string, error) {
+	g := dot.NewGraph(dot.Directed)
+	g.Attr("rankdir", "LR")
+	diagram.MakeTheme(g, opts.Theme)
+
+	// every team owning components gets a node, including the components without a team when there are any
+	counts := map[string]int{}
+	for _, c := range d.Components {
+		counts[c.TeamKey]++
+	}
+
+	nodes := map[string]dot.Node{}
+	for _, k := range sortedKeys(counts) {
+		t := d.Teams[k]
+
+		components := "components"
+		if counts[k] == 1 {
+			components = "component"
+		}
+
+		n := g.Node(k).
+			Attr("label", fmt.Sprintf("%s\n%d %s", TeamName(d, k), counts[k], components)).
+			Attr("shape", "box").
+			Attr("style", "filled,rounded")
+		diagram.MakeNodeColors(n, t, opts.Theme)
+		diagram.MakeTeamLink(n, t)
+
+		for _, s := range d.TeamStyles(k) {
+			diagram.MakeStyle(n, s)
+		}
+
+		nodes[k] = n
+	}
+
+	interactions := Interactions(d)
+
+	most := 1
+	for _, i := range interactions {
+		if len(i.Dependencies) > most {
+			most = len(i.Dependencies)
+		}
+	}
+
+	for _, i := range interactions {
+		lines := []string{}
+		for _, dep := range i.Dependencies {
+			lines = append(lines, dep.From+" -> "+dep.To)
+		}
+
+		n := len(i.Dependencies)
+		e := nodes[i.From].Edge(nodes[i.To]).
+			Attr("label", fmt.Sprint(n)).
+			Attr("weight", fmt.Sprint(n)).
+			Attr("penwidth", fmt.Sprintf("%.1f", 1+(maxPenWidth-1)*float64(n-1)/float64(max(most-1, 1)))).
+			Attr("tooltip", strings.Join(lines, "\n"))
+		if opts.Theme.EdgeColor != "" {
+			e.Attr("color", opts.Theme.EdgeColor).Attr("fontcolor", opts.Theme.Font.Color)
+		}
+	}
+
+	return g.String(), nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,69 @@
+package teammap
+
+import (
+	"sort"
+
+	"github.com/abramsimon/gomponere/internal/model"
+)
+
+// NoTeam is the key components without a team are grouped under
+const NoTeam = ""
+
+// Dependency is a dependency of one component on another
+type Dependency struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Interaction is a team depending on another team through the dependencies between their components
+type Interaction struct {
+	From         string       `json:"from"`
+	To           string       `json:"to"`
+	Dependencies []Dependency `json:"dependencies"`
+}
+
+// Interactions returns every pair of teams where components of one depend on components of the other, sorted by team
+// dependencies between components of the same team are left out
+func Interactions(d model.Diagram) []Interaction {
+	byTeams := map[Dependency]*Interaction{}
+	for _, k := range d.ComponentKeys() {
+		from := d.Components[k].TeamKey
+		for _, dk := range d.Dependencies(k) {
+			to := d.Components[dk].TeamKey
+			if from == to {
+				continue
+			}
+
+			pair := Dependency{from, to}
+			if byTeams[pair] == nil {
+				byTeams[pair] = &Interaction{From: from, To: to}
+			}
+			byTeams[pair].Dependencies = append(byTeams[pair].Dependencies, Dependency{k, dk})
+		}
+	}
+
+	interactions := []Interaction{}
+	for _, i := range byTeams {
+		interactions = append(interactions, *i)
+	}
+	sort.Slice(interactions, func(a, b int) bool {
+		if interactions[a].From != interactions[b].From {
+			return interactions[a].From < interactions[b].From
+		}
+		return interactions[a].To < interactions[b].To
+	})
+
+	return interactions
+}
+
+// TeamName returns the name of the team, or a placeholder for components without a team
+func TeamName(d model.Diagram, key string) string {
+	if key == NoTeam {
+		return "No team"
+	}
+	if t, exists := d.Teams[key]; exists {
+		return t.Name
+	}
+
+	return key
+}
@@ -0,0 +1,13 @@
+package teammap_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTeammap(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Teammap Suite")
+}
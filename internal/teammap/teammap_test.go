@@ -0,0 +1,98 @@
+package teammap_test
+
+import (
+	"bytes"
+
+	"github.com/abramsimon/gomponere/internal/model"
+	"github.com/abramsimon/gomponere/internal/teammap"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Teammap", func() {
+	var d model.Diagram
+
+	BeforeEach(func() {
+		d = model.Diagram{
+			Teams: map[string]model.Team{
+				"web":      {Name: "Web", Display: model.Display{BackgroundColor: "coral", ForegroundColor: "white"}},
+				"orders":   {Name: "Orders", TeamContact: model.TeamContact{Email: "orders@example.com"}},
+				"payments": {Name: "Payments", Tags: []string{"pci"}},
+			},
+			Styles: []model.Style{{Select: model.Selector{Tag: "pci"}, BorderColor: "red"}},
+			Components: map[string]model.Component{
+				"web":    {Name: "Web", TeamKey: "web", DependencyKeys: []string{"api", "pay"}},
+				"mobile": {Name: "Mobile", TeamKey: "web", DependencyKeys: []string{"api", "web"}},
+				"api":    {Name: "Api", TeamKey: "orders", DependencyKeys: []string{"db", "pay", "legacy", "missing"}},
+				"db":     {Name: "Db", TeamKey: "orders"},
+				"pay":    {Name: "Pay", TeamKey: "payments"},
+				"legacy": {Name: "Legacy"},
+			},
+		}
+	})
+
+	Describe("Interactions", func() {
+		It("collapses component dependencies into team dependencies", func() {
+			Expect(teammap.Interactions(d)).To(Equal([]teammap.Interaction{
+				{From: "orders", To: "", Dependencies: []teammap.Dependency{{From: "api", To: "legacy"}}},
+				{From: "orders", To: "payments", Dependencies: []teammap.Dependency{{From: "api", To: "pay"}}},
+				{From: "web", To: "orders", Dependencies: []teammap.Dependency{{From: "mobile", To: "api"}, {From: "web", To: "api"}}},
+				{From: "web", To: "payments", Dependencies: []teammap.Dependency{{From: "web", To: "pay"}}},
+			}))
+		})
+	})
+
+	Describe("MakeDot", func() {
+		var (
+			err error
+			dot string
+		)
+
+		JustBeforeEach(func() {
+			dot, err = teammap.MakeDot(d)
+		})
+
+		It("does not error", func() {
+			Expect(err).To(BeNil())
+		})
+		It("draws a node per team with its colors", func() {
+			Expect(dot).To(ContainSubstring(`color="coral",fontcolor="white",label="Web\n2 components"`))
+			Expect(dot).To(ContainSubstring(`label="Payments\n1 component"`))
+		})
+		It("applies the styles of the teams", func() {
+			Expect(dot).To(ContainSubstring(`color="red",fillcolor="",fontcolor="",label="Payments\n1 component"`))
+			Expect(dot).To(ContainSubstring(`label="No team\n1 component"`))
+		})
+		It("links the teams to their contact", func() {
+			Expect(dot).To(ContainSubstring(`URL="mailto:orders@example.com"`))
+			Expect(dot).To(ContainSubstring(`tooltip="orders@example.com"`))
+		})
+		It("weights edges by the number of dependencies", func() {
+			Expect(dot).To(ContainSubstring(`label="2",penwidth="8.0",tooltip="mobile -> api\nweb -> api",weight="2"`))
+			Expect(dot).To(ContainSubstring(`label="1",penwidth="1.0",tooltip="web -> pay",weight="1"`))
+		})
+	})
+
+	Describe("WriteText", func() {
+		It("lists the dependencies behind each interaction", func() {
+			var b bytes.Buffer
+			err := teammap.WriteText(&b, d, teammap.Interactions(d))
+			Expect(err).To(BeNil())
+			Expect(b.String()).To(Equal(`Orders -> No team (1)
+  api -> legacy
+Orders -> Payments (1)
+  api -> pay
+Web -> Orders (2)
+  mobile -> api
+  web -> api
+Web -> Payments (1)
+  web -> pay
+`))
+		})
+		It("says when there are none", func() {
+			var b bytes.Buffer
+			teammap.WriteText(&b, d, []teammap.Interaction{})
+			Expect(b.String()).To(Equal("no dependencies between teams\n"))
+		})
+	})
+})
@@ -0,0 +1,29 @@
+package teammap
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/abramsimon/gomponere/internal/model"
+)
+
+// WriteText writes each team interaction with the component dependencies behind it
+func WriteText(w io.Writer, d model.Diagram, interactions []Interaction) error {
+	if len(interactions) == 0 {
+		_, err := fmt.Fprintln(w, "no dependencies between teams")
+		return err
+	}
+
+	for _, i := range interactions {
+		if _, err := fmt.Fprintf(w, "%s -> %s (%d)\n", TeamName(d, i.From), TeamName(d, i.To), len(i.Dependencies)); err != nil {
+			return err
+		}
+		for _, dep := range i.Dependencies {
+			if _, err := fmt.Fprintf(w, "  %s -> %s\n", dep.From, dep.To); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,232 @@
+package theme
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+)
+
+// the names of the built in themes
+const (
+	Light        = "light"
+	Dark         = "dark"
+	HighContrast = "high-contrast"
+	Print        = "print"
+)
+
+// Theme sets the colors and fonts a diagram is drawn with, anything left empty keeps the graphviz default
+type Theme struct {
+	Name       string `yaml:"name"`
+	Extends    string `yaml:"extends,omitempty"`
+	Background string `yaml:"background,omitempty"`
+	Font       Font   `yaml:"font,omitempty"`
+
+	// Areas are the colors of the areas by how deep they are, with the last used for everything deeper
+	Areas []Box `yaml:"areas,omitempty"`
+
+	// Unowned are the colors of components whose team has no colors, or of every component when TeamColors is false
+	Unowned Box `yaml:"unowned,omitempty"`
+
+	// TeamColors turns the team colors off when false, like for printing in grayscale
+	TeamColors *bool `yaml:"team-colors,omitempty"`
+
+	EdgeColor string `yaml:"edge-color,omitempty"`
+	Legend    Box    `yaml:"legend,omitempty"`
+}
+
+// Font is the font used for all text
+type Font struct {
+	Name  string `yaml:"name,omitempty"`
+	Size  string `yaml:"size,omitempty"`
+	Color string `yaml:"color,omitempty"`
+}
+
+// Box are the colors of something drawn with a border around it
+type Box struct {
+	Fill      string `yaml:"fill,omitempty"`
+	Border    string `yaml:"border,omitempty"`
+	FontColor string `yaml:"font-color,omitempty"`
+	Width     string `yaml:"width,omitempty"`
+}
+
+// Area returns the colors of an area depth areas down from the top, where the top areas are at depth 0
+func (t Theme) Area(depth int) Box {
+	if len(t.Areas) == 0 {
+		return Box{}
+	}
+	if depth >= len(t.Areas) {
+		depth = len(t.Areas) - 1
+	}
+
+	return t.Areas[depth]
+}
+
+// UsesTeamColors returns true unless the theme turns team colors off
+func (t Theme) UsesTeamColors() bool {
+	return t.TeamColors == nil || *t.TeamColors
+}
+
+// clone returns a copy of the theme that shares nothing with it, so changing the copy never changes a built in theme
+func (t Theme) clone() Theme {
+	c := t
+	c.Areas = append([]Box(nil), t.Areas...)
+	if t.TeamColors != nil {
+		teamColors := *t.TeamColors
+		c.TeamColors = &teamColors
+	}
+
+	return c
+}
+
+var off = false
+
+var builtins = map[string]Theme{
+	Light: {
+		Name:       Light,
+		Background: "white",
+		Font:       Font{Name: "Helvetica", Color: "#222222"},
+		Areas: []Box{
+			{Fill: "#f5f7fa", Border: "#c0c8d2", FontColor: "#222222"},
+			{Fill: "#e8edf3", Border: "#aab4c0", FontColor: "#222222"},
+			{Fill: "#dbe3ec", Border: "#94a1b0", FontColor: "#222222"},
+		},
+		Unowned:   Box{Fill: "white", Border: "#666666", FontColor: "#222222"},
+		EdgeColor: "#555555",
+		Legend:    Box{Fill: "white", Border: "#c0c8d2", FontColor: "#222222"},
+	},
+	Dark: {
+		Name:       Dark,
+		Background: "#1e1e1e",
+		Font:       Font{Name: "Helvetica", Color: "#e0e0e0"},
+		Areas: []Box{
+			{Fill: "#2a2d31", Border: "#4a4f57", FontColor: "#e0e0e0"},
+			{Fill: "#33373d", Border: "#5a606a", FontColor: "#e0e0e0"},
+			{Fill: "#3c4148", Border: "#6a717c", FontColor: "#e0e0e0"},
+		},
+		Unowned:   Box{Fill: "#44484f", Border: "#9aa0a6", FontColor: "#e0e0e0"},
+		EdgeColor: "#9aa0a6",
+		Legend:    Box{Fill: "#2a2d31", Border: "#4a4f57", FontColor: "#e0e0e0"},
+	},
+	HighContrast: {
+		Name:       HighContrast,
+		Background: "white",
+		Font:       Font{Name: "Helvetica-Bold", Size: "16", Color: "black"},
+		Areas:      []Box{{Fill: "white", Border: "black", FontColor: "black", Width: "2"}},
+		Unowned:    Box{Fill: "white", Border: "black", FontColor: "black", Width: "2"},
+		EdgeColor:  "black",
+		Legend:     Box{Fill: "white", Border: "black", FontColor: "black", Width: "2"},
+	},
+	Print: {
+		Name:       Print,
+		Background: "white",
+		Font:       Font{Name: "Times-Roman", Color: "black"},
+		Areas: []Box{
+			{Fill: "gray97", Border: "gray40", FontColor: "black"},
+			{Fill: "gray92", Border: "gray40", FontColor: "black"},
+			{Fill: "gray87", Border: "gray40", FontColor: "black"},
+		},
+		Unowned:    Box{Fill: "white", Border: "black", FontColor: "black"},
+		TeamColors: &off,
+		EdgeColor:  "black",
+		Legend:     Box{Fill: "white", Border: "gray40", FontColor: "black"},
+	},
+}
+
+var (
+	themesMu sync.RWMutex
+	themes   = map[string]Theme{}
+)
+
+// Register makes a theme loaded from a file available by its name, replacing one registered before with the same name
+// the built in themes cannot be replaced
+func Register(t Theme) error {
+	if t.Name == "" {
+		return fmt.Errorf("a theme needs a name")
+	}
+	if _, exists := builtins[t.Name]; exists {
+		return fmt.Errorf("theme '%s' is built in", t.Name)
+	}
+
+	themesMu.Lock()
+	defer themesMu.Unlock()
+	themes[t.Name] = t
+
+	return nil
+}
+
+// Lookup returns a copy of the theme with the name, or the empty theme, which keeps every graphviz default, for an
+// empty name
+func Lookup(name string) (Theme, error) {
+	if name == "" {
+		return Theme{}, nil
+	}
+	if t, exists := builtins[name]; exists {
+		return t.clone(), nil
+	}
+
+	themesMu.RLock()
+	defer themesMu.RUnlock()
+	if t, exists := themes[name]; exists {
+		return t.clone(), nil
+	}
+
+	return Theme{}, fmt.Errorf("unknown theme '%s'", name)
+}
+
+// Names returns the names of the built in and registered themes in order
+func Names() []string {
+	themesMu.RLock()
+	defer themesMu.RUnlock()
+
+	names := make([]string, 0, len(builtins)+len(themes))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Load reads a theme file, which is named after the file unless it has a name
+// a theme that extends another starts with everything the other one sets
+func Load(fs afero.Fs, path string) (Theme, error) {
+	b, err := afero.ReadFile(fs, path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Theme{}, fmt.Errorf("theme file '%s' not found", path)
+		}
+		return Theme{}, err
+	}
+
+	t := Theme{}
+	if err := yaml.UnmarshalStrict(b, &t); err != nil {
+		return Theme{}, fmt.Errorf("unable to read '%s': %s", path, err)
+	}
+
+	if t.Extends != "" {
+		base, err := Lookup(t.Extends)
+		if err != nil {
+			return Theme{}, fmt.Errorf("theme '%s' extends %s", path, err)
+		}
+		// base is a copy, so reading the file onto it leaves the theme it extends alone
+		t = base
+		if err := yaml.UnmarshalStrict(b, &t); err != nil {
+			return Theme{}, err
+		}
+	}
+
+	if t.Name == "" || (t.Extends != "" && t.Name == t.Extends) {
+		t.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	return t, nil
+}
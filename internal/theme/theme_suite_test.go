@@ -0,0 +1,13 @@
+package theme_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTheme(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Theme Suite")
+}
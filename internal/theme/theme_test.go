@@ -0,0 +1,122 @@
+package theme_test
+
+import (
+	"github.com/abramsimon/gomponere/internal/theme"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+)
+
+var _ = Describe("Theme", func() {
+	Describe("Lookup", func() {
+		It("finds the built in themes", func() {
+			for _, name := range []string{theme.Light, theme.Dark, theme.HighContrast, theme.Print} {
+				t, err := theme.Lookup(name)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(t.Name).To(Equal(name))
+			}
+		})
+		It("returns the empty theme without a name", func() {
+			Expect(theme.Lookup("")).To(Equal(theme.Theme{}))
+		})
+		It("fails for unknown themes", func() {
+			_, err := theme.Lookup("neon")
+			Expect(err).To(MatchError("unknown theme 'neon'"))
+		})
+	})
+
+	Describe("Register", func() {
+		It("makes the theme available by name", func() {
+			Expect(theme.Register(theme.Theme{Name: "registered", Background: "navy"})).To(Succeed())
+			t, err := theme.Lookup("registered")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t.Background).To(Equal("navy"))
+			Expect(theme.Names()).To(ContainElement("registered"))
+		})
+		It("does not replace the built in themes", func() {
+			Expect(theme.Register(theme.Theme{Name: theme.Dark})).To(MatchError("theme 'dark' is built in"))
+		})
+	})
+
+	Describe("Area", func() {
+		It("uses the last colors for deeper areas", func() {
+			t := theme.Theme{Areas: []theme.Box{{Fill: "a"}, {Fill: "b"}}}
+			Expect(t.Area(0).Fill).To(Equal("a"))
+			Expect(t.Area(5).Fill).To(Equal("b"))
+			Expect(theme.Theme{}.Area(1)).To(Equal(theme.Box{}))
+		})
+	})
+
+	Describe("Load", func() {
+		var (
+			fs  afero.Fs
+			t   theme.Theme
+			err error
+		)
+
+		BeforeEach(func() {
+			fs = afero.NewMemMapFs()
+		})
+
+		JustBeforeEach(func() {
+			t, err = theme.Load(fs, "themes/brand.yaml")
+		})
+
+		Context("with a theme extending a built in one", func() {
+			BeforeEach(func() {
+				afero.WriteFile(fs, "themes/brand.yaml", []byte("extends: dark\nbackground: '#000033'\nteam-colors: false\n"), 0644)
+			})
+
+			It("keeps what it does not set and is named after the file", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(t.Name).To(Equal("brand"))
+				Expect(t.Background).To(Equal("#000033"))
+				Expect(t.EdgeColor).To(Equal("#9aa0a6"))
+				Expect(t.UsesTeamColors()).To(BeFalse())
+			})
+		})
+
+		Context("with a theme turning team colors back on", func() {
+			BeforeEach(func() {
+				afero.WriteFile(fs, "themes/brand.yaml", []byte("extends: print\nteam-colors: true\nareas: [{fill: white}]\n"), 0644)
+			})
+
+			It("does not change the theme it extends", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(t.UsesTeamColors()).To(BeTrue())
+
+				base, err := theme.Lookup(theme.Print)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(base.UsesTeamColors()).To(BeFalse())
+				Expect(base.Areas).To(HaveLen(3))
+				Expect(base.Areas[0].Fill).To(Equal("gray97"))
+			})
+		})
+
+		Context("with a theme extending an unknown one", func() {
+			BeforeEach(func() {
+				afero.WriteFile(fs, "themes/brand.yaml", []byte("extends: neon\n"), 0644)
+			})
+
+			It("fails", func() {
+				Expect(err).To(MatchError("theme 'themes/brand.yaml' extends unknown theme 'neon'"))
+			})
+		})
+
+		Context("with an unknown setting", func() {
+			BeforeEach(func() {
+				afero.WriteFile(fs, "themes/brand.yaml", []byte("name: brand\nbackgroud: black\n"), 0644)
+			})
+
+			It("fails", func() {
+				Expect(err).To(MatchError(ContainSubstring("unable to read 'themes/brand.yaml'")))
+			})
+		})
+
+		Context("without the file", func() {
+			It("fails", func() {
+				Expect(err).To(MatchError("theme file 'themes/brand.yaml' not found"))
+			})
+		})
+	})
+})
@@ -0,0 +1,63 @@
+package gomponere
+
+import (
+	"github.com/abramsimon/gomponere/internal/diagram"
+	"github.com/abramsimon/gomponere/internal/metrics"
+	"github.com/abramsimon/gomponere/internal/teammap"
+)
+
+// RenderOptions allow the nodes and edges of a rendered diagram to be decorated, and set its theme
+type RenderOptions = diagram.Options
+
+// where the legend of the dot and heatmap formats is drawn, chosen with the legend format option
+const (
+	LegendOption = "legend"
+	LegendOn     = diagram.LegendOn
+	LegendOff    = diagram.LegendOff
+	LegendOnly   = diagram.LegendOnly
+)
+
+// AreaURLOption is the format option with the address the areas of the dot and heatmap formats link to, where
+// AreaKeyPlaceholder is replaced by the key of each area
+const AreaURLOption = "area-url"
+
+// AreaKeyPlaceholder is replaced by the key of the area in the area-url option and RenderOptions.AreaURL
+const AreaKeyPlaceholder = diagram.AreaKeyPlaceholder
+
+// the metrics a heat map can be rendered for
+const (
+	Afferent    = metrics.Afferent
+	Efferent    = metrics.Efferent
+	Instability = metrics.Instability
+	Depth       = metrics.Depth
+)
+
+// Render draws the diagram as graphviz dot, with components grouped by area and level
+func Render(d Diagram) (string, error) {
+	return diagram.MakeDot(d)
+}
+
+// RenderWithOptions draws the diagram like Render, calling the options to decorate each node and edge
+func RenderWithOptions(d Diagram, opts RenderOptions) (string, error) {
+	return diagram.MakeDotWithOptions(d, opts)
+}
+
+// RenderTeams draws the teams and how much their components depend on each other as graphviz dot
+func RenderTeams(d Diagram) (string, error) {
+	return teammap.MakeDot(d)
+}
+
+// RenderTeamsWithOptions draws the teams like RenderTeams with the theme of the options
+func RenderTeamsWithOptions(d Diagram, opts RenderOptions) (string, error) {
+	return teammap.MakeDotWithOptions(d, opts)
+}
+
+// RenderHeatMap draws the diagram as graphviz dot with components colored by one of the coupling metrics
+func RenderHeatMap(d Diagram, metric string) (string, error) {
+	return metrics.MakeHeatMap(d, metric)
+}
+
+// RenderHeatMapWithOptions draws the heat map like RenderHeatMap with the theme of the options
+func RenderHeatMapWithOptions(d Diagram, metric string, opts RenderOptions) (string, error) {
+	return metrics.MakeHeatMapWithOptions(d, metric, opts)
+}
@@ -0,0 +1,159 @@
+package gomponere
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Renderer draws a diagram in one output format
+type Renderer interface {
+	// Render returns the diagram in the renderer's format along with the media type of that format
+	Render(d Diagram, opts FormatOptions) ([]byte, string, error)
+}
+
+// RendererFunc lets an ordinary function be used as a Renderer
+type RendererFunc func(d Diagram, opts FormatOptions) ([]byte, string, error)
+
+// Render calls f
+func (f RendererFunc) Render(d Diagram, opts FormatOptions) ([]byte, string, error) {
+	return f(d, opts)
+}
+
+// FormatOptions are the settings passed to a renderer by name, like "metric" for a heat map
+// renderers ignore the options they do not know
+type FormatOptions map[string]string
+
+// the media types of the built in formats
+const (
+	DotContentType  = "text/vnd.graphviz"
+	JSONContentType = "application/json"
+	YAMLContentType = "application/yaml"
+)
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[string]Renderer{}
+)
+
+func init() {
+	Register("dot", RendererFunc(func(d Diagram, opts FormatOptions) ([]byte, string, error) {
+		ro, err := renderOptions(opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return dotBytes(RenderWithOptions(d, ro))
+	}))
+	Register("teams", RendererFunc(func(d Diagram, opts FormatOptions) ([]byte, string, error) {
+		ro, err := renderOptions(opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return dotBytes(RenderTeamsWithOptions(d, ro))
+	}))
+	Register("heatmap", RendererFunc(func(d Diagram, opts FormatOptions) ([]byte, string, error) {
+		ro, err := renderOptions(opts)
+		if err != nil {
+			return nil, "", err
+		}
+		metric := opts["metric"]
+		if metric == "" {
+			metric = Instability
+		}
+		return dotBytes(RenderHeatMapWithOptions(d, metric, ro))
+	}))
+	Register("json", RendererFunc(func(d Diagram, _ FormatOptions) ([]byte, string, error) {
+		b, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return nil, "", err
+		}
+		return append(b, '\n'), JSONContentType, nil
+	}))
+	Register("yaml", RendererFunc(func(d Diagram, _ FormatOptions) ([]byte, string, error) {
+		b, err := Marshal(d)
+		if err != nil {
+			return nil, "", err
+		}
+		return b, YAMLContentType, nil
+	}))
+}
+
+// Register makes a renderer available under the format name, usually from the init function of the package that
+// provides it
+// it panics when the name is already taken, so two packages cannot silently replace each other's formats
+func Register(name string, r Renderer) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+
+	if r == nil {
+		panic("gomponere: renderer for format '" + name + "' is nil")
+	}
+	if _, exists := renderers[name]; exists {
+		panic("gomponere: format '" + name + "' is registered twice")
+	}
+
+	renderers[name] = r
+}
+
+// Lookup returns the renderer registered for the format
+func Lookup(name string) (Renderer, error) {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+
+	r, exists := renderers[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown format '%s'", name)
+	}
+
+	return r, nil
+}
+
+// Formats returns the names of every registered format in order
+func Formats() []string {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// RenderFormat draws the diagram with the renderer registered for the format
+func RenderFormat(d Diagram, format string, opts FormatOptions) ([]byte, string, error) {
+	r, err := Lookup(format)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return r.Render(d, opts)
+}
+
+func dotBytes(dot string, err error) ([]byte, string, error) {
+	if err != nil {
+		return nil, "", err
+	}
+
+	return []byte(dot), DotContentType, nil
+}
+
+// renderOptions returns the render options for the theme and legend chosen in the format options
+func renderOptions(opts FormatOptions) (RenderOptions, error) {
+	t, err := LookupTheme(opts[ThemeOption])
+	if err != nil {
+		return RenderOptions{}, err
+	}
+
+	legend := opts[LegendOption]
+	switch legend {
+	case "", LegendOn, LegendOff, LegendOnly:
+	default:
+		return RenderOptions{}, fmt.Errorf("unknown legend '%s'", legend)
+	}
+
+	return RenderOptions{Theme: t, Legend: legend, AreaURL: opts[AreaURLOption]}, nil
+}
@@ -0,0 +1,97 @@
+package gomponere_test
+
+import (
+	"strings"
+
+	"github.com/abramsimon/gomponere"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// names lists the component names, one per line, and is registered once for the whole suite
+var names = gomponere.RendererFunc(func(d gomponere.Diagram, opts gomponere.FormatOptions) ([]byte, string, error) {
+	list := []string{}
+	for _, k := range d.ComponentKeys() {
+		list = append(list, opts["prefix"]+d.Components[k].Name)
+	}
+	return []byte(strings.Join(list, "\n")), "text/plain", nil
+})
+
+func init() {
+	gomponere.Register("names", names)
+}
+
+var _ = Describe("Renderer", func() {
+	var (
+		d gomponere.Diagram
+	)
+
+	BeforeEach(func() {
+		var err error
+		d, err = gomponere.NewBuilder().
+			Area("prod", "Production").
+			Component("web", "Web").InArea("prod").DependsOn("api").
+			Component("api", "Api").InArea("prod").
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("Formats", func() {
+		It("lists the built in and registered formats", func() {
+			Expect(gomponere.Formats()).To(Equal([]string{"dot", "heatmap", "json", "names", "teams", "yaml"}))
+		})
+	})
+
+	Describe("Register", func() {
+		It("panics when a format is registered twice", func() {
+			Expect(func() { gomponere.Register("dot", names) }).To(Panic())
+		})
+		It("panics without a renderer", func() {
+			Expect(func() { gomponere.Register("nothing", nil) }).To(Panic())
+		})
+	})
+
+	Describe("RenderFormat", func() {
+		It("uses the registered renderer with the options", func() {
+			b, contentType, err := gomponere.RenderFormat(d, "names", gomponere.FormatOptions{"prefix": "- "})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(contentType).To(Equal("text/plain"))
+			Expect(string(b)).To(Equal("- Api\n- Web"))
+		})
+		It("renders the built in formats", func() {
+			for _, format := range []string{"dot", "heatmap", "json", "teams", "yaml"} {
+				b, _, err := gomponere.RenderFormat(d, format, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(b).NotTo(BeEmpty())
+			}
+		})
+		It("renders the dot formats with a theme", func() {
+			for _, format := range []string{"dot", "heatmap", "teams"} {
+				b, _, err := gomponere.RenderFormat(d, format, gomponere.FormatOptions{gomponere.ThemeOption: gomponere.DarkTheme})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(b)).To(ContainSubstring(`bgcolor="#1e1e1e"`))
+			}
+			_, _, err := gomponere.RenderFormat(d, "dot", gomponere.FormatOptions{gomponere.ThemeOption: "neon"})
+			Expect(err).To(MatchError("unknown theme 'neon'"))
+		})
+		It("renders the legend where it is asked for", func() {
+			b, _, err := gomponere.RenderFormat(d, "dot", gomponere.FormatOptions{gomponere.LegendOption: gomponere.LegendOnly})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(b)).To(ContainSubstring(`label="Legend"`))
+			Expect(string(b)).NotTo(ContainSubstring(`label="Web"`))
+			_, _, err = gomponere.RenderFormat(d, "dot", gomponere.FormatOptions{gomponere.LegendOption: "top"})
+			Expect(err).To(MatchError("unknown legend 'top'"))
+		})
+		It("links the areas to their pages", func() {
+			for _, format := range []string{"dot", "heatmap"} {
+				b, _, err := gomponere.RenderFormat(d, format, gomponere.FormatOptions{gomponere.AreaURLOption: "https://arch.example.com/{key}"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(b)).To(ContainSubstring(`URL="https://arch.example.com/prod"`))
+			}
+		})
+		It("fails for unknown formats", func() {
+			_, _, err := gomponere.RenderFormat(d, "png", nil)
+			Expect(err).To(MatchError("unknown format 'png'"))
+		})
+	})
+})
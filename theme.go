@@ -0,0 +1,40 @@
+package gomponere
+
+import (
+	"github.com/abramsimon/gomponere/internal/theme"
+	"github.com/spf13/afero"
+)
+
+// Theme sets the colors and fonts a diagram is rendered with
+type Theme = theme.Theme
+
+// the built in themes
+const (
+	LightTheme        = theme.Light
+	DarkTheme         = theme.Dark
+	HighContrastTheme = theme.HighContrast
+	PrintTheme        = theme.Print
+)
+
+// ThemeOption is the format option choosing the theme of the dot, teams and heatmap formats
+const ThemeOption = "theme"
+
+// LoadTheme reads a theme file
+func LoadTheme(fs afero.Fs, path string) (Theme, error) {
+	return theme.Load(fs, path)
+}
+
+// RegisterTheme makes a theme available to the formats by its name
+func RegisterTheme(t Theme) error {
+	return theme.Register(t)
+}
+
+// LookupTheme returns the built in or registered theme with the name
+func LookupTheme(name string) (Theme, error) {
+	return theme.Lookup(name)
+}
+
+// Themes returns the names of the built in and registered themes in order
+func Themes() []string {
+	return theme.Names()
+}